@@ -0,0 +1,237 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/servertest"
+)
+
+// createGameSessionRequest - body of POST /processes/{processId}/game-session.
+type createGameSessionRequest struct {
+	GameSessionID             string            `json:"GameSessionId"`
+	Name                      string            `json:"Name"`
+	MaximumPlayerSessionCount int               `json:"MaximumPlayerSessionCount"`
+	GameProperties            map[string]string `json:"GameProperties"`
+	GameSessionData           string            `json:"GameSessionData"`
+	// MatchmakerData - either a JSON string or a JSON object, which is passed to the game server as a string.
+	MatchmakerData json.RawMessage `json:"MatchmakerData"`
+}
+
+// createPlayerSessionRequest - body of POST /processes/{processId}/game-session/player-sessions.
+type createPlayerSessionRequest struct {
+	PlayerID   string `json:"PlayerId"`
+	PlayerData string `json:"PlayerData"`
+}
+
+// terminateRequest - body of POST /processes/{processId}/terminate.
+// TerminationTime is an RFC 3339 time and In a duration such as "5m" from now. Without either,
+// the termination time is now.
+type terminateRequest struct {
+	TerminationTime time.Time `json:"TerminationTime"`
+	In              string    `json:"In,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"Error"`
+}
+
+type api struct {
+	service *servertest.Service
+}
+
+// newAPI - returns the handler of the REST API that drives the simulator.
+func newAPI(service *servertest.Service) http.Handler {
+	a := &api{service: service}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /processes", a.listProcesses)
+	mux.HandleFunc("GET /processes/{processId}", a.getProcess)
+	mux.HandleFunc("POST /processes/{processId}/game-session", a.createGameSession)
+	mux.HandleFunc("GET /processes/{processId}/game-session", a.getGameSession)
+	mux.HandleFunc("POST /processes/{processId}/game-session/player-sessions", a.createPlayerSession)
+	mux.HandleFunc("GET /processes/{processId}/game-session/player-sessions", a.listPlayerSessions)
+	mux.HandleFunc("POST /processes/{processId}/refresh-connection", a.refreshConnection)
+	mux.HandleFunc("POST /processes/{processId}/terminate", a.terminate)
+	mux.HandleFunc("GET /backfill-tickets", a.listBackfillTickets)
+	return mux
+}
+
+func (a *api) listProcesses(w http.ResponseWriter, _ *http.Request) {
+	writeResponse(w, http.StatusOK, a.service.Processes())
+}
+
+func (a *api) getProcess(w http.ResponseWriter, r *http.Request) {
+	process, ok := a.service.Process(r.PathValue("processId"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("process %s never connected", r.PathValue("processId")))
+		return
+	}
+	writeResponse(w, http.StatusOK, process)
+}
+
+func (a *api) createGameSession(w http.ResponseWriter, r *http.Request) {
+	var req createGameSessionRequest
+	if err := readRequest(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	matchmakerData, err := matchmakerDataString(req.MatchmakerData)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	session, err := a.service.CreateGameSession(r.PathValue("processId"), model.GameSession{
+		GameSessionID:             req.GameSessionID,
+		Name:                      req.Name,
+		MaximumPlayerSessionCount: req.MaximumPlayerSessionCount,
+		GameProperties:            req.GameProperties,
+		GameSessionData:           req.GameSessionData,
+		MatchmakerData:            matchmakerData,
+	})
+	if err != nil {
+		writeError(w, statusCodeFor(err), err)
+		return
+	}
+	writeResponse(w, http.StatusCreated, session)
+}
+
+func (a *api) getGameSession(w http.ResponseWriter, r *http.Request) {
+	gameSessionID, ok := a.gameSessionID(w, r)
+	if !ok {
+		return
+	}
+	session, _ := a.service.GameSession(gameSessionID)
+	writeResponse(w, http.StatusOK, session)
+}
+
+func (a *api) createPlayerSession(w http.ResponseWriter, r *http.Request) {
+	gameSessionID, ok := a.gameSessionID(w, r)
+	if !ok {
+		return
+	}
+	var req createPlayerSessionRequest
+	if err := readRequest(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.PlayerID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("PlayerId is required"))
+		return
+	}
+	session, err := a.service.CreatePlayerSession(gameSessionID, req.PlayerID, req.PlayerData)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeResponse(w, http.StatusCreated, session)
+}
+
+func (a *api) listPlayerSessions(w http.ResponseWriter, r *http.Request) {
+	gameSessionID, ok := a.gameSessionID(w, r)
+	if !ok {
+		return
+	}
+	writeResponse(w, http.StatusOK, a.service.PlayerSessions(gameSessionID))
+}
+
+func (a *api) refreshConnection(w http.ResponseWriter, r *http.Request) {
+	if err := a.service.RefreshConnection(r.PathValue("processId")); err != nil {
+		writeError(w, statusCodeFor(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (a *api) terminate(w http.ResponseWriter, r *http.Request) {
+	var req terminateRequest
+	if err := readRequest(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	terminationTime := req.TerminationTime
+	if terminationTime.IsZero() {
+		terminationTime = time.Now()
+		if req.In != "" {
+			in, err := time.ParseDuration(req.In)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid In: %w", err))
+				return
+			}
+			terminationTime = terminationTime.Add(in)
+		}
+	}
+	if err := a.service.TerminateProcess(r.PathValue("processId"), terminationTime); err != nil {
+		writeError(w, statusCodeFor(err), err)
+		return
+	}
+	writeResponse(w, http.StatusAccepted, terminateRequest{TerminationTime: terminationTime})
+}
+
+func (a *api) listBackfillTickets(w http.ResponseWriter, _ *http.Request) {
+	writeResponse(w, http.StatusOK, a.service.BackfillTickets())
+}
+
+// gameSessionID - returns the game session hosted by the process of the request, or writes a 404 response.
+func (a *api) gameSessionID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	processID := r.PathValue("processId")
+	process, ok := a.service.Process(processID)
+	if !ok || process.GameSessionID == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("process %s does not host a game session", processID))
+		return "", false
+	}
+	return process.GameSessionID, true
+}
+
+// matchmakerDataString - accepts MatchmakerData either as a JSON string or as an inline JSON document.
+func matchmakerDataString(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	if !json.Valid(raw) {
+		return "", errors.New("MatchmakerData is not valid JSON")
+	}
+	return string(raw), nil
+}
+
+func statusCodeFor(err error) int {
+	if errors.Is(err, servertest.ErrProcessNotConnected) {
+		return http.StatusNotFound
+	}
+	return http.StatusConflict
+}
+
+// readRequest - decodes the JSON body of the request. An empty body leaves v unchanged.
+func readRequest(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func writeResponse(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeResponse(w, statusCode, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/servertest"
+)
+
+func setupAPI(t *testing.T) (http.Handler, *servertest.Service) {
+	t.Helper()
+	service, err := servertest.NewService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+	return newAPI(service), service
+}
+
+func doRequest(handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder
+}
+
+func TestAPI_GameSessionLifecycle(t *testing.T) {
+	// GIVEN
+	handler, service := setupAPI(t)
+	client, err := server.NewClient(service.ServerParameters("process-1"), server.WithLogger(stdLogger{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Destroy()
+	gameSessions := make(chan model.GameSession, 1)
+	terminations := make(chan struct{}, 1)
+	err = client.ProcessReady(server.ProcessParameters{
+		OnStartGameSession: func(session model.GameSession) { gameSessions <- session },
+		OnProcessTerminate: func() { terminations <- struct{}{} },
+		OnHealthCheck:      func() bool { return true },
+		Port:               7777,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	created := doRequest(handler, http.MethodPost, "/processes/process-1/game-session",
+		`{"MaximumPlayerSessionCount": 2, "GameProperties": {"mode": "duel"}, "MatchmakerData": {"matchId": "m-1"}}`)
+	var session model.GameSession
+	select {
+	case session = <-gameSessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for OnStartGameSession")
+	}
+	if err := client.ActivateGameSession(); err != nil {
+		t.Fatal(err)
+	}
+	reserved := doRequest(handler, http.MethodPost, "/processes/process-1/game-session/player-sessions",
+		`{"PlayerId": "player-1"}`)
+	terminated := doRequest(handler, http.MethodPost, "/processes/process-1/terminate", `{"In": "1m"}`)
+	select {
+	case <-terminations:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for OnProcessTerminate")
+	}
+
+	// THEN
+	common.AssertEqual(t, http.StatusCreated, created.Code)
+	common.AssertEqual(t, "duel", session.GameProperties["mode"])
+	common.AssertEqual(t, `{"matchId": "m-1"}`, session.MatchmakerData)
+	common.AssertEqual(t, http.StatusCreated, reserved.Code)
+	var playerSession model.PlayerSession
+	if err := json.Unmarshal(reserved.Body.Bytes(), &playerSession); err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, "player-1", playerSession.PlayerID)
+	common.AssertEqual(t, http.StatusAccepted, terminated.Code)
+	terminationTime, err := client.GetTerminationTime()
+	common.AssertEqual(t, nil, err)
+	if remaining := time.Until(time.Unix(terminationTime, 0)); remaining < 50*time.Second || remaining > time.Minute {
+		t.Fatalf("Expected termination in about a minute but got %s", remaining)
+	}
+}
+
+func TestAPI_ReturnError(t *testing.T) {
+	// GIVEN
+	handler, _ := setupAPI(t)
+	tests := map[string]struct {
+		method, path, body string
+		statusCode         int
+	}{
+		"unknown process": {http.MethodGet, "/processes/process-1", "", http.StatusNotFound},
+		"process not ready": {http.MethodPost, "/processes/process-1/game-session",
+			`{"MaximumPlayerSessionCount": 2}`, http.StatusConflict},
+		"invalid matchmaker data": {http.MethodPost, "/processes/process-1/game-session",
+			`{"MatchmakerData": 1`, http.StatusBadRequest},
+		"unknown field": {http.MethodPost, "/processes/process-1/game-session",
+			`{"MaxPlayers": 2}`, http.StatusBadRequest},
+		"no game session": {http.MethodPost, "/processes/process-1/game-session/player-sessions",
+			`{"PlayerId": "player-1"}`, http.StatusNotFound},
+		"invalid duration": {http.MethodPost, "/processes/process-1/terminate", `{"In": "soon"}`, http.StatusBadRequest},
+		"not connected":    {http.MethodPost, "/processes/process-1/terminate", "", http.StatusNotFound},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// WHEN
+			recorder := doRequest(handler, test.method, test.path, test.body)
+
+			// THEN
+			common.AssertEqual(t, test.statusCode, recorder.Code)
+			common.AssertContains(t, recorder.Body.String(), `"Error"`)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Command gamelift-local hosts a local Amazon GameLift Servers endpoint for manual testing of game servers.
+//
+// Game servers connect to the websocket endpoint by setting ServerParameters.WebSocketURL to the printed URL,
+// together with any ProcessID, HostID, FleetID and AuthToken. The lifecycle of the connected server processes
+// is then driven through a small REST API:
+//
+//	GET  /processes                                         list connected server processes
+//	GET  /processes/{processId}                             describe a server process
+//	POST /processes/{processId}/game-session                create a game session on the process
+//	GET  /processes/{processId}/game-session                describe the game session of the process
+//	POST /processes/{processId}/game-session/player-sessions  reserve a player session
+//	GET  /processes/{processId}/game-session/player-sessions  list the player sessions
+//	POST /processes/{processId}/refresh-connection          ask the process to refresh its connection
+//	POST /processes/{processId}/terminate                   send TerminateProcess
+//	GET  /backfill-tickets                                  list received match backfill requests
+//
+// For example:
+//
+//	gamelift-local -ws-addr 127.0.0.1:8080 -api-addr 127.0.0.1:8081
+//	curl -X POST localhost:8081/processes/process-1/game-session \
+//		-d '{"MaximumPlayerSessionCount": 4, "GameProperties": {"mode": "duel"}}'
+//	curl -X POST localhost:8081/processes/process-1/game-session/player-sessions -d '{"PlayerId": "player-1"}'
+//	curl -X POST localhost:8081/processes/process-1/terminate -d '{"In": "2m"}'
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/servertest"
+)
+
+// stdLogger - writes the log messages of the simulator to the standard logger.
+type stdLogger struct {
+	verbose bool
+}
+
+func (l stdLogger) Debugf(format string, args ...any) {
+	if l.verbose {
+		log.Printf("[DEBUG]:"+format, args...)
+	}
+}
+
+func (l stdLogger) Warnf(format string, args ...any) {
+	log.Printf("[WARN]:"+format, args...)
+}
+
+func (l stdLogger) Errorf(format string, args ...any) {
+	log.Printf("[ERROR]:"+format, args...)
+}
+
+func main() {
+	wsAddr := flag.String("ws-addr", "127.0.0.1:8080", "address of the websocket endpoint the game servers connect to")
+	apiAddr := flag.String("api-addr", "127.0.0.1:8081", "address of the REST API that drives the simulator")
+	reservationTimeout := flag.Duration("reservation-timeout", servertest.DefaultReservationTimeout,
+		"time a reserved player session waits to be accepted before it times out")
+	verbose := flag.Bool("v", false, "log every request received from the game servers")
+	flag.Parse()
+
+	if err := run(*wsAddr, *apiAddr, *reservationTimeout, stdLogger{verbose: *verbose}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(wsAddr, apiAddr string, reservationTimeout time.Duration, logger stdLogger) error {
+	service, err := servertest.Start(wsAddr,
+		servertest.WithLogger(logger),
+		servertest.WithReservationTimeout(reservationTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start websocket endpoint: %w", err)
+	}
+	defer service.Close()
+
+	listener, err := net.Listen("tcp", apiAddr)
+	if err != nil {
+		return fmt.Errorf("failed to start REST API: %w", err)
+	}
+	apiServer := &http.Server{Handler: newAPI(service), ReadHeaderTimeout: 10 * time.Second}
+
+	log.Printf("WebSocketURL: %s", service.URL)
+	log.Printf("REST API: http://%s", listener.Addr())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = apiServer.Close()
+	}()
+
+	if err := apiServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
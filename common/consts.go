@@ -13,16 +13,37 @@ const (
 	ServiceCallTimeoutDefault         time.Duration = 20 * time.Second
 	MaxRetryDefault                                 = 5
 	RetryFactorDefault                              = 2
-	MaxReconnectBackoffDuration                     = 32 * time.Second
+	ReconnectMaxDelayDefault                        = 32 * time.Second
 	RetryIntervalDefault                            = 2 * time.Second
-	ConnectMaxRetries                               = 7
-	ConnectRetryInterval                            = 2 * time.Second
+	ReconnectMaxAttemptsDefault                     = 8
+	ReconnectBaseDelayDefault                       = 8 * time.Second
+	// Deprecated: use ReconnectMaxDelayDefault.
+	MaxReconnectBackoffDuration = ReconnectMaxDelayDefault
+	// Deprecated: use ReconnectMaxAttemptsDefault, which counts the first attempt as well.
+	ConnectMaxRetries = ReconnectMaxAttemptsDefault - 1
+	// Deprecated: use ReconnectBaseDelayDefault. The first retry used to wait ConnectRetryInterval doubled twice.
+	ConnectRetryInterval = ReconnectBaseDelayDefault / 4
 	ServiceBufferSizeDefault                        = 2048
 	HealthcheckIntervalDefault                      = 60 * time.Second
 	HealthcheckRetryIntervalDefault                 = 10 * time.Second
 	HealthcheckMaxJitterDefault                     = 10 * time.Second
 	HealthcheckTimeoutDefault                       = HealthcheckIntervalDefault - HealthcheckRetryIntervalDefault
 	DisconnectWebsocketTimeoutDefault               = 5 * time.Second
+	WebsocketPingIntervalDefault                    = 0 // keepalive disabled unless WEBSOCKET_PING_INTERVAL is set
+	WebsocketPongTimeoutDefault                     = 10 * time.Second
+	WriteQueueSizeDefault                           = 256
+	RequestBufferSizeDefault                        = 64
+	// DrainTimeoutDefault Amazon GameLift Servers waits five minutes for ProcessEnding after a terminate process signal
+	DrainTimeoutDefault      = 5 * time.Minute
+	DrainSafetyMarginDefault = 10 * time.Second
+	DrainPollIntervalDefault = 1 * time.Second
+	// ReconciliationIntervalDefault interval between player session reconciliations
+	ReconciliationIntervalDefault = 60 * time.Second
+	// PlayerSessionPolicyPollIntervalDefault interval between counts of RESERVED player sessions
+	PlayerSessionPolicyPollIntervalDefault = 10 * time.Second
+	// BackfillRetryDelayDefault time to wait before the first retry of a failed match backfill request
+	BackfillRetryDelayDefault    = 5 * time.Second
+	BackfillRetryMaxDelayDefault = 1 * time.Minute
 	// InstanceRoleCredentialTTL duration of expiration we retrieve new instance role credentials
 	InstanceRoleCredentialTTL     = 15 * time.Minute
 	RoleSessionNameMaxLength  int = 64
@@ -79,6 +100,14 @@ const (
 	HealthcheckInterval        = "HEALTHCHECK_INTERVAL"
 	HealthcheckTimeout         = "HEALTHCHECK_TIMEOUT"
 	DisconnectWebsocketTimeout = "DISCONNECT_WEBSOCKET_TIMEOUT"
+	// WebsocketPingInterval interval between keepalive pings, the keepalive is disabled when unset or 0
+	WebsocketPingInterval = "WEBSOCKET_PING_INTERVAL"
+	// WebsocketPongTimeout time allowed for a pong after the ping interval before the connection is reconnected
+	WebsocketPongTimeout = "WEBSOCKET_PONG_TIMEOUT"
+	// WriteQueueSize number of requests waiting to be written before new requests are rejected
+	WriteQueueSize = "WRITE_QUEUE_SIZE"
+	// RequestBufferSize number of requests held back while the websocket connection is re-established
+	RequestBufferSize = "REQUEST_BUFFER_SIZE"
 )
 
 const (
@@ -96,6 +125,8 @@ const (
 	EnvironmentKeySDKToolName    string = "GAMELIFT_SDK_TOOL_NAME"
 	EnvironmentKeySDKToolVersion string = "GAMELIFT_SDK_TOOL_VERSION"
 	EnvironmentKeyDiscoveryEndpoint string = "GAMELIFT_CONTAINER_DISCOVERY_SERVER_ENDPOINT"
+	// EnvironmentKeyTrafficRecordFile - path of a file the websocket traffic is recorded to, for troubleshooting.
+	EnvironmentKeyTrafficRecordFile string = "GAMELIFT_SDK_TRAFFIC_RECORD_FILE"
 
 	// Metrics environment variables
 	EnvironmentKeyStatsdHost        string = "GAMELIFT_STATSD_HOST"
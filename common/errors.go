@@ -91,6 +91,10 @@ const (
 	MetricUnsupportedTypeException
 	// UnsupportedComputeTypeException - Operation not supported on this compute type.
 	UnsupportedComputeTypeException
+	// IllegalStateTransition - The call is not allowed in the current lifecycle state of the server process.
+	IllegalStateTransition
+	// WebsocketRequestInterrupted - The connection a request was sent on was lost before its response was received.
+	WebsocketRequestInterrupted
 )
 
 type errorDescription struct {
@@ -260,12 +264,21 @@ var errorMessages = map[GameLiftErrorType]errorDescription{
 		name:    "Unsupported compute type exception.",
 		message: "This operation is not supported on the current compute type.",
 	},
+	IllegalStateTransition: {
+		name:    "Illegal state transition.",
+		message: "The call is not allowed in the current lifecycle state of the server process.",
+	},
+	WebsocketRequestInterrupted: {
+		name:    "WebSocket request interrupted",
+		message: "The connection was lost before a response was received. The request may have been processed.",
+	},
 }
 
 // GameLiftError - Represents an error in a call to the server SDK for Amazon GameLift Servers.
 type GameLiftError struct {
 	ErrorType GameLiftErrorType
 	errorDescription
+	cause error
 }
 
 // NewGameLiftError - creates a new GameLiftError.
@@ -284,6 +297,27 @@ func NewGameLiftError(errorType GameLiftErrorType, name, message string) error {
 	}
 }
 
+// WrapGameLiftError - creates a new GameLiftError caused by the specified error.
+// The cause is used as the error message and can be retrieved with errors.Is and errors.As.
+//
+// Example:
+//
+//	err := common.WrapGameLiftError(common.ServiceCallFailed, ctx.Err())
+//	errors.Is(err, context.Canceled) // true if ctx was cancelled
+func WrapGameLiftError(errorType GameLiftErrorType, cause error) error {
+	var message string
+	if cause != nil {
+		message = cause.Error()
+	}
+	return &GameLiftError{
+		ErrorType: errorType,
+		errorDescription: errorDescription{
+			message: message,
+		},
+		cause: cause,
+	}
+}
+
 // NewGameLiftErrorFromStatusCode - convert statusCode and errorMessage to the GameLiftError.
 func NewGameLiftErrorFromStatusCode(statusCode int, errorMessage string) error {
 	return NewGameLiftError(getErrorTypeForStatusCode(statusCode), "", errorMessage)
@@ -297,6 +331,11 @@ func (e *GameLiftError) Error() string {
 	)
 }
 
+// Unwrap - returns the error that caused this GameLiftError, if any.
+func (e *GameLiftError) Unwrap() error {
+	return e.cause
+}
+
 func (e *GameLiftError) getMessageOrDefaultForErrorType() string {
 	if e.message != "" {
 		return e.message
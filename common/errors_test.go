@@ -6,6 +6,8 @@
 package common
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -54,3 +56,24 @@ func TestNewGameLiftError(t *testing.T) {
 
 	}
 }
+
+func TestWrapGameLiftError(t *testing.T) {
+	err := WrapGameLiftError(ServiceCallFailed, context.DeadlineExceeded)
+
+	var gameLiftErr *GameLiftError
+	if !errors.As(err, &gameLiftErr) {
+		t.Fatal("Incorrect error type from the function WrapGameLiftError")
+	}
+	if gameLiftErr.ErrorType != ServiceCallFailed {
+		t.Fatalf("Incorrect error type, expect: \"%d\", but get: \"%d\"", ServiceCallFailed, gameLiftErr.ErrorType)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("Wrapped error should match its cause")
+	}
+	if gameLiftErr.getMessageOrDefaultForErrorType() != context.DeadlineExceeded.Error() {
+		t.Fatalf("Incorrect error message, expect: \"%s\", but get: \"%s\"",
+			context.DeadlineExceeded.Error(),
+			gameLiftErr.getMessageOrDefaultForErrorType(),
+		)
+	}
+}
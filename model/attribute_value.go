@@ -7,6 +7,7 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -83,6 +84,10 @@ type AttributeValueN struct {
 
 func (a AttributeValue) MarshalJSON() ([]byte, error) {
 	type localAttributeValue AttributeValue
+	if a.AttrType == nil {
+		attrType := None
+		a.AttrType = &attrType
+	}
 	switch *a.AttrType {
 	case Double:
 		attributeValueN := AttributeValueN{a.AttrType, a.N}
@@ -91,6 +96,49 @@ func (a AttributeValue) MarshalJSON() ([]byte, error) {
 	return json.Marshal(localAttributeValue(a))
 }
 
+// UnmarshalJSON - parses an AttributeValue in the form written by MarshalJSON.
+// An unknown AttrType is parsed as NONE; use UnmarshalAttributeValueStrict to reject it instead.
+func (a *AttributeValue) UnmarshalJSON(data []byte) error {
+	return a.unmarshal(data, false)
+}
+
+// UnmarshalAttributeValueStrict - same as AttributeValue.UnmarshalJSON, but returns an error for an unknown AttrType.
+func UnmarshalAttributeValueStrict(data []byte) (AttributeValue, error) {
+	var a AttributeValue
+	err := a.unmarshal(data, true)
+	return a, err
+}
+
+func (a *AttributeValue) unmarshal(data []byte, strict bool) error {
+	var raw struct {
+		AttrType *string            `json:"AttrType"`
+		N        float64            `json:"N"`
+		S        string             `json:"S"`
+		SL       []string           `json:"SL"`
+		SDM      map[string]float64 `json:"SDM"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*a = AttributeValue{N: raw.N, S: raw.S, SL: raw.SL, SDM: raw.SDM}
+	if raw.AttrType == nil {
+		return nil
+	}
+	attrType := None
+	known := false
+	for i := range attributeTypesStr {
+		if strings.EqualFold(attributeTypesStr[i], *raw.AttrType) {
+			attrType, known = attributeType(i), true
+			break
+		}
+	}
+	if !known && strict {
+		return fmt.Errorf("unknown attribute type %q", *raw.AttrType)
+	}
+	a.AttrType = &attrType
+	return nil
+}
+
 // GetAttrType - return current attributeType.
 //
 //nolint:revive // Return an unexposed type is enough to use a predefined values [ String, Double, StringList, StringDoubleMap ]
@@ -144,3 +144,39 @@ func getNumFieldsInMarshaledData(marshaledString string) int {
 	}
 	return count
 }
+
+func TestAttributeValue_RoundTrip(t *testing.T) {
+	cases := []AttributeValue{
+		MakeAttributeValue("Deathmatch"),
+		MakeAttributeValue(float64(0)),
+		MakeAttributeValue([]string{"a", "b"}),
+		MakeAttributeValue(map[string]float64{"a": 1}),
+		MakeAttributeValue(nil),
+	}
+
+	for _, expected := range cases {
+		data, err := json.Marshal(expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := UnmarshalAttributeValueStrict(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("expect %v but get %v from %s", expected, got, data)
+		}
+	}
+}
+
+func TestUnmarshalAttributeValueStrict_UnknownType_ReturnError(t *testing.T) {
+	data := []byte(`{"AttrType":"OBJECT","S":"value"}`)
+
+	if _, err := UnmarshalAttributeValueStrict(data); err == nil {
+		t.Error("expect error for unknown attribute type")
+	}
+	var lenient AttributeValue
+	if err := json.Unmarshal(data, &lenient); err != nil || lenient.GetAttrType() != None {
+		t.Errorf("expect NONE attribute type but get %v, %v", lenient, err)
+	}
+}
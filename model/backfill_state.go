@@ -0,0 +1,61 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import "strconv"
+
+// BackfillState - state of the match backfill tracked by server.BackfillManager.
+type BackfillState int
+
+// Possible BackfillState values
+const (
+	// BackfillIdle - no match backfill request is active.
+	BackfillIdle BackfillState = iota
+	// BackfillActive - a match backfill request was started and its outcome has not been received yet.
+	BackfillActive
+	// BackfillRetrying - the last match backfill request failed or timed out and is about to be retried.
+	BackfillRetrying
+	// BackfillGaveUp - the match backfill request failed and all retries were used.
+	BackfillGaveUp
+)
+
+var backfillStateStrs = []string{
+	"IDLE",
+	"ACTIVE",
+	"RETRYING",
+	"GAVE_UP",
+}
+
+func (b *BackfillState) String() string {
+	n := int(*b)
+	if n < 0 || n >= len(backfillStateStrs) {
+		n = 0
+	}
+	return backfillStateStrs[n]
+}
+
+func (b *BackfillState) ToBackfillState(s string) {
+	for i := range backfillStateStrs {
+		if backfillStateStrs[i] == s {
+			*b = BackfillState(i)
+			return
+		}
+	}
+	*b = BackfillIdle
+}
+
+func (b *BackfillState) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(b.String())), nil
+}
+
+func (b *BackfillState) UnmarshalJSON(data []byte) error {
+	origin, err := strconv.Unquote(string(data))
+	if err != nil {
+		return err
+	}
+	b.ToBackfillState(origin)
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// EventType - kind of an Event reported by the server SDK.
+type EventType int
+
+// Possible EventType values
+const (
+	// EventWebsocketConnected - a websocket connection to Amazon GameLift Servers was established.
+	EventWebsocketConnected EventType = iota
+	// EventWebsocketDisconnected - the websocket connection was closed or failed.
+	EventWebsocketDisconnected
+	// EventWebsocketReconnecting - the server SDK started to re-establish the websocket connection.
+	EventWebsocketReconnecting
+	// EventConnectionRefreshed - the websocket connection was refreshed at the request of Amazon GameLift Servers.
+	EventConnectionRefreshed
+	// EventHeartbeatSent - the health status of the server process was reported.
+	EventHeartbeatSent
+	// EventHeartbeatFailed - the health status of the server process could not be reported.
+	EventHeartbeatFailed
+	// EventRequestTimedOut - no response to a request was received within the service call timeout.
+	EventRequestTimedOut
+	// EventCreateGameSessionReceived - a CreateGameSession message was received.
+	EventCreateGameSessionReceived
+	// EventUpdateGameSessionReceived - an UpdateGameSession message was received.
+	EventUpdateGameSessionReceived
+	// EventTerminateProcessReceived - a TerminateProcess message was received.
+	EventTerminateProcessReceived
+	// EventProcessEndingSent - ProcessEnding was reported to Amazon GameLift Servers.
+	EventProcessEndingSent
+	// EventWebsocketPongReceived - the websocket connection answered a keepalive ping.
+	EventWebsocketPongReceived
+	// EventWebsocketConnectionRetired - a websocket connection was replaced or closed,
+	// no more responses can be received on it.
+	EventWebsocketConnectionRetired
+	// EventWebsocketReconnectFailed - the websocket connection could not be re-established.
+	EventWebsocketReconnectFailed
+	// EventRequestBuffered - a request was held back until the websocket connection is re-established.
+	EventRequestBuffered
+	// EventRequestBufferFlushed - the requests held back were written to the new websocket connection.
+	EventRequestBufferFlushed
+	// EventRequestDropped - a request held back was failed because the buffer was full, its deadline passed
+	// or the websocket connection could not be re-established.
+	EventRequestDropped
+	// EventWebsocketConnectRetry - an attempt to establish the websocket connection failed and will be retried.
+	EventWebsocketConnectRetry
+)
+
+var eventTypeStrs = []string{
+	"WEBSOCKET_CONNECTED",
+	"WEBSOCKET_DISCONNECTED",
+	"WEBSOCKET_RECONNECTING",
+	"CONNECTION_REFRESHED",
+	"HEARTBEAT_SENT",
+	"HEARTBEAT_FAILED",
+	"REQUEST_TIMED_OUT",
+	"CREATE_GAME_SESSION_RECEIVED",
+	"UPDATE_GAME_SESSION_RECEIVED",
+	"TERMINATE_PROCESS_RECEIVED",
+	"PROCESS_ENDING_SENT",
+	"WEBSOCKET_PONG_RECEIVED",
+	"WEBSOCKET_CONNECTION_RETIRED",
+	"WEBSOCKET_RECONNECT_FAILED",
+	"REQUEST_BUFFERED",
+	"REQUEST_BUFFER_FLUSHED",
+	"REQUEST_DROPPED",
+	"WEBSOCKET_CONNECT_RETRY",
+}
+
+func (e *EventType) String() string {
+	n := int(*e)
+	if n < 0 || n >= len(eventTypeStrs) {
+		n = 0
+	}
+	return eventTypeStrs[n]
+}
+
+func (e *EventType) ToEventType(s string) {
+	for i := range eventTypeStrs {
+		if eventTypeStrs[i] == s {
+			*e = EventType(i)
+			return
+		}
+	}
+	*e = EventWebsocketConnected
+}
+
+func (e *EventType) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(e.String())), nil
+}
+
+func (e *EventType) UnmarshalJSON(data []byte) error {
+	origin, err := strconv.Unquote(string(data))
+	if err != nil {
+		return err
+	}
+	e.ToEventType(origin)
+	return nil
+}
+
+// Event - a connection or lifecycle event reported by the server SDK. Only the fields relevant to the Type are set.
+type Event struct {
+	Type EventType `json:"Type"`
+	Time time.Time `json:"Time"`
+	// ConnectionID - sequence number of the websocket connection, for websocket events.
+	ConnectionID int `json:"ConnectionId,omitempty"`
+	// RequestID - identifier of the request, for EventRequestTimedOut.
+	RequestID string `json:"RequestId,omitempty"`
+	// GameSessionID - identifier of the game session, for game session events.
+	GameSessionID string `json:"GameSessionId,omitempty"`
+	// TerminationTime - time the server process will be shut down, for EventTerminateProcessReceived.
+	TerminationTime time.Time `json:"TerminationTime,omitzero"`
+	// Healthy - the reported health status, for EventHeartbeatSent.
+	Healthy bool `json:"Healthy,omitempty"`
+	// RoundTripTime - time between a keepalive ping and its pong, for EventWebsocketPongReceived.
+	RoundTripTime time.Duration `json:"RoundTripTime,omitempty"`
+	// BufferedRequests - the number of requests waiting for the websocket connection to be re-established,
+	// for EventRequestBuffered, EventRequestBufferFlushed and EventRequestDropped.
+	BufferedRequests int `json:"BufferedRequests,omitempty"`
+	// Attempt - the number of the failed connection attempt, for EventWebsocketConnectRetry.
+	Attempt int `json:"Attempt,omitempty"`
+	// RetryDelay - the time until the next connection attempt, for EventWebsocketConnectRetry.
+	RetryDelay time.Duration `json:"RetryDelay,omitempty"`
+	// Err - the cause of a failure or disconnection, if any.
+	Err error `json:"-"`
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventType_MarshalJSON(t *testing.T) {
+	cases := map[EventType]string{
+		EventWebsocketConnected:         "\"WEBSOCKET_CONNECTED\"",
+		EventWebsocketDisconnected:      "\"WEBSOCKET_DISCONNECTED\"",
+		EventWebsocketReconnecting:      "\"WEBSOCKET_RECONNECTING\"",
+		EventConnectionRefreshed:        "\"CONNECTION_REFRESHED\"",
+		EventHeartbeatSent:              "\"HEARTBEAT_SENT\"",
+		EventHeartbeatFailed:            "\"HEARTBEAT_FAILED\"",
+		EventRequestTimedOut:            "\"REQUEST_TIMED_OUT\"",
+		EventCreateGameSessionReceived:  "\"CREATE_GAME_SESSION_RECEIVED\"",
+		EventUpdateGameSessionReceived:  "\"UPDATE_GAME_SESSION_RECEIVED\"",
+		EventTerminateProcessReceived:   "\"TERMINATE_PROCESS_RECEIVED\"",
+		EventProcessEndingSent:          "\"PROCESS_ENDING_SENT\"",
+		EventWebsocketPongReceived:      "\"WEBSOCKET_PONG_RECEIVED\"",
+		EventWebsocketConnectionRetired: "\"WEBSOCKET_CONNECTION_RETIRED\"",
+		EventWebsocketReconnectFailed:   "\"WEBSOCKET_RECONNECT_FAILED\"",
+		EventRequestBuffered:            "\"REQUEST_BUFFERED\"",
+		EventRequestBufferFlushed:       "\"REQUEST_BUFFER_FLUSHED\"",
+		EventRequestDropped:             "\"REQUEST_DROPPED\"",
+		EventWebsocketConnectRetry:      "\"WEBSOCKET_CONNECT_RETRY\"",
+	}
+
+	for origin, expected := range cases {
+		data, err := json.Marshal(&origin)
+		if err != nil {
+			t.Fatalf("json marshal EventType error: %s", err.Error())
+		}
+		if expected != string(data) {
+			t.Errorf("expect %s but get %s", expected, data)
+		}
+
+		var eventType EventType
+		if err := json.Unmarshal(data, &eventType); err != nil {
+			t.Fatalf("json unmarshal EventType error: %s", err.Error())
+		}
+		if eventType != origin {
+			t.Errorf("expect %v but get %v", origin, eventType)
+		}
+	}
+}
+
+func TestEvent_MarshalJSON_OmitZeroTerminationTime(t *testing.T) {
+	data, err := json.Marshal(&Event{Type: EventHeartbeatSent, Time: time.Unix(0, 0).UTC()})
+	if err != nil {
+		t.Fatalf("json marshal Event error: %s", err.Error())
+	}
+	if strings.Contains(string(data), "TerminationTime") {
+		t.Errorf("expect no TerminationTime but get %s", data)
+	}
+
+	terminationTime := time.Unix(1640995200, 0).UTC()
+	data, err = json.Marshal(&Event{Type: EventTerminateProcessReceived, TerminationTime: terminationTime})
+	if err != nil {
+		t.Fatalf("json marshal Event error: %s", err.Error())
+	}
+	if !strings.Contains(string(data), "\"TerminationTime\":\"2022-01-01T00:00:00Z\"") {
+		t.Errorf("expect TerminationTime %s but get %s", terminationTime, data)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+)
+
+// gamePropertyTag - struct tag naming the game property of a field, see BindGameProperties.
+const gamePropertyTag = "gamelift"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// BindGameProperties - returns a T with its fields set from the GameProperties of the game session.
+// T must be a struct. The property name is set by the gamelift struct tag, and defaults to the field name.
+// The tag options are:
+//   - required: the game session must have the property,
+//   - default=<value>: the value used when the game session does not have the property; it cannot contain commas.
+//
+// Properties are converted to strings, bools, ints, uints, floats and time.Duration, the latter with
+// time.ParseDuration. Fields tagged "-" and unexported fields are skipped.
+//
+// All fields are bound before returning. Every missing required property and every value that cannot be converted
+// is returned as a common.ValidationException error naming the property, joined with errors.Join.
+// A game session that cannot be bound should not be activated:
+//
+//	type MatchSettings struct {
+//		Map        string        `gamelift:"map,required"`
+//		MaxPlayers int           `gamelift:"maxPlayers,default=10"`
+//		Ranked     bool          `gamelift:"ranked"`
+//		RoundTime  time.Duration `gamelift:"roundTime,default=3m"`
+//	}
+//
+//	OnStartGameSession: func(session model.GameSession) {
+//		settings, err := model.BindGameProperties[MatchSettings](session)
+//		if err != nil {
+//			log.Printf("Invalid game session %s: %s", session.GameSessionID, err)
+//			server.ProcessEnding()
+//			return
+//		}
+//		...
+//		server.ActivateGameSession()
+//	}
+func BindGameProperties[T any](session GameSession) (T, error) {
+	var bound T
+	value := reflect.ValueOf(&bound).Elem()
+	if value.Kind() != reflect.Struct {
+		return bound, common.NewGameLiftError(common.ValidationException, "",
+			fmt.Sprintf("Cannot bind game properties to %T, a struct is required", bound))
+	}
+	var errs []error
+	_ = walkTaggedFields(value, gamePropertyTag, func(field taggedField) error {
+		property, ok := session.GameProperties[field.name]
+		if !ok {
+			property, ok = field.optionValue("default")
+		}
+		if !ok {
+			if field.hasOption("required") {
+				errs = append(errs, gamePropertyError(field.name, "is required"))
+			}
+			return nil
+		}
+		if err := bindGameProperty(property, field.value); err != nil {
+			errs = append(errs, gamePropertyError(field.name, "%s", err))
+		}
+		return nil
+	})
+	return bound, errors.Join(errs...)
+}
+
+// DecodeGameSessionData - returns the GameSessionData of the game session decoded as JSON into a T.
+// Empty game session data decodes to the zero T. Returns a common.ValidationException error if the data
+// is not valid JSON for T; like BindGameProperties, such a game session should not be activated.
+func DecodeGameSessionData[T any](session GameSession) (T, error) {
+	var data T
+	if session.GameSessionData == "" {
+		return data, nil
+	}
+	if err := json.Unmarshal([]byte(session.GameSessionData), &data); err != nil {
+		return data, common.NewGameLiftError(common.ValidationException, "",
+			fmt.Sprintf("Game session data: %s", err))
+	}
+	return data, nil
+}
+
+func bindGameProperty(property string, value reflect.Value) error {
+	if value.Kind() == reflect.Pointer {
+		elem := reflect.New(value.Type().Elem())
+		if err := bindGameProperty(property, elem.Elem()); err != nil {
+			return err
+		}
+		value.Set(elem)
+		return nil
+	}
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(property)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", property)
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(property)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(property)
+		if err != nil {
+			return fmt.Errorf("%q is not a bool", property)
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(property, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid %s", property, value.Type())
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(property, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid %s", property, value.Type())
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(property, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid %s", property, value.Type())
+		}
+		value.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+func gamePropertyError(name, format string, args ...any) error {
+	return common.NewGameLiftError(common.ValidationException, "",
+		fmt.Sprintf("Game property %s: %s", name, fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+)
+
+type testMatchSettings struct {
+	Map        string        `gamelift:"map,required"`
+	MaxPlayers int           `gamelift:"maxPlayers,default=10"`
+	Ranked     bool          `gamelift:"ranked"`
+	RoundTime  time.Duration `gamelift:"roundTime,default=3m"`
+	Gravity    *float64      `gamelift:"gravity"`
+	Seed       uint32
+	Ignored    string `gamelift:"-"`
+}
+
+func TestBindGameProperties(t *testing.T) {
+	// GIVEN
+	session := GameSession{GameProperties: map[string]string{
+		"map":     "desert",
+		"ranked":  "true",
+		"gravity": "9.8",
+		"Seed":    "42",
+		"Ignored": "value",
+	}}
+
+	// WHEN
+	settings, err := BindGameProperties[testMatchSettings](session)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	gravity := 9.8
+	expected := testMatchSettings{
+		Map:        "desert",
+		MaxPlayers: 10,
+		Ranked:     true,
+		RoundTime:  3 * time.Minute,
+		Gravity:    &gravity,
+		Seed:       42,
+	}
+	if !reflect.DeepEqual(expected, settings) {
+		t.Fatalf("Expected %+v but got %+v", expected, settings)
+	}
+}
+
+func TestBindGameProperties_ReturnAllErrors(t *testing.T) {
+	// GIVEN
+	session := GameSession{GameProperties: map[string]string{
+		"maxPlayers": "many",
+		"ranked":     "maybe",
+		"roundTime":  "3",
+	}}
+
+	// WHEN
+	_, err := BindGameProperties[testMatchSettings](session)
+
+	// THEN
+	if err == nil {
+		t.Fatal("Expected binding errors")
+	}
+	for _, expected := range []string{
+		"Game property map: is required",
+		`Game property maxPlayers: "many" is not a valid int`,
+		`Game property ranked: "maybe" is not a bool`,
+		`Game property roundTime: "3" is not a duration`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in %s", expected, err)
+		}
+	}
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.ValidationException {
+		t.Fatalf("Expected ValidationException error but got %v", err)
+	}
+}
+
+func TestDecodeGameSessionData(t *testing.T) {
+	type payload struct {
+		Mode  string `json:"mode"`
+		Waves int    `json:"waves"`
+	}
+
+	// WHEN
+	data, err := DecodeGameSessionData[payload](GameSession{GameSessionData: `{"mode":"survival","waves":5}`})
+	empty, emptyErr := DecodeGameSessionData[payload](GameSession{})
+	_, invalidErr := DecodeGameSessionData[payload](GameSession{GameSessionData: `{"waves":"five"}`})
+
+	// THEN
+	if err != nil || emptyErr != nil {
+		t.Fatal(err, emptyErr)
+	}
+	common.AssertEqual(t, payload{Mode: "survival", Waves: 5}, data)
+	common.AssertEqual(t, payload{}, empty)
+	if invalidErr == nil || !strings.Contains(invalidErr.Error(), "Game session data") {
+		t.Fatalf("Expected decoding error but got %v", invalidErr)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"slices"
+)
+
+// LatencyStats - statistics of the latencies reported for a Region by a set of players, in milliseconds.
+type LatencyStats struct {
+	// Players - number of players that reported a latency for the Region.
+	Players int
+	Min     int
+	Max     int
+	Mean    float64
+	Median  float64
+}
+
+// RegionLatencies - returns the latency statistics of each Region reported by at least one of the players,
+// see Player.LatencyInMS.
+func RegionLatencies(players []Player) map[string]LatencyStats {
+	samples := make(map[string][]int)
+	for i := range players {
+		for region, latency := range players[i].LatencyInMS {
+			samples[region] = append(samples[region], latency)
+		}
+	}
+	stats := make(map[string]LatencyStats, len(samples))
+	for region, latencies := range samples {
+		stats[region] = newLatencyStats(latencies)
+	}
+	return stats
+}
+
+// TeamRegionLatencies - returns the latency statistics of each Region for each team, see RegionLatencies.
+func TeamRegionLatencies(players []Player) map[string]map[string]LatencyStats {
+	teams := make(map[string][]Player)
+	for i := range players {
+		teams[players[i].Team] = append(teams[players[i].Team], players[i])
+	}
+	stats := make(map[string]map[string]LatencyStats, len(teams))
+	for team, teamPlayers := range teams {
+		stats[team] = RegionLatencies(teamPlayers)
+	}
+	return stats
+}
+
+// BestRegion - returns the Region with the lowest maximum latency among the Regions reported by all players,
+// as FlexMatch only places a match in a Region for which every player reported a latency.
+// Ties are broken by the lowest mean latency, then by Region name.
+// Returns false if no Region was reported by all players.
+func BestRegion(players []Player) (string, LatencyStats, bool) {
+	var (
+		best      string
+		bestStats LatencyStats
+		found     bool
+	)
+	for region, stats := range RegionLatencies(players) {
+		if stats.Players != len(players) {
+			continue
+		}
+		if !found || stats.Max < bestStats.Max ||
+			(stats.Max == bestStats.Max && (stats.Mean < bestStats.Mean ||
+				(stats.Mean == bestStats.Mean && region < best))) {
+			best, bestStats, found = region, stats, true
+		}
+	}
+	return best, bestStats, found
+}
+
+func newLatencyStats(latencies []int) LatencyStats {
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	stats := LatencyStats{Players: len(sorted), Min: sorted[0], Max: sorted[len(sorted)-1]}
+	sum := 0
+	for _, latency := range sorted {
+		sum += latency
+	}
+	stats.Mean = float64(sum) / float64(len(sorted))
+	if mid := len(sorted) / 2; len(sorted)%2 == 1 {
+		stats.Median = float64(sorted[mid])
+	} else {
+		stats.Median = float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	return stats
+}
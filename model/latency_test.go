@@ -0,0 +1,58 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"testing"
+)
+
+var latencyPlayers = []Player{
+	{PlayerID: "player-1", Team: "red", LatencyInMS: map[string]int{"us-west-2": 20, "us-east-1": 80}},
+	{PlayerID: "player-2", Team: "red", LatencyInMS: map[string]int{"us-west-2": 60, "us-east-1": 40}},
+	{PlayerID: "player-3", Team: "blue", LatencyInMS: map[string]int{"us-west-2": 30, "us-east-1": 50, "eu-west-1": 10}},
+}
+
+func TestRegionLatencies(t *testing.T) {
+	// WHEN
+	stats := RegionLatencies(latencyPlayers)
+
+	// THEN
+	expected := LatencyStats{Players: 3, Min: 20, Max: 60, Mean: 110.0 / 3, Median: 30}
+	if stats["us-west-2"] != expected {
+		t.Fatalf("expect %+v but get %+v", expected, stats["us-west-2"])
+	}
+	if stats["eu-west-1"].Players != 1 {
+		t.Fatalf("expect 1 player for eu-west-1 but get %+v", stats["eu-west-1"])
+	}
+}
+
+func TestTeamRegionLatencies(t *testing.T) {
+	// WHEN
+	stats := TeamRegionLatencies(latencyPlayers)
+
+	// THEN
+	expected := LatencyStats{Players: 2, Min: 40, Max: 80, Mean: 60, Median: 60}
+	if stats["red"]["us-east-1"] != expected {
+		t.Fatalf("expect %+v but get %+v", expected, stats["red"]["us-east-1"])
+	}
+	if _, ok := stats["red"]["eu-west-1"]; ok {
+		t.Fatal("expect no eu-west-1 latency for team red")
+	}
+}
+
+func TestBestRegion(t *testing.T) {
+	// WHEN
+	region, stats, ok := BestRegion(latencyPlayers)
+	_, _, none := BestRegion([]Player{{PlayerID: "player-4"}})
+
+	// THEN
+	if !ok || region != "us-west-2" || stats.Max != 60 {
+		t.Fatalf("expect us-west-2 but get %s %+v", region, stats)
+	}
+	if none {
+		t.Fatal("expect no region for players without latency")
+	}
+}
@@ -7,6 +7,8 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 type MatchmakerData struct {
@@ -17,6 +19,9 @@ type MatchmakerData struct {
 	MatchmakingConfigurationArn string
 	// A set of data representing all players who are currently in the game session.
 	Players []Player
+	// Names of the teams of the match in their original order, including teams without players.
+	// Teams of Players that are not listed are serialized after the listed ones, in order of first appearance.
+	Teams []string
 	// A unique identifier for a matchmaking ticket. If no ticket ID is specified here,
 	// Amazon GameLift Servers will generate one in the form of a UUID.
 	// Length Constraints: Maximum length of 128.
@@ -42,6 +47,8 @@ type playerMatchmakerData struct {
 	// Type: String to AttributeValue object map
 	// Key Length Constraints: Minimum length of 1. Maximum length of 1024.
 	AttributeValue map[string]attributeMatchMakerData `json:"attributes"`
+	// Latency in milliseconds reported by the player for each Region, see Player.LatencyInMS.
+	LatencyInMS map[string]int `json:"latencyInMs,omitempty"`
 }
 
 type teamMatchmakerData struct {
@@ -69,14 +76,31 @@ type matchmakerDataOriginal struct {
 	BackfillMode string `json:"autoBackfillMode"`
 }
 
-func (m *MatchmakerData) MarshalJSON() ([]byte, error) {
+// MarshalJSON - serializes the matchmaker data in the format used by Amazon GameLift Servers.
+// The output is stable: teams are in the order of Teams, players keep their order and attributes are sorted by name.
+func (m MatchmakerData) MarshalJSON() ([]byte, error) {
 	var origin matchmakerDataOriginal
-	origin.fromMatchmakerData(m)
+	origin.fromMatchmakerData(&m)
 
 	return json.Marshal(origin)
 }
 
+// UnmarshalJSON - parses matchmaker data in the format used by Amazon GameLift Servers.
+// Attributes of an unknown type, or with a value that does not match their type, are parsed by the type of their value,
+// see MakeAttributeValue; use UnmarshalMatchmakerDataStrict to reject them instead.
 func (m *MatchmakerData) UnmarshalJSON(data []byte) error {
+	return m.unmarshal(data, false)
+}
+
+// UnmarshalMatchmakerDataStrict - same as MatchmakerData.UnmarshalJSON, but returns an error for an attribute
+// of an unknown type, or with a value that does not match its type.
+func UnmarshalMatchmakerDataStrict(data []byte) (MatchmakerData, error) {
+	var m MatchmakerData
+	err := m.unmarshal(data, true)
+	return m, err
+}
+
+func (m *MatchmakerData) unmarshal(data []byte, strict bool) error {
 	var matchmaker matchmakerDataOriginal
 
 	if len(data) == 0 {
@@ -86,12 +110,19 @@ func (m *MatchmakerData) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &matchmaker); err != nil {
 		return err
 	}
-	*m = matchmaker.toMatchmakerData()
+	result, err := matchmaker.toMatchmakerData(strict)
+	if err != nil {
+		return err
+	}
+	*m = result
 
 	return nil
 }
 
 func fromAttributesValue(a AttributeValue) attributeMatchMakerData {
+	if a.AttrType == nil {
+		return attributeMatchMakerData{AttributeType: "NONE"}
+	}
 	switch *a.AttrType {
 	case String:
 		return attributeMatchMakerData{
@@ -124,33 +155,33 @@ func (mo *matchmakerDataOriginal) fromMatchmakerData(m *MatchmakerData) {
 	mo.MatchmakingConfigurationArn = m.MatchmakingConfigurationArn
 	mo.AutoBackfillTicketID = m.AutoBackfillTicketID
 	mo.BackfillMode = m.BackfillMode.String()
-	teams := make(map[string][]Player)
-	for i := range m.Players {
-		if _, ok := teams[m.Players[i].Team]; !ok {
-			teams[m.Players[i].Team] = make([]Player, 0, 1)
+	teams := make(map[string]int)
+	addTeam := func(name string) int {
+		if i, ok := teams[name]; ok {
+			return i
 		}
-		teams[m.Players[i].Team] = append(teams[m.Players[i].Team], m.Players[i])
-	}
-	for name, players := range teams {
-		var pMatchmakerData []playerMatchmakerData
-		for _, singlePlayer := range players {
-			var attributes = make(map[string]attributeMatchMakerData)
-			for k, v := range singlePlayer.PlayerAttributes {
-				attributes[k] = fromAttributesValue(v)
-			}
-			pMatchmakerData = append(pMatchmakerData, playerMatchmakerData{
-				PlayerID:       singlePlayer.PlayerID,
-				AttributeValue: attributes,
-			})
+		teams[name] = len(mo.Teams)
+		mo.Teams = append(mo.Teams, teamMatchmakerData{Name: name, Players: []playerMatchmakerData{}})
+		return teams[name]
+	}
+	for _, name := range m.Teams {
+		addTeam(name)
+	}
+	for _, singlePlayer := range m.Players {
+		var attributes = make(map[string]attributeMatchMakerData)
+		for k, v := range singlePlayer.PlayerAttributes {
+			attributes[k] = fromAttributesValue(v)
 		}
-		mo.Teams = append(mo.Teams, teamMatchmakerData{
-			Name:    name,
-			Players: pMatchmakerData,
+		team := &mo.Teams[addTeam(singlePlayer.Team)]
+		team.Players = append(team.Players, playerMatchmakerData{
+			PlayerID:       singlePlayer.PlayerID,
+			AttributeValue: attributes,
+			LatencyInMS:    singlePlayer.LatencyInMS,
 		})
 	}
 }
 
-func (mo *matchmakerDataOriginal) toMatchmakerData() MatchmakerData {
+func (mo *matchmakerDataOriginal) toMatchmakerData(strict bool) (MatchmakerData, error) {
 	var data = MatchmakerData{
 		MatchID:                     mo.MatchID,
 		MatchmakingConfigurationArn: mo.MatchmakingConfigurationArn,
@@ -158,17 +189,69 @@ func (mo *matchmakerDataOriginal) toMatchmakerData() MatchmakerData {
 		BackfillMode:                toBackfillMode(mo.BackfillMode),
 	}
 	for _, team := range mo.Teams {
+		data.Teams = append(data.Teams, team.Name)
 		for _, player := range team.Players {
 			var playerAttributes = make(map[string]AttributeValue)
 			for k, v := range player.AttributeValue {
-				playerAttributes[k] = MakeAttributeValue(v.ValueAttribute)
+				attr, err := v.toAttributeValue(strict)
+				if err != nil {
+					return MatchmakerData{}, fmt.Errorf("player %s attribute %s: %w", player.PlayerID, k, err)
+				}
+				playerAttributes[k] = attr
 			}
 			data.Players = append(data.Players, Player{
 				Team:             team.Name,
 				PlayerID:         player.PlayerID,
 				PlayerAttributes: playerAttributes,
+				LatencyInMS:      player.LatencyInMS,
 			})
 		}
 	}
-	return data
+	return data, nil
+}
+
+// toAttributeValue - converts the value by its declared type. In non-strict mode, a value of an unknown type
+// or that does not match its type is converted by the type of the value.
+func (a *attributeMatchMakerData) toAttributeValue(strict bool) (AttributeValue, error) {
+	attrType := None
+	known := false
+	for i := range attributeTypesStr {
+		if strings.EqualFold(attributeTypesStr[i], a.AttributeType) {
+			attrType, known = attributeType(i), true
+			break
+		}
+	}
+	if !known {
+		if strict {
+			return AttributeValue{}, fmt.Errorf("unknown attribute type %q", a.AttributeType)
+		}
+		return MakeAttributeValue(a.ValueAttribute), nil
+	}
+	if attrType == None || (a.ValueAttribute == nil && (attrType == StringList || attrType == StringDoubleMap)) {
+		return AttributeValue{AttrType: &attrType}, nil
+	}
+	value := MakeAttributeValue(a.ValueAttribute)
+	if strict && (value.GetAttrType() != attrType || !isWholeValue(a.ValueAttribute)) {
+		return AttributeValue{}, fmt.Errorf("value %v is not a valid %s", a.ValueAttribute, attrType.String())
+	}
+	return value, nil
+}
+
+// isWholeValue - reports whether MakeAttributeValue keeps every element of the decoded JSON value.
+func isWholeValue(v any) bool {
+	switch v := v.(type) {
+	case []any:
+		for _, e := range v {
+			if _, ok := e.(string); !ok {
+				return false
+			}
+		}
+	case map[string]any:
+		for _, e := range v {
+			if _, ok := e.(float64); !ok {
+				return false
+			}
+		}
+	}
+	return true
 }
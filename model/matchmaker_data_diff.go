@@ -0,0 +1,157 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"maps"
+	"slices"
+	"sort"
+)
+
+// PlayerAttributeChange - a player attribute that was added, removed or changed between two MatchmakerData.
+type PlayerAttributeChange struct {
+	PlayerID  string
+	Attribute string
+	// Previous - the previous value, or nil if the attribute was added.
+	Previous *AttributeValue
+	// Current - the current value, or nil if the attribute was removed.
+	Current *AttributeValue
+}
+
+// MatchmakerDataDiff - differences between two MatchmakerData of a game session, see DiffMatchmakerData.
+type MatchmakerDataDiff struct {
+	// PlayersAdded - players that joined, by team. A player that moved to another team is listed as added
+	// to the new team and removed from the old one.
+	PlayersAdded map[string][]Player
+	// PlayersRemoved - players that left, by team, with their previous data.
+	PlayersRemoved map[string][]Player
+	// AttributesChanged - attribute changes of players that stayed in the game session,
+	// ordered by player ID and attribute.
+	AttributesChanged []PlayerAttributeChange
+
+	// MatchIDChanged - the match ID differs; MatchID holds the current one.
+	MatchIDChanged bool
+	MatchID        string
+	// AutoBackfillTicketChanged - the auto-backfill ticket differs; AutoBackfillTicketID holds the current one.
+	AutoBackfillTicketChanged bool
+	AutoBackfillTicketID      string
+	// BackfillModeChanged - the backfill mode differs; BackfillMode holds the current one.
+	BackfillModeChanged bool
+	BackfillMode        backfillMode
+}
+
+// HasChanges - reports whether any difference was found.
+func (d *MatchmakerDataDiff) HasChanges() bool {
+	return len(d.PlayersAdded) > 0 || len(d.PlayersRemoved) > 0 || len(d.AttributesChanged) > 0 ||
+		d.MatchIDChanged || d.AutoBackfillTicketChanged || d.BackfillModeChanged
+}
+
+// AddedPlayers - returns the players that joined, ordered by team.
+func (d *MatchmakerDataDiff) AddedPlayers() []Player {
+	var players []Player
+	for _, team := range slices.Sorted(maps.Keys(d.PlayersAdded)) {
+		players = append(players, d.PlayersAdded[team]...)
+	}
+	return players
+}
+
+// DiffMatchmakerData - returns the differences from previous to current. Players are matched by PlayerID.
+// Use an empty MatchmakerData as previous to treat every current player as added.
+func DiffMatchmakerData(previous, current *MatchmakerData) MatchmakerDataDiff {
+	diff := MatchmakerDataDiff{
+		MatchIDChanged:            previous.MatchID != current.MatchID,
+		MatchID:                   current.MatchID,
+		AutoBackfillTicketChanged: previous.AutoBackfillTicketID != current.AutoBackfillTicketID,
+		AutoBackfillTicketID:      current.AutoBackfillTicketID,
+		BackfillModeChanged:       previous.BackfillMode != current.BackfillMode,
+		BackfillMode:              current.BackfillMode,
+	}
+
+	previousPlayers := make(map[string]*Player, len(previous.Players))
+	for i := range previous.Players {
+		previousPlayers[previous.Players[i].PlayerID] = &previous.Players[i]
+	}
+	currentPlayers := make(map[string]*Player, len(current.Players))
+	for i := range current.Players {
+		player := &current.Players[i]
+		currentPlayers[player.PlayerID] = player
+		old, ok := previousPlayers[player.PlayerID]
+		if !ok || old.Team != player.Team {
+			diff.PlayersAdded = appendTeamPlayer(diff.PlayersAdded, *player)
+		}
+		if ok {
+			diff.AttributesChanged = append(diff.AttributesChanged, diffAttributes(player.PlayerID, old, player)...)
+		}
+	}
+	for i := range previous.Players {
+		player := &previous.Players[i]
+		if now, ok := currentPlayers[player.PlayerID]; !ok || now.Team != player.Team {
+			diff.PlayersRemoved = appendTeamPlayer(diff.PlayersRemoved, *player)
+		}
+	}
+	sort.SliceStable(diff.AttributesChanged, func(i, j int) bool {
+		a, b := diff.AttributesChanged[i], diff.AttributesChanged[j]
+		if a.PlayerID != b.PlayerID {
+			return a.PlayerID < b.PlayerID
+		}
+		return a.Attribute < b.Attribute
+	})
+	return diff
+}
+
+func appendTeamPlayer(teams map[string][]Player, player Player) map[string][]Player {
+	if teams == nil {
+		teams = make(map[string][]Player)
+	}
+	teams[player.Team] = append(teams[player.Team], player)
+	return teams
+}
+
+func diffAttributes(playerID string, previous, current *Player) []PlayerAttributeChange {
+	var changes []PlayerAttributeChange
+	for name, value := range current.PlayerAttributes {
+		old, ok := previous.PlayerAttributes[name]
+		switch {
+		case !ok:
+			changes = append(changes, PlayerAttributeChange{PlayerID: playerID, Attribute: name, Current: &value})
+		case !old.Equal(value):
+			changes = append(changes, PlayerAttributeChange{
+				PlayerID: playerID, Attribute: name, Previous: &old, Current: &value,
+			})
+		}
+	}
+	for name, old := range previous.PlayerAttributes {
+		if _, ok := current.PlayerAttributes[name]; !ok {
+			changes = append(changes, PlayerAttributeChange{PlayerID: playerID, Attribute: name, Previous: &old})
+		}
+	}
+	return changes
+}
+
+// Equal - reports whether both attribute values have the same type and value.
+func (a AttributeValue) Equal(other AttributeValue) bool {
+	var typeA, typeB attributeType
+	if a.AttrType != nil {
+		typeA = *a.AttrType
+	}
+	if other.AttrType != nil {
+		typeB = *other.AttrType
+	}
+	if typeA != typeB {
+		return false
+	}
+	switch typeA {
+	case String:
+		return a.S == other.S
+	case Double:
+		return a.N == other.N
+	case StringList:
+		return slices.Equal(a.SL, other.SL)
+	case StringDoubleMap:
+		return maps.Equal(a.SDM, other.SDM)
+	}
+	return true
+}
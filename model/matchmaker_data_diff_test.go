@@ -0,0 +1,111 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func doubleAttribute(n float64) AttributeValue {
+	attrType := Double
+	return AttributeValue{AttrType: &attrType, N: n}
+}
+
+func TestDiffMatchmakerData(t *testing.T) {
+	// GIVEN
+	previous := MatchmakerData{
+		MatchID: "match-1",
+		Players: []Player{
+			{PlayerID: "player-1", Team: "red", PlayerAttributes: map[string]AttributeValue{"skill": doubleAttribute(10)}},
+			{PlayerID: "player-2", Team: "red", PlayerAttributes: map[string]AttributeValue{"skill": doubleAttribute(20)}},
+			{PlayerID: "player-3", Team: "blue"},
+		},
+		BackfillMode: BackFillModeManual,
+	}
+	current := MatchmakerData{
+		MatchID: "match-1",
+		Players: []Player{
+			{PlayerID: "player-1", Team: "red", PlayerAttributes: map[string]AttributeValue{"skill": doubleAttribute(15)}},
+			{PlayerID: "player-2", Team: "blue", PlayerAttributes: map[string]AttributeValue{"skill": doubleAttribute(20)}},
+			{PlayerID: "player-4", Team: "blue"},
+		},
+		AutoBackfillTicketID: "ticket-1",
+		BackfillMode:         BackFillModeAutomatic,
+	}
+
+	// WHEN
+	diff := DiffMatchmakerData(&previous, &current)
+
+	// THEN
+	if !diff.HasChanges() {
+		t.Fatal("Expected changes")
+	}
+	expectedAdded := map[string][]Player{"blue": {current.Players[1], current.Players[2]}}
+	if !reflect.DeepEqual(expectedAdded, diff.PlayersAdded) {
+		t.Fatalf("Unexpected added players: %v", diff.PlayersAdded)
+	}
+	expectedRemoved := map[string][]Player{"red": {previous.Players[1]}, "blue": {previous.Players[2]}}
+	if !reflect.DeepEqual(expectedRemoved, diff.PlayersRemoved) {
+		t.Fatalf("Unexpected removed players: %v", diff.PlayersRemoved)
+	}
+	if len(diff.AttributesChanged) != 1 {
+		t.Fatalf("Expected 1 attribute change but got %d", len(diff.AttributesChanged))
+	}
+	change := diff.AttributesChanged[0]
+	if change.PlayerID != "player-1" || change.Attribute != "skill" || change.Previous.N != 10 || change.Current.N != 15 {
+		t.Fatalf("Unexpected attribute change: %+v", change)
+	}
+	if diff.MatchIDChanged || !diff.AutoBackfillTicketChanged || !diff.BackfillModeChanged {
+		t.Fatalf("Unexpected diff: %+v", diff)
+	}
+	if diff.AutoBackfillTicketID != "ticket-1" || diff.BackfillMode != BackFillModeAutomatic {
+		t.Fatalf("Unexpected diff: %+v", diff)
+	}
+}
+
+func TestDiffMatchmakerData_NoChanges(t *testing.T) {
+	// WHEN
+	diff := DiffMatchmakerData(&matchMakerData, &matchMakerData)
+
+	// THEN
+	if diff.HasChanges() {
+		t.Fatalf("Expected no changes but got %+v", diff)
+	}
+}
+
+func TestDiffMatchmakerData_FromEmpty(t *testing.T) {
+	// WHEN
+	diff := DiffMatchmakerData(&MatchmakerData{}, &matchMakerData)
+
+	// THEN
+	if len(diff.AddedPlayers()) != len(matchMakerData.Players) {
+		t.Fatalf("Expected all players to be added but got %v", diff.AddedPlayers())
+	}
+	if len(diff.PlayersRemoved) != 0 || len(diff.AttributesChanged) != 0 {
+		t.Fatalf("Unexpected diff: %+v", diff)
+	}
+}
+
+func TestAttributeValue_Equal(t *testing.T) {
+	stringType, listType := String, StringList
+	tests := []struct {
+		a, b  AttributeValue
+		equal bool
+	}{
+		{doubleAttribute(1), doubleAttribute(1), true},
+		{doubleAttribute(1), doubleAttribute(2), false},
+		{AttributeValue{AttrType: &stringType, S: "1"}, doubleAttribute(1), false},
+		{AttributeValue{AttrType: &listType, SL: []string{"a"}}, AttributeValue{AttrType: &listType, SL: []string{"a"}}, true},
+		{AttributeValue{AttrType: &listType, SL: []string{"a"}}, AttributeValue{AttrType: &listType, SL: []string{"b"}}, false},
+		{AttributeValue{}, AttributeValue{AttrType: &attrValue[0]}, true},
+	}
+	for i, test := range tests {
+		if test.a.Equal(test.b) != test.equal {
+			t.Errorf("%d: expected Equal to return %t", i, test.equal)
+		}
+	}
+}
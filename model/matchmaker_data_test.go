@@ -19,6 +19,7 @@ var matchMakerData = MatchmakerData{
 	MatchmakingConfigurationArn: "TestMatchMakingConfigurationArn",
 	AutoBackfillTicketID:        "TestAutoBackfillTicketID",
 	BackfillMode:                BackFillModeAutomatic,
+	Teams:                       []string{"attacker", "defender"},
 	Players: []Player{
 		{
 			PlayerID: "TestPlayerID_1",
@@ -288,3 +289,102 @@ func TestMatchmakerData_UnmarshalJSON(t *testing.T) {
 		t.Fatalf("\nexpect  %v \nbut get %v", matchMakerData, unmarshalJsonOutput)
 	}
 }
+
+func TestMatchmakerData_MarshalJSON_Stable(t *testing.T) {
+	// GIVEN
+	data := matchMakerData
+	data.Teams = nil
+
+	// WHEN
+	first, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		next, err := json.Marshal(&data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// THEN
+		if string(first) != string(next) {
+			t.Fatalf("Expected stable output but got\n%s\n%s", first, next)
+		}
+	}
+	var origin matchmakerDataOriginal
+	if err := json.Unmarshal(first, &origin); err != nil {
+		t.Fatal(err)
+	}
+	if origin.Teams[0].Name != "attacker" || origin.Teams[1].Name != "defender" {
+		t.Fatalf("Expected teams in order of first appearance but got %v", origin.Teams)
+	}
+}
+
+func TestMatchmakerData_RoundTrip(t *testing.T) {
+	// GIVEN
+	data := matchMakerData
+	data.Teams = []string{"defender", "spectator", "attacker"}
+	data.Players = append([]Player{}, data.Players...)
+	data.Players[0].LatencyInMS = map[string]int{"us-west-2": 20, "us-east-1": 70}
+	data.Players[1].PlayerAttributes = map[string]AttributeValue{
+		"empty": MakeAttributeValue([]string{}),
+		"zero":  MakeAttributeValue(float64(0)),
+	}
+
+	// WHEN
+	marshaled, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalMatchmakerDataStrict(marshaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remarshaled, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	if string(marshaled) != string(remarshaled) {
+		t.Fatalf("Expected identical output but got\n%s\n%s", marshaled, remarshaled)
+	}
+	if !reflect.DeepEqual(data.Teams, decoded.Teams) {
+		t.Fatalf("Expected teams %v but got %v", data.Teams, decoded.Teams)
+	}
+	player, _ := getDecodedPlayer(decoded, "TestPlayerID_1")
+	if !reflect.DeepEqual(data.Players[0].LatencyInMS, player.LatencyInMS) {
+		t.Fatalf("Expected latency %v but got %v", data.Players[0].LatencyInMS, player.LatencyInMS)
+	}
+	player, _ = getDecodedPlayer(decoded, "TestPlayerID_2")
+	if !reflect.DeepEqual(data.Players[1].PlayerAttributes, player.PlayerAttributes) {
+		t.Fatalf("Expected attributes %v but got %v", data.Players[1].PlayerAttributes, player.PlayerAttributes)
+	}
+}
+
+func TestUnmarshalMatchmakerDataStrict_ReturnError(t *testing.T) {
+	cases := []string{
+		`{"teams":[{"name":"red","players":[{"playerId":"p1","attributes":{"skill":{"attributeType":"OBJECT","valueAttribute":{}}}}]}]}`,
+		`{"teams":[{"name":"red","players":[{"playerId":"p1","attributes":{"skill":{"attributeType":"DOUBLE","valueAttribute":"high"}}}]}]}`,
+		`{"teams":[{"name":"red","players":[{"playerId":"p1","attributes":{"modes":{"attributeType":"STRING_LIST","valueAttribute":["a",1]}}}]}]}`,
+	}
+
+	for _, data := range cases {
+		if _, err := UnmarshalMatchmakerDataStrict([]byte(data)); err == nil {
+			t.Errorf("Expected error decoding %s", data)
+		}
+		var lenient MatchmakerData
+		if err := json.Unmarshal([]byte(data), &lenient); err != nil {
+			t.Errorf("Expected lenient decoding of %s but got %s", data, err)
+		}
+	}
+}
+
+func getDecodedPlayer(data MatchmakerData, id string) (*Player, error) {
+	for i := range data.Players {
+		if data.Players[i].PlayerID == id {
+			return &data.Players[i], nil
+		}
+	}
+	return nil, fmt.Errorf("undefine player with id %s", id)
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+)
+
+// playerAttributeTag - struct tag naming the player attribute of a field, see EncodePlayerAttributes.
+const playerAttributeTag = "gamelift"
+
+// EncodePlayerAttributes - converts the fields of the struct v, or of the struct v points to,
+// to player attributes. The attribute name is set by the gamelift struct tag, and defaults to the field name:
+//
+//	type PlayerProfile struct {
+//		Skill      int                `gamelift:"skill"`
+//		GameMode   string             `gamelift:"gameMode,omitempty"`
+//		Characters []string           `gamelift:"characters"`
+//		Latency    map[string]float64 `gamelift:"latency"`
+//		Session    string             `gamelift:"-"`
+//	}
+//
+// Fields are converted by kind:
+//   - ints, uints and floats to DOUBLE,
+//   - strings to STRING,
+//   - string slices to STRING_LIST,
+//   - maps of strings to floats to STRING_DOUBLE_MAP.
+//
+// Fields tagged "-" and unexported fields are skipped, as are nil pointers and fields with the omitempty option
+// that hold a zero value. Fields of embedded structs are encoded as if they were fields of v.
+// Returns a common.ValidationException error naming the field if a field has an unsupported type.
+func EncodePlayerAttributes(v any) (map[string]AttributeValue, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, playerAttributeError("", "cannot encode %T, a struct is required", v)
+	}
+	attrs := make(map[string]AttributeValue)
+	err := walkTaggedFields(value, playerAttributeTag, func(field taggedField) error {
+		fieldValue := field.value
+		if fieldValue.Kind() == reflect.Pointer {
+			if fieldValue.IsNil() {
+				return nil
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if field.hasOption("omitempty") && fieldValue.IsZero() {
+			return nil
+		}
+		attr, err := encodePlayerAttribute(fieldValue)
+		if err != nil {
+			return playerAttributeError(field.name, "field %s: %s", field.path, err)
+		}
+		attrs[field.name] = attr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+// DecodePlayerAttributes - sets the fields of the struct v points to from the player attributes,
+// using the same field mapping as EncodePlayerAttributes. Fields without a matching attribute are left unchanged.
+// A DOUBLE attribute decoded into an int or uint field must hold a whole number within the range of the field.
+// Returns a common.ValidationException error naming the attribute if an attribute does not match its field type.
+func DecodePlayerAttributes(attrs map[string]AttributeValue, v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return playerAttributeError("", "cannot decode into %T, a non-nil pointer to a struct is required", v)
+	}
+	return walkTaggedFields(value.Elem(), playerAttributeTag, func(field taggedField) error {
+		attr, ok := attrs[field.name]
+		if !ok {
+			return nil
+		}
+		fieldValue := field.value
+		if fieldValue.Kind() == reflect.Pointer {
+			if fieldValue.IsNil() {
+				fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if err := decodePlayerAttribute(attr, fieldValue); err != nil {
+			return playerAttributeError(field.name, "field %s: %s", field.path, err)
+		}
+		return nil
+	})
+}
+
+func encodePlayerAttribute(value reflect.Value) (AttributeValue, error) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return MakeAttributeValue(float64(value.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return MakeAttributeValue(float64(value.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return MakeAttributeValue(value.Float()), nil
+	case reflect.String:
+		return MakeAttributeValue(value.String()), nil
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.String {
+			sl := make([]string, value.Len())
+			for i := range sl {
+				sl[i] = value.Index(i).String()
+			}
+			return MakeAttributeValue(sl), nil
+		}
+	case reflect.Map:
+		if isStringDoubleMap(value.Type()) {
+			sdm := make(map[string]float64, value.Len())
+			iter := value.MapRange()
+			for iter.Next() {
+				sdm[iter.Key().String()] = iter.Value().Float()
+			}
+			return MakeAttributeValue(sdm), nil
+		}
+	}
+	return AttributeValue{}, fmt.Errorf("unsupported type %s", value.Type())
+}
+
+func decodePlayerAttribute(attr AttributeValue, value reflect.Value) error {
+	attrType := None
+	if attr.AttrType != nil {
+		attrType = *attr.AttrType
+	}
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if attrType != Double {
+			break
+		}
+		if attr.N != math.Trunc(attr.N) || attr.N < math.MinInt64 || attr.N >= math.MaxInt64 ||
+			value.OverflowInt(int64(attr.N)) {
+			return fmt.Errorf("%v does not fit in %s", attr.N, value.Type())
+		}
+		value.SetInt(int64(attr.N))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if attrType != Double {
+			break
+		}
+		if attr.N != math.Trunc(attr.N) || attr.N < 0 || attr.N >= math.MaxUint64 || value.OverflowUint(uint64(attr.N)) {
+			return fmt.Errorf("%v does not fit in %s", attr.N, value.Type())
+		}
+		value.SetUint(uint64(attr.N))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if attrType != Double {
+			break
+		}
+		value.SetFloat(attr.N)
+		return nil
+	case reflect.String:
+		if attrType != String {
+			break
+		}
+		value.SetString(attr.S)
+		return nil
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", value.Type())
+		}
+		if attrType != StringList {
+			break
+		}
+		sl := reflect.MakeSlice(value.Type(), len(attr.SL), len(attr.SL))
+		for i, s := range attr.SL {
+			sl.Index(i).SetString(s)
+		}
+		value.Set(sl)
+		return nil
+	case reflect.Map:
+		if !isStringDoubleMap(value.Type()) {
+			return fmt.Errorf("unsupported type %s", value.Type())
+		}
+		if attrType != StringDoubleMap {
+			break
+		}
+		sdm := reflect.MakeMapWithSize(value.Type(), len(attr.SDM))
+		for k, n := range attr.SDM {
+			sdm.SetMapIndex(reflect.ValueOf(k).Convert(value.Type().Key()), reflect.ValueOf(n).Convert(value.Type().Elem()))
+		}
+		value.Set(sdm)
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return fmt.Errorf("cannot decode %s attribute into %s", attrType.String(), value.Type())
+}
+
+func isStringDoubleMap(t reflect.Type) bool {
+	return t.Key().Kind() == reflect.String && (t.Elem().Kind() == reflect.Float64 || t.Elem().Kind() == reflect.Float32)
+}
+
+func playerAttributeError(name, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if name != "" {
+		msg = fmt.Sprintf("Player attribute %s: %s", name, msg)
+	}
+	return common.NewGameLiftError(common.ValidationException, "", msg)
+}
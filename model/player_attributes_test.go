@@ -0,0 +1,129 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testPlayerStats struct {
+	Wins   uint16  `gamelift:"wins"`
+	Rating float32 `gamelift:"rating"`
+}
+
+type testPlayerProfile struct {
+	testPlayerStats
+	Skill      int                `gamelift:"skill"`
+	GameMode   string             `gamelift:"gameMode,omitempty"`
+	Characters []string           `gamelift:"characters"`
+	Latency    map[string]float64 `gamelift:"latency"`
+	Level      *int               `gamelift:"level"`
+	Region     string
+	Session    string `gamelift:"-"`
+	internal   int
+}
+
+func TestEncodePlayerAttributes(t *testing.T) {
+	// GIVEN
+	profile := testPlayerProfile{
+		testPlayerStats: testPlayerStats{Wins: 3, Rating: 1.5},
+		Skill:           42,
+		Characters:      []string{"knight", "mage"},
+		Latency:         map[string]float64{"us-west-2": 20},
+		Region:          "us-west-2",
+		Session:         "secret",
+		internal:        1,
+	}
+
+	// WHEN
+	attrs, err := EncodePlayerAttributes(&profile)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]AttributeValue{
+		"wins":       MakeAttributeValue(float64(3)),
+		"rating":     MakeAttributeValue(1.5),
+		"skill":      MakeAttributeValue(float64(42)),
+		"characters": MakeAttributeValue([]string{"knight", "mage"}),
+		"latency":    MakeAttributeValue(map[string]float64{"us-west-2": 20}),
+		"Region":     MakeAttributeValue("us-west-2"),
+	}
+	if !reflect.DeepEqual(expected, attrs) {
+		t.Fatalf("Unexpected attributes: %v", attrs)
+	}
+}
+
+func TestEncodePlayerAttributes_UnsupportedType_ReturnError(t *testing.T) {
+	// GIVEN
+	profile := struct {
+		Scores []int `gamelift:"scores"`
+	}{Scores: []int{1}}
+
+	// WHEN
+	_, err := EncodePlayerAttributes(profile)
+
+	// THEN
+	if err == nil || !strings.Contains(err.Error(), "scores") || !strings.Contains(err.Error(), "[]int") {
+		t.Fatalf("Expected unsupported type error but got %v", err)
+	}
+}
+
+func TestDecodePlayerAttributes_RoundTrip(t *testing.T) {
+	// GIVEN
+	level := 7
+	profile := testPlayerProfile{
+		testPlayerStats: testPlayerStats{Wins: 3, Rating: 1.5},
+		Skill:           -42,
+		GameMode:        "deathmatch",
+		Characters:      []string{"knight"},
+		Latency:         map[string]float64{"us-west-2": 20},
+		Level:           &level,
+		Region:          "us-west-2",
+	}
+	attrs, err := EncodePlayerAttributes(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	var decoded testPlayerProfile
+	err = DecodePlayerAttributes(attrs, &decoded)
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(profile, decoded) {
+		t.Fatalf("Expected %+v but got %+v", profile, decoded)
+	}
+}
+
+func TestDecodePlayerAttributes_ReturnError(t *testing.T) {
+	tests := []struct {
+		attrs    map[string]AttributeValue
+		expected string
+	}{
+		{map[string]AttributeValue{"skill": MakeAttributeValue("high")}, "cannot decode STRING attribute into int"},
+		{map[string]AttributeValue{"skill": MakeAttributeValue(1.5)}, "1.5 does not fit in int"},
+		{map[string]AttributeValue{"wins": MakeAttributeValue(float64(-1))}, "-1 does not fit in uint16"},
+		{map[string]AttributeValue{"wins": MakeAttributeValue(float64(70000))}, "70000 does not fit in uint16"},
+		{map[string]AttributeValue{"characters": MakeAttributeValue(float64(1))}, "cannot decode DOUBLE attribute into []string"},
+	}
+	for _, test := range tests {
+		var profile testPlayerProfile
+		err := DecodePlayerAttributes(test.attrs, &profile)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Expected error containing %q but got %v", test.expected, err)
+		}
+	}
+	if err := DecodePlayerAttributes(nil, testPlayerProfile{}); err == nil {
+		t.Error("Expected error decoding into a non-pointer")
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// ProcessState - lifecycle state of the server process, as tracked by the server SDK.
+//
+// A server process moves through the states in the following order:
+//
+//	ProcessInitialized -> ProcessReady -> ProcessSessionAssigned -> ProcessSessionActive -> ProcessTerminating -> ProcessEnded
+//
+// ProcessTerminating can be entered from any state before it, and ProcessEnded from any state.
+type ProcessState int
+
+// Possible ProcessState values
+const (
+	// ProcessInitialized - the server SDK is initialized, ProcessReady has not been called yet.
+	ProcessInitialized ProcessState = iota
+	// ProcessReady - the server process is ready to host a game session.
+	ProcessReady
+	// ProcessSessionAssigned - a game session was assigned to the server process but is not activated yet.
+	ProcessSessionAssigned
+	// ProcessSessionActive - the game session was activated and can accept players.
+	ProcessSessionActive
+	// ProcessTerminating - Amazon GameLift Servers requested the server process to shut down.
+	ProcessTerminating
+	// ProcessEnded - ProcessEnding was reported to Amazon GameLift Servers.
+	ProcessEnded
+)
+
+var processStateStrs = []string{
+	"INITIALIZED",
+	"READY",
+	"SESSION_ASSIGNED",
+	"SESSION_ACTIVE",
+	"TERMINATING",
+	"ENDED",
+}
+
+// processStateTransitions - the states that can be reached from each state.
+var processStateTransitions = map[ProcessState][]ProcessState{
+	ProcessInitialized:     {ProcessReady, ProcessTerminating, ProcessEnded},
+	ProcessReady:           {ProcessSessionAssigned, ProcessTerminating, ProcessEnded},
+	ProcessSessionAssigned: {ProcessSessionActive, ProcessTerminating, ProcessEnded},
+	ProcessSessionActive:   {ProcessTerminating, ProcessEnded},
+	ProcessTerminating:     {ProcessEnded},
+}
+
+func (p *ProcessState) String() string {
+	n := int(*p)
+	if n < 0 || n >= len(processStateStrs) {
+		n = 0
+	}
+	return processStateStrs[n]
+}
+
+func (p *ProcessState) ToProcessState(s string) {
+	for i := range processStateStrs {
+		if processStateStrs[i] == s {
+			*p = ProcessState(i)
+			return
+		}
+	}
+	*p = ProcessInitialized
+}
+
+// CanTransitionTo - reports whether the server process is allowed to move from this state to next.
+func (p *ProcessState) CanTransitionTo(next ProcessState) bool {
+	for _, allowed := range processStateTransitions[*p] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ProcessState) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(p.String())), nil
+}
+
+func (p *ProcessState) UnmarshalJSON(data []byte) error {
+	origin, err := strconv.Unquote(string(data))
+	if err != nil {
+		return err
+	}
+	p.ToProcessState(origin)
+	return nil
+}
+
+// ProcessStateTransition - a single change of the ProcessState of the server process.
+type ProcessStateTransition struct {
+	From ProcessState `json:"From"`
+	To   ProcessState `json:"To"`
+	// Trigger - name of the server SDK call or service message that caused the transition, for example "ProcessReady".
+	Trigger string    `json:"Trigger"`
+	Time    time.Time `json:"Time"`
+}
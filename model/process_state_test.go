@@ -0,0 +1,68 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProcessState_MarshalJSON(t *testing.T) {
+	cases := map[ProcessState]string{
+		ProcessInitialized:     "\"INITIALIZED\"",
+		ProcessReady:           "\"READY\"",
+		ProcessSessionAssigned: "\"SESSION_ASSIGNED\"",
+		ProcessSessionActive:   "\"SESSION_ACTIVE\"",
+		ProcessTerminating:     "\"TERMINATING\"",
+		ProcessEnded:           "\"ENDED\"",
+	}
+
+	for origin, expected := range cases {
+		data, err := json.Marshal(&origin)
+		if err != nil {
+			t.Fatalf("json marshal ProcessState error: %s", err.Error())
+		}
+		if expected != string(data) {
+			t.Errorf("expect %s but get %s", expected, data)
+		}
+
+		var state ProcessState
+		if err := json.Unmarshal(data, &state); err != nil {
+			t.Fatalf("json unmarshal ProcessState error: %s", err.Error())
+		}
+		if state != origin {
+			t.Errorf("expect %v but get %v", origin, state)
+		}
+	}
+}
+
+func TestProcessState_CanTransitionTo(t *testing.T) {
+	cases := []struct {
+		from     ProcessState
+		to       ProcessState
+		expected bool
+	}{
+		{ProcessInitialized, ProcessReady, true},
+		{ProcessReady, ProcessSessionAssigned, true},
+		{ProcessSessionAssigned, ProcessSessionActive, true},
+		{ProcessSessionActive, ProcessTerminating, true},
+		{ProcessTerminating, ProcessEnded, true},
+		{ProcessInitialized, ProcessEnded, true},
+		{ProcessInitialized, ProcessSessionActive, false},
+		{ProcessReady, ProcessReady, false},
+		{ProcessSessionActive, ProcessSessionActive, false},
+		{ProcessSessionActive, ProcessSessionAssigned, false},
+		{ProcessTerminating, ProcessSessionActive, false},
+		{ProcessEnded, ProcessEnded, false},
+		{ProcessEnded, ProcessReady, false},
+	}
+
+	for _, c := range cases {
+		if actual := c.from.CanTransitionTo(c.to); actual != c.expected {
+			t.Errorf("%s -> %s: expect %v but get %v", c.from.String(), c.to.String(), c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package ruleset validates players against the player attributes and teams of a FlexMatch rule set,
+// so that an invalid match backfill request fails locally instead of with BACKFILL_FAILED later on.
+//
+//	rules, err := ruleset.Parse(ruleSetBody)
+//	if err != nil {
+//		return err
+//	}
+//	if err := rules.ValidatePlayers(req.Players); err != nil {
+//		return err
+//	}
+//	res, err := server.StartMatchBackfill(req)
+package ruleset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// AttributeType - type of a player attribute as declared in a rule set.
+type AttributeType string
+
+// Possible AttributeType values
+const (
+	AttributeTypeString          AttributeType = "string"
+	AttributeTypeNumber          AttributeType = "number"
+	AttributeTypeStringList      AttributeType = "string_list"
+	AttributeTypeStringNumberMap AttributeType = "string_number_map"
+)
+
+// attributeTypes - the model.AttributeValue type matching each AttributeType.
+var attributeTypes = map[AttributeType]model.AttributeValue{
+	AttributeTypeString:          model.MakeAttributeValue(""),
+	AttributeTypeNumber:          model.MakeAttributeValue(float64(0)),
+	AttributeTypeStringList:      model.MakeAttributeValue([]string(nil)),
+	AttributeTypeStringNumberMap: model.MakeAttributeValue(map[string]float64(nil)),
+}
+
+// PlayerAttribute - a player attribute declared in the playerAttributes of a rule set.
+type PlayerAttribute struct {
+	Name string        `json:"name"`
+	Type AttributeType `json:"type"`
+	// Default - the value FlexMatch uses when a player does not have the attribute, if any.
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// HasDefault - reports whether the attribute can be omitted.
+func (a *PlayerAttribute) HasDefault() bool {
+	return len(a.Default) > 0 && string(a.Default) != "null"
+}
+
+// Team - a team declared in the teams of a rule set.
+type Team struct {
+	Name       string `json:"name"`
+	MinPlayers int    `json:"minPlayers"`
+	MaxPlayers int    `json:"maxPlayers"`
+	// Quantity - number of teams with this definition. Defaults to 1.
+	// FlexMatch names the teams <name>_1, <name>_2, ... when Quantity is greater than 1.
+	Quantity int `json:"quantity,omitempty"`
+}
+
+// TeamNames - returns the names FlexMatch gives to the teams with this definition.
+func (t *Team) TeamNames() []string {
+	if t.Quantity <= 1 {
+		return []string{t.Name}
+	}
+	names := make([]string, t.Quantity)
+	for i := range names {
+		names[i] = t.Name + "_" + strconv.Itoa(i+1)
+	}
+	return names
+}
+
+// RuleSet - the player attributes and teams of a FlexMatch rule set. Other rule set properties are ignored.
+type RuleSet struct {
+	Name                string            `json:"name"`
+	RuleLanguageVersion string            `json:"ruleLanguageVersion"`
+	PlayerAttributes    []PlayerAttribute `json:"playerAttributes"`
+	Teams               []Team            `json:"teams"`
+
+	attributes map[string]*PlayerAttribute
+	teams      map[string]*Team
+}
+
+// Parse - parses a FlexMatch rule set JSON document.
+// Returns a common.ValidationException error if the document is not valid JSON, if an attribute has an unknown type,
+// or if a team is declared twice or has invalid size bounds.
+func Parse(data []byte) (*RuleSet, error) {
+	var rules RuleSet
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, common.NewGameLiftError(common.ValidationException, "", fmt.Sprintf("Invalid rule set: %s", err))
+	}
+	if err := rules.init(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+func (r *RuleSet) init() error {
+	r.attributes = make(map[string]*PlayerAttribute, len(r.PlayerAttributes))
+	for i := range r.PlayerAttributes {
+		attribute := &r.PlayerAttributes[i]
+		if _, ok := attributeTypes[attribute.Type]; !ok {
+			return ruleSetError("player attribute %s has unknown type %q", attribute.Name, attribute.Type)
+		}
+		if _, ok := r.attributes[attribute.Name]; ok {
+			return ruleSetError("player attribute %s is declared twice", attribute.Name)
+		}
+		r.attributes[attribute.Name] = attribute
+	}
+	r.teams = make(map[string]*Team, len(r.Teams))
+	for i := range r.Teams {
+		team := &r.Teams[i]
+		if team.MinPlayers < 0 || team.MaxPlayers < 1 || team.MinPlayers > team.MaxPlayers {
+			return ruleSetError("team %s has invalid size bounds %d-%d", team.Name, team.MinPlayers, team.MaxPlayers)
+		}
+		for _, name := range team.TeamNames() {
+			if _, ok := r.teams[name]; ok {
+				return ruleSetError("team %s is declared twice", name)
+			}
+			r.teams[name] = team
+		}
+	}
+	return nil
+}
+
+// Attribute - returns the declared player attribute with the specified name, or nil.
+func (r *RuleSet) Attribute(name string) *PlayerAttribute {
+	return r.attributes[name]
+}
+
+// Team - returns the definition of the team with the specified name, or nil.
+// Teams with a Quantity greater than 1 are looked up by their numbered name, such as red_1.
+func (r *RuleSet) Team(name string) *Team {
+	return r.teams[name]
+}
+
+// ValidatePlayer - checks that the player has every declared attribute without a default, with the declared type.
+// Attributes that are not declared by the rule set are ignored by FlexMatch and are not reported.
+func (r *RuleSet) ValidatePlayer(player *model.Player) error {
+	var errs []error
+	for i := range r.PlayerAttributes {
+		attribute := &r.PlayerAttributes[i]
+		value, ok := player.PlayerAttributes[attribute.Name]
+		if !ok {
+			if !attribute.HasDefault() {
+				errs = append(errs, playerError(player, "attribute %s is missing", attribute.Name))
+			}
+			continue
+		}
+		expected := attributeTypes[attribute.Type]
+		if value.AttrType == nil || value.GetAttrType() != expected.GetAttrType() {
+			errs = append(errs, playerError(player, "attribute %s must be %s but is %s",
+				attribute.Name, expected.AttrType.String(), typeName(&value)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidatePlayers - validates each player with ValidatePlayer, and checks that every player is assigned to a declared
+// team and that no team has more than MaxPlayers players.
+// Teams with fewer than MinPlayers players are valid, as filling them is the purpose of a match backfill,
+// see MissingPlayers.
+// All violations are returned, joined with errors.Join; each one is a common.ValidationException error.
+func (r *RuleSet) ValidatePlayers(players []model.Player) error {
+	var errs []error
+	for i := range players {
+		player := &players[i]
+		if err := r.ValidatePlayer(player); err != nil {
+			errs = append(errs, err)
+		}
+		if r.Team(player.Team) == nil {
+			errs = append(errs, playerError(player, "team %q is not declared by the rule set", player.Team))
+		}
+	}
+	sizes := teamSizes(players)
+	for _, name := range r.teamNames() {
+		if team := r.teams[name]; sizes[name] > team.MaxPlayers {
+			errs = append(errs, common.NewGameLiftError(common.ValidationException, "",
+				fmt.Sprintf("Team %s has %d players but allows at most %d.", name, sizes[name], team.MaxPlayers)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MissingPlayers - returns the number of players each team needs to reach its MinPlayers.
+// Teams that have enough players are not listed.
+func (r *RuleSet) MissingPlayers(players []model.Player) map[string]int {
+	sizes := teamSizes(players)
+	missing := make(map[string]int)
+	for _, name := range r.teamNames() {
+		if n := r.teams[name].MinPlayers - sizes[name]; n > 0 {
+			missing[name] = n
+		}
+	}
+	return missing
+}
+
+// teamNames - returns the names of all teams, in declaration order.
+func (r *RuleSet) teamNames() []string {
+	var names []string
+	for i := range r.Teams {
+		names = append(names, r.Teams[i].TeamNames()...)
+	}
+	return names
+}
+
+func teamSizes(players []model.Player) map[string]int {
+	sizes := make(map[string]int)
+	for i := range players {
+		sizes[players[i].Team]++
+	}
+	return sizes
+}
+
+func typeName(value *model.AttributeValue) string {
+	if value.AttrType == nil {
+		return "NONE"
+	}
+	return value.AttrType.String()
+}
+
+func ruleSetError(format string, args ...any) error {
+	return common.NewGameLiftError(common.ValidationException, "", "Invalid rule set: "+fmt.Sprintf(format, args...)+".")
+}
+
+func playerError(player *model.Player, format string, args ...any) error {
+	return common.NewGameLiftError(common.ValidationException, "",
+		fmt.Sprintf("Player %s: ", player.PlayerID)+fmt.Sprintf(format, args...)+".")
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package ruleset_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/ruleset"
+)
+
+const testRuleSet = `{
+	"name": "test",
+	"ruleLanguageVersion": "1.0",
+	"playerAttributes": [
+		{"name": "skill", "type": "number", "default": 10},
+		{"name": "gameMode", "type": "string"},
+		{"name": "characters", "type": "string_list"}
+	],
+	"teams": [
+		{"name": "red", "minPlayers": 2, "maxPlayers": 2},
+		{"name": "blue", "minPlayers": 1, "maxPlayers": 2, "quantity": 2}
+	],
+	"rules": [{"name": "FairTeamSkill", "type": "distance", "measurements": ["avg(teams[*].players.attributes[skill])"]}]
+}`
+
+func newPlayer(id, team string) model.Player {
+	return model.Player{
+		PlayerID: id,
+		Team:     team,
+		PlayerAttributes: map[string]model.AttributeValue{
+			"gameMode":   model.MakeAttributeValue("deathmatch"),
+			"characters": model.MakeAttributeValue([]string{"knight"}),
+		},
+	}
+}
+
+func parse(t *testing.T) *ruleset.RuleSet {
+	t.Helper()
+	rules, err := ruleset.Parse([]byte(testRuleSet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rules
+}
+
+func TestParse(t *testing.T) {
+	// WHEN
+	rules := parse(t)
+
+	// THEN
+	common.AssertEqual(t, 3, len(rules.PlayerAttributes))
+	common.AssertEqual(t, true, rules.Attribute("skill").HasDefault())
+	common.AssertEqual(t, false, rules.Attribute("gameMode").HasDefault())
+	common.AssertEqual(t, "blue", rules.Team("blue_2").Name)
+	if rules.Team("blue") != nil {
+		t.Fatal("Expected numbered team names for a team with quantity 2")
+	}
+}
+
+func TestParse_InvalidRuleSet_ReturnError(t *testing.T) {
+	tests := []string{
+		`{"playerAttributes": [{"name": "skill", "type": "integer"}]}`,
+		`{"teams": [{"name": "red", "minPlayers": 3, "maxPlayers": 2}]}`,
+		`{"teams": [{"name": "red", "maxPlayers": 2}, {"name": "red", "maxPlayers": 2}]}`,
+		`{"teams": `,
+	}
+	for _, test := range tests {
+		if _, err := ruleset.Parse([]byte(test)); err == nil {
+			t.Errorf("Expected error parsing %s", test)
+		}
+	}
+}
+
+func TestRuleSet_ValidatePlayers(t *testing.T) {
+	// GIVEN
+	rules := parse(t)
+	players := []model.Player{newPlayer("player-1", "red"), newPlayer("player-2", "blue_1")}
+
+	// WHEN
+	err := rules.ValidatePlayers(players)
+
+	// THEN
+	common.AssertEqual(t, nil, err)
+	missing := rules.MissingPlayers(players)
+	common.AssertEqual(t, 2, len(missing))
+	common.AssertEqual(t, 1, missing["red"])
+	common.AssertEqual(t, 1, missing["blue_2"])
+}
+
+func TestRuleSet_ValidatePlayers_ReturnAllViolations(t *testing.T) {
+	// GIVEN
+	rules := parse(t)
+	missingAttribute := newPlayer("player-1", "red")
+	delete(missingAttribute.PlayerAttributes, "gameMode")
+	wrongType := newPlayer("player-2", "red")
+	wrongType.PlayerAttributes["skill"] = model.MakeAttributeValue("high")
+	players := []model.Player{missingAttribute, wrongType, newPlayer("player-3", "red"), newPlayer("player-4", "green")}
+
+	// WHEN
+	err := rules.ValidatePlayers(players)
+
+	// THEN
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	for _, expected := range []string{
+		"Player player-1: attribute gameMode is missing.",
+		"Player player-2: attribute skill must be DOUBLE but is STRING.",
+		`Player player-4: team "green" is not declared by the rule set.`,
+		"Team red has 3 players but allows at most 2.",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in %s", expected, err)
+		}
+	}
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.ValidationException {
+		t.Fatalf("Expected ValidationException error but got %v", err)
+	}
+}
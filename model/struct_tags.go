@@ -0,0 +1,73 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package model
+
+import (
+	"reflect"
+	"strings"
+)
+
+// taggedField - an exported struct field and the name and options of its struct tag.
+type taggedField struct {
+	value   reflect.Value
+	path    string
+	name    string
+	options []string
+}
+
+func (f *taggedField) hasOption(option string) bool {
+	for _, o := range f.options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// optionValue - returns the value of an option written as key=value.
+func (f *taggedField) optionValue(key string) (string, bool) {
+	for _, o := range f.options {
+		if k, v, ok := strings.Cut(o, "="); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// walkTaggedFields - calls fn for every exported field of the struct value, including the fields of embedded
+// structs, with the name and options of its tag. The name defaults to the field name; fields tagged "-" are skipped.
+func walkTaggedFields(value reflect.Value, tag string, fn func(taggedField) error) error {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		tagValue, tagged := structField.Tag.Lookup(tag)
+		if tagValue == "-" {
+			continue
+		}
+		// The exported fields of an embedded struct are promoted, even if the struct type is unexported.
+		if structField.Anonymous && !tagged && structField.Type.Kind() == reflect.Struct {
+			if err := walkTaggedFields(value.Field(i), tag, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if !structField.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(tagValue, ",")
+		if name == "" {
+			name = structField.Name
+		}
+		field := taggedField{value: value.Field(i), path: t.Name() + "." + structField.Name, name: name}
+		if options != "" {
+			field.options = strings.Split(options, ",")
+		}
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -13,6 +13,9 @@ type UpdateGameSession struct {
 	GameSession GameSession `json:"GameSession"`
 	// The reason this update is being supplied.
 	UpdateReason *UpdateReason `json:"UpdateReason,omitempty"`
+	// Differences from the matchmaker data previously received for the game session.
+	// Nil if the matchmaker data of the game session could not be parsed.
+	MatchmakerDataDiff *MatchmakerDataDiff `json:"-"`
 }
 
 func (u UpdateGameSession) WithReason(reason UpdateReason) UpdateGameSession {
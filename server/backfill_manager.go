@@ -0,0 +1,374 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+// BackfillRetryPolicy - controls how BackfillManager retries match backfill requests
+// that end with BACKFILL_FAILED or BACKFILL_TIMED_OUT, or that could not be started because of a retryable error,
+// see BackfillManager.Start. A retry that cannot be started counts as a failed attempt.
+type BackfillRetryPolicy struct {
+	// MaxRetries - number of times a match backfill request is retried. Zero disables retries.
+	MaxRetries int
+
+	// Delay - time to wait before the first retry. Each further retry waits twice as long as the previous one.
+	// Defaults to 5 seconds.
+	Delay time.Duration
+
+	// MaxDelay - the longest time to wait before a retry. Defaults to 1 minute.
+	MaxDelay time.Duration
+}
+
+// retryDelay - returns the time to wait before the specified retry, starting at 1.
+func (p *BackfillRetryPolicy) retryDelay(retry int) time.Duration {
+	delay := p.Delay
+	if delay <= 0 {
+		delay = common.BackfillRetryDelayDefault
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = common.BackfillRetryMaxDelayDefault
+	}
+	for i := 1; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// BackfillStatus - the match backfill tracked by BackfillManager.
+type BackfillStatus struct {
+	State model.BackfillState
+	// TicketID - the ticket of the active match backfill request, or of the last one.
+	TicketID string
+	// Attempts - number of times the current match backfill request was started, including retries.
+	Attempts int
+	// LastUpdateReason - reason of the last update received for the ticket, if any.
+	LastUpdateReason *model.UpdateReason
+	// LastError - the error of the last failed StartMatchBackfill call, if any.
+	LastError error
+	// NextRetry - the time of the next retry, when State is BackfillRetrying.
+	NextRetry time.Time
+}
+
+// backfillClient - the calls used by BackfillManager, implemented by Client.
+type backfillClient interface {
+	StartMatchBackfillContext(ctx context.Context, req request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, error)
+	StopMatchBackfillContext(ctx context.Context, req request.StopMatchBackfillRequest) error
+}
+
+// defaultBackfillClient - calls the default client, see InitSDK.
+type defaultBackfillClient struct{}
+
+func (defaultBackfillClient) StartMatchBackfillContext(
+	ctx context.Context,
+	req request.StartMatchBackfillRequest,
+) (result.StartMatchBackfillResult, error) {
+	return StartMatchBackfillContext(ctx, req)
+}
+
+func (defaultBackfillClient) StopMatchBackfillContext(ctx context.Context, req request.StopMatchBackfillRequest) error {
+	return StopMatchBackfillContext(ctx, req)
+}
+
+// BackfillManager - tracks the match backfill of the game session, as only one match backfill request
+// can be active per server process.
+//
+// Start stops the active match backfill request, if any, before starting a new one.
+// Failed and timed out requests are retried according to the BackfillRetryPolicy.
+// The manager learns the outcome of its tickets from the game session updates received by its client,
+// before they are passed to ProcessParameters.OnUpdateGameSession. Close the manager once it is no longer used:
+//
+//	backfill := client.NewBackfillManager(server.BackfillRetryPolicy{MaxRetries: 3})
+//	defer backfill.Close()
+//	...
+//	ticketID, err := backfill.Start(ctx, request.NewStartMatchBackfill(gameSessionArn, configArn, players))
+type BackfillManager struct {
+	client backfillClient
+	policy BackfillRetryPolicy
+	lg     log.ILogger
+	// callTimeout - bounds the calls of a retry, which has no caller context.
+	callTimeout time.Duration
+	// unsubscribe - stops passing the game session updates of the client to HandleUpdateGameSession, if set.
+	unsubscribe func()
+
+	// mtx - guards the fields below. It is not held during the calls to Amazon GameLift Servers.
+	mtx     sync.Mutex
+	status  BackfillStatus
+	request request.StartMatchBackfillRequest
+	// retryTimer - pending retry, if any.
+	retryTimer *time.Timer
+	// generation - incremented whenever the tracked request changes, so that the result of a stale call
+	// or retry is discarded.
+	generation int
+}
+
+// NewBackfillManager - creates a BackfillManager for the default client, see InitSDK.
+// If it is created before InitSDK, it does not receive the game session updates;
+// pass them to HandleUpdateGameSession instead.
+func NewBackfillManager(policy BackfillRetryPolicy) *BackfillManager {
+	if defaultClient != nil {
+		return defaultClient.NewBackfillManager(policy)
+	}
+	return newBackfillManager(defaultBackfillClient{}, policy, nil,
+		common.GetEnvDurationOrDefault(common.ServiceCallTimeout, common.ServiceCallTimeoutDefault, lg))
+}
+
+func newBackfillManager(
+	client backfillClient,
+	policy BackfillRetryPolicy,
+	l log.ILogger,
+	callTimeout time.Duration,
+) *BackfillManager {
+	return &BackfillManager{client: client, policy: policy, lg: l, callTimeout: callTimeout}
+}
+
+func (m *BackfillManager) logger() log.ILogger {
+	if m.lg != nil {
+		return m.lg
+	}
+	return lg
+}
+
+// Start - stops the active match backfill request, if any, and starts the specified one.
+// The request is retried according to the BackfillRetryPolicy if its ticket fails or times out.
+// Returns the ticket ID of the started request.
+//
+// If the request cannot be started because the call timed out, was throttled or failed in the service,
+// the error is returned and a retry is scheduled; Status reports BackfillRetrying until then.
+// Other errors, such as an invalid request, are not retried.
+func (m *BackfillManager) Start(ctx context.Context, req request.StartMatchBackfillRequest) (string, error) {
+	m.mtx.Lock()
+	stopReq, stopping := m.stopLocked()
+	m.request = req
+	m.status = BackfillStatus{}
+	startReq, generation := m.beginAttemptLocked()
+	m.mtx.Unlock()
+
+	if stopping {
+		// A ticket that cannot be stopped has usually ended already, so the new request is started anyway.
+		_ = m.stopTicket(ctx, stopReq)
+	}
+	return m.attempt(ctx, startReq, generation, func(err error) {
+		if !isRetryableBackfillError(err) {
+			m.status.State = model.BackfillIdle
+			return
+		}
+		m.logger().Warnf("Failed to start match backfill, retrying: %s", err)
+		m.retryLocked()
+	})
+}
+
+// Stop - stops the active match backfill request and cancels a pending retry.
+func (m *BackfillManager) Stop(ctx context.Context) error {
+	m.mtx.Lock()
+	req, stopping := m.stopLocked()
+	m.mtx.Unlock()
+	if !stopping {
+		return nil
+	}
+	return m.stopTicket(ctx, req)
+}
+
+// Status - returns the tracked match backfill.
+func (m *BackfillManager) Status() BackfillStatus {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.status
+}
+
+// Close - cancels a pending retry and stops receiving game session updates, without stopping the active
+// match backfill request.
+func (m *BackfillManager) Close() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.unsubscribe != nil {
+		m.unsubscribe()
+		m.unsubscribe = nil
+	}
+	m.cancelRetryLocked()
+	if m.status.State == model.BackfillRetrying {
+		m.status.State = model.BackfillIdle
+	}
+}
+
+// HandleUpdateGameSession - updates the tracked match backfill with the outcome of its ticket.
+// It is called with the game session updates of the client, so calling it is only needed for a manager
+// created before InitSDK. Updates for other tickets are ignored.
+//   - MATCHMAKING_DATA_UPDATED and BACKFILL_CANCELLED end the match backfill.
+//   - BACKFILL_FAILED and BACKFILL_TIMED_OUT schedule a retry, or give up if all retries were used.
+func (m *BackfillManager) HandleUpdateGameSession(update model.UpdateGameSession) {
+	if update.UpdateReason == nil {
+		return
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.status.State != model.BackfillActive || update.BackfillTicketID != m.status.TicketID {
+		return
+	}
+	reason := *update.UpdateReason
+	m.status.LastUpdateReason = &reason
+	switch reason {
+	case model.MatchmakingDataUpdated, model.BackfillCancelled:
+		m.logger().Debugf("Match backfill %s ended with %s", m.status.TicketID, reason.String())
+		m.status.State = model.BackfillIdle
+		m.generation++
+	case model.BackfillFailed, model.BackfillTimedOut:
+		m.logger().Warnf("Match backfill %s ended with %s", m.status.TicketID, reason.String())
+		m.retryLocked()
+	}
+}
+
+// beginAttemptLocked - returns the tracked request as a new attempt and the generation it belongs to.
+// Must be called with m.mtx held.
+func (m *BackfillManager) beginAttemptLocked() (request.StartMatchBackfillRequest, int) {
+	req := m.request
+	req.Message = message.NewMessage(message.StartMatchBackfill)
+	if m.status.Attempts > 0 {
+		// A retry asks for a new ticket rather than reusing the one that failed.
+		req.TicketID = ""
+	}
+	m.status.Attempts++
+	m.generation++
+	return req, m.generation
+}
+
+// attempt - starts an attempt of the tracked request and records its outcome, unless the tracked request
+// changed meanwhile; a ticket started for a request that is no longer tracked is stopped.
+// If the attempt fails, onError is called with m.mtx held.
+func (m *BackfillManager) attempt(
+	ctx context.Context,
+	req request.StartMatchBackfillRequest,
+	generation int,
+	onError func(err error),
+) (string, error) {
+	res, err := m.client.StartMatchBackfillContext(ctx, req)
+	m.mtx.Lock()
+	if m.generation != generation {
+		m.mtx.Unlock()
+		if err == nil {
+			m.logger().Debugf("Match backfill %s is no longer tracked, stopping it", res.TicketID)
+			_ = m.stopTicket(ctx, newStopMatchBackfill(req, res.TicketID))
+		}
+		return "", common.NewGameLiftError(common.ConflictException, "",
+			"The match backfill was stopped or replaced while it was starting.")
+	}
+	defer m.mtx.Unlock()
+	if err != nil {
+		m.status.LastError = err
+		onError(err)
+		return "", err
+	}
+	m.status.State = model.BackfillActive
+	m.status.TicketID = res.TicketID
+	m.status.LastError = nil
+	m.status.NextRetry = time.Time{}
+	m.logger().Debugf("Match backfill %s started, attempt %d", res.TicketID, m.status.Attempts)
+	return res.TicketID, nil
+}
+
+// retryLocked - schedules a retry of the tracked request or gives up. Must be called with m.mtx held.
+func (m *BackfillManager) retryLocked() {
+	retry := m.status.Attempts
+	if retry > m.policy.MaxRetries {
+		m.logger().Warnf("Match backfill gave up after %d attempts", m.status.Attempts)
+		m.status.State = model.BackfillGaveUp
+		m.generation++
+		return
+	}
+	delay := m.policy.retryDelay(retry)
+	m.status.State = model.BackfillRetrying
+	m.status.NextRetry = time.Now().Add(delay)
+	m.generation++
+	generation := m.generation
+	m.retryTimer = time.AfterFunc(delay, func() { m.runRetry(generation) })
+}
+
+// runRetry - starts the retry scheduled for the generation, unless the tracked request changed meanwhile.
+// The retry is bounded by callTimeout. A retry that cannot be started counts as a failed attempt.
+func (m *BackfillManager) runRetry(generation int) {
+	m.mtx.Lock()
+	if m.generation != generation {
+		m.mtx.Unlock()
+		return
+	}
+	m.retryTimer = nil
+	req, generation := m.beginAttemptLocked()
+	m.mtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.callTimeout)
+	defer cancel()
+	_, _ = m.attempt(ctx, req, generation, func(err error) {
+		m.logger().Warnf("Failed to retry match backfill: %s", err)
+		m.retryLocked()
+	})
+}
+
+// stopLocked - stops tracking the active match backfill request and cancels a pending retry.
+// Returns the request that stops the active ticket, if any. Must be called with m.mtx held.
+func (m *BackfillManager) stopLocked() (request.StopMatchBackfillRequest, bool) {
+	m.cancelRetryLocked()
+	active := m.status.State == model.BackfillActive
+	m.status.State = model.BackfillIdle
+	if !active {
+		return request.StopMatchBackfillRequest{}, false
+	}
+	return newStopMatchBackfill(m.request, m.status.TicketID), true
+}
+
+// isRetryableBackfillError - reports whether a failed StartMatchBackfill call may succeed if sent again.
+// A call cancelled by its caller is not retried.
+func isRetryableBackfillError(err error) bool {
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch gameLiftErr.ErrorType {
+	case common.ServiceCallFailed, common.TooManyRequestsException, common.InternalServiceException,
+		common.WebsocketRetriableSendMessageFailure:
+		return true
+	default:
+		return false
+	}
+}
+
+// newStopMatchBackfill - returns the request that stops the ticket started for req.
+func newStopMatchBackfill(req request.StartMatchBackfillRequest, ticketID string) request.StopMatchBackfillRequest {
+	stopReq := request.NewStopMatchBackfill()
+	stopReq.TicketID = ticketID
+	stopReq.GameSessionArn = req.GameSessionArn
+	stopReq.MatchmakingConfigurationArn = req.MatchmakingConfigurationArn
+	return stopReq
+}
+
+// stopTicket - stops a match backfill ticket. Must be called without m.mtx held.
+func (m *BackfillManager) stopTicket(ctx context.Context, req request.StopMatchBackfillRequest) error {
+	if err := m.client.StopMatchBackfillContext(ctx, req); err != nil {
+		m.logger().Warnf("Failed to stop match backfill %s: %s", req.TicketID, err)
+		return err
+	}
+	m.logger().Debugf("Match backfill %s stopped", req.TicketID)
+	return nil
+}
+
+func (m *BackfillManager) cancelRetryLocked() {
+	if m.retryTimer != nil {
+		m.retryTimer.Stop()
+		m.retryTimer = nil
+	}
+	m.generation++
+}
@@ -0,0 +1,332 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+)
+
+const testBackfillConfigArn = "arn:aws:gamelift:us-west-2:123456789012:matchmakingconfiguration/test"
+
+// fakeBackfillClient - records the match backfill calls and issues sequential ticket IDs.
+type fakeBackfillClient struct {
+	mtx      sync.Mutex
+	started  []request.StartMatchBackfillRequest
+	stopped  []request.StopMatchBackfillRequest
+	startErr error
+	startCh  chan string
+	// deadlines - whether the context of each start call had a deadline.
+	deadlines []bool
+	// entered and release, if set, hold each start call until release is closed.
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newFakeBackfillClient() *fakeBackfillClient {
+	return &fakeBackfillClient{startCh: make(chan string, 10)}
+}
+
+func (f *fakeBackfillClient) StartMatchBackfillContext(
+	ctx context.Context,
+	req request.StartMatchBackfillRequest,
+) (result.StartMatchBackfillResult, error) {
+	if f.entered != nil {
+		f.entered <- struct{}{}
+		<-f.release
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.started = append(f.started, req)
+	_, hasDeadline := ctx.Deadline()
+	f.deadlines = append(f.deadlines, hasDeadline)
+	if f.startErr != nil {
+		return result.StartMatchBackfillResult{}, f.startErr
+	}
+	ticketID := req.TicketID
+	if ticketID == "" {
+		ticketID = fmt.Sprintf("ticket-%d", len(f.started))
+	}
+	f.startCh <- ticketID
+	return result.StartMatchBackfillResult{TicketID: ticketID}, nil
+}
+
+func (f *fakeBackfillClient) StopMatchBackfillContext(_ context.Context, req request.StopMatchBackfillRequest) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.stopped = append(f.stopped, req)
+	return nil
+}
+
+func newTestBackfillRequest() request.StartMatchBackfillRequest {
+	return request.NewStartMatchBackfill("arn:aws:gamelift:us-west-2::gamesession/fleet-1/gsess-1", testBackfillConfigArn,
+		[]model.Player{{PlayerID: "player-1"}})
+}
+
+func backfillUpdate(ticketID string, reason model.UpdateReason) model.UpdateGameSession {
+	return model.UpdateGameSession{BackfillTicketID: ticketID}.WithReason(reason)
+}
+
+// waitForBackfillState - waits until the manager reports the state, as a retry is recorded after its call returns.
+func waitForBackfillState(t *testing.T, manager *BackfillManager, state model.BackfillState) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for manager.Status().State != state {
+		if time.Now().After(deadline) {
+			got := manager.Status().State
+			t.Fatalf("Expected backfill state %s, got %s", state.String(), got.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBackfillManager_Start_StopActiveTicket(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	manager := newBackfillManager(client, BackfillRetryPolicy{}, lg, time.Second)
+	first, err := manager.Start(context.Background(), newTestBackfillRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	second, err := manager.Start(context.Background(), newTestBackfillRequest())
+
+	// THEN
+	common.AssertEqual(t, nil, err)
+	common.AssertEqual(t, 1, len(client.stopped))
+	common.AssertEqual(t, first, client.stopped[0].TicketID)
+	common.AssertEqual(t, testBackfillConfigArn, client.stopped[0].MatchmakingConfigurationArn)
+	status := manager.Status()
+	common.AssertEqual(t, model.BackfillActive, status.State)
+	common.AssertEqual(t, second, status.TicketID)
+	common.AssertEqual(t, 1, status.Attempts)
+}
+
+func TestBackfillManager_HandleUpdateGameSession_RetryFailed(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	manager := newBackfillManager(client, BackfillRetryPolicy{MaxRetries: 1, Delay: time.Millisecond}, lg, time.Second)
+	defer manager.Close()
+	first, err := manager.Start(context.Background(), newTestBackfillRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-client.startCh
+
+	// WHEN
+	manager.HandleUpdateGameSession(backfillUpdate(first, model.BackfillTimedOut))
+	var retried string
+	select {
+	case retried = <-client.startCh:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the retry")
+	}
+	waitForBackfillState(t, manager, model.BackfillActive)
+	manager.HandleUpdateGameSession(backfillUpdate(retried, model.MatchmakingDataUpdated))
+
+	// THEN
+	status := manager.Status()
+	common.AssertEqual(t, model.BackfillIdle, status.State)
+	common.AssertEqual(t, retried, status.TicketID)
+	common.AssertEqual(t, 2, status.Attempts)
+	common.AssertEqual(t, model.MatchmakingDataUpdated, *status.LastUpdateReason)
+	if retried == first {
+		t.Fatal("Expected the retry to use a new ticket")
+	}
+	if client.started[0].RequestID == client.started[1].RequestID {
+		t.Fatal("Expected the retry to use a new request ID")
+	}
+}
+
+func TestBackfillManager_Start_DoesNotHoldLockDuringCall(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	client.entered = make(chan struct{}, 1)
+	client.release = make(chan struct{})
+	manager := newBackfillManager(client, BackfillRetryPolicy{}, lg, time.Second)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := manager.Start(context.Background(), newTestBackfillRequest())
+		errCh <- err
+	}()
+	<-client.entered
+
+	// WHEN
+	status := manager.Status()
+	stopErr := manager.Stop(context.Background())
+	close(client.release)
+
+	// THEN
+	common.AssertEqual(t, model.BackfillIdle, status.State)
+	common.AssertEqual(t, nil, stopErr)
+	if err := <-errCh; err == nil {
+		t.Fatal("Expected the start superseded by Stop to fail")
+	}
+	common.AssertEqual(t, model.BackfillIdle, manager.Status().State)
+	common.AssertEqual(t, 1, len(client.stopped))
+	common.AssertEqual(t, <-client.startCh, client.stopped[0].TicketID)
+}
+
+func TestBackfillManager_Retry_BoundedByCallTimeout(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	manager := newBackfillManager(client, BackfillRetryPolicy{MaxRetries: 1, Delay: time.Millisecond}, lg, time.Second)
+	defer manager.Close()
+	ticketID, err := manager.Start(context.Background(), newTestBackfillRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-client.startCh
+
+	// WHEN
+	manager.HandleUpdateGameSession(backfillUpdate(ticketID, model.BackfillFailed))
+	select {
+	case <-client.startCh:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the retry")
+	}
+
+	// THEN
+	client.mtx.Lock()
+	defer client.mtx.Unlock()
+	common.AssertEqual(t, false, client.deadlines[0])
+	common.AssertEqual(t, true, client.deadlines[1])
+}
+
+func TestBackfillManager_HandleUpdateGameSession_GiveUp(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	manager := newBackfillManager(client, BackfillRetryPolicy{}, lg, time.Second)
+	ticketID, err := manager.Start(context.Background(), newTestBackfillRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	manager.HandleUpdateGameSession(backfillUpdate(ticketID, model.BackfillFailed))
+
+	// THEN
+	common.AssertEqual(t, model.BackfillGaveUp, manager.Status().State)
+	common.AssertEqual(t, 1, len(client.started))
+}
+
+func TestBackfillManager_HandleUpdateGameSession_IgnoreOtherTickets(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	manager := newBackfillManager(client, BackfillRetryPolicy{MaxRetries: 1}, lg, time.Second)
+	ticketID, err := manager.Start(context.Background(), newTestBackfillRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	manager.HandleUpdateGameSession(backfillUpdate("old-ticket", model.BackfillCancelled))
+	manager.HandleUpdateGameSession(model.UpdateGameSession{BackfillTicketID: ticketID})
+
+	// THEN
+	status := manager.Status()
+	common.AssertEqual(t, model.BackfillActive, status.State)
+	common.AssertEqual(t, (*model.UpdateReason)(nil), status.LastUpdateReason)
+}
+
+func TestBackfillManager_Start_ReturnError(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	client.startErr = common.NewGameLiftError(common.BadRequestException, "", "")
+	manager := newBackfillManager(client, BackfillRetryPolicy{MaxRetries: 3}, lg, time.Second)
+
+	// WHEN
+	_, err := manager.Start(context.Background(), newTestBackfillRequest())
+
+	// THEN
+	common.AssertEqual(t, client.startErr, err)
+	status := manager.Status()
+	common.AssertEqual(t, model.BackfillIdle, status.State)
+	common.AssertEqual(t, client.startErr, status.LastError)
+}
+
+func TestBackfillManager_Start_RetryRetryableError(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	client := newFakeBackfillClient()
+	client.startErr = common.NewGameLiftError(common.TooManyRequestsException, "", "")
+	manager := newBackfillManager(client, BackfillRetryPolicy{MaxRetries: 1, Delay: 50 * time.Millisecond}, lg, time.Second)
+	defer manager.Close()
+
+	// WHEN
+	_, err := manager.Start(context.Background(), newTestBackfillRequest())
+	status := manager.Status()
+	client.mtx.Lock()
+	client.startErr = nil
+	client.mtx.Unlock()
+
+	// THEN
+	if err == nil {
+		t.Fatal("Expected the error of the first attempt")
+	}
+	common.AssertEqual(t, model.BackfillRetrying, status.State)
+	common.AssertEqual(t, err, status.LastError)
+	select {
+	case <-client.startCh:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the retry")
+	}
+	waitForBackfillState(t, manager, model.BackfillActive)
+	common.AssertEqual(t, 2, manager.Status().Attempts)
+}
+
+func TestBackfillRetryPolicy_RetryDelay(t *testing.T) {
+	// GIVEN
+	policy := BackfillRetryPolicy{Delay: time.Second, MaxDelay: 5 * time.Second}
+
+	// THEN
+	common.AssertEqual(t, time.Second, policy.retryDelay(1))
+	common.AssertEqual(t, 2*time.Second, policy.retryDelay(2))
+	common.AssertEqual(t, 4*time.Second, policy.retryDelay(3))
+	common.AssertEqual(t, 5*time.Second, policy.retryDelay(4))
+	common.AssertEqual(t, common.BackfillRetryDelayDefault, (&BackfillRetryPolicy{}).retryDelay(1))
+}
+
+func TestClient_NewBackfillManager_ReceivesGameSessionUpdates(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	c := newClient(WithLogger(lg))
+	c.state.isReadyProcess.Store(true)
+	manager := c.NewBackfillManager(BackfillRetryPolicy{})
+	client := newFakeBackfillClient()
+	manager.client = client
+	ticketID, err := manager.Start(context.Background(), newTestBackfillRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	reason := model.MatchmakingDataUpdated
+	c.state.OnUpdateGameSession(&model.GameSession{GameSessionID: "gsess-1"}, &reason, ticketID)
+	manager.Close()
+
+	// THEN
+	status := manager.Status()
+	common.AssertEqual(t, model.BackfillIdle, status.State)
+	common.AssertEqual(t, model.MatchmakingDataUpdated, *status.LastUpdateReason)
+	common.AssertEqual(t, 0, len(c.state.updateGameSessionHandlers))
+}
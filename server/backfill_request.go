@@ -0,0 +1,95 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+)
+
+// BuildStartMatchBackfillRequest - builds a StartMatchBackfillRequest for the game session from its matchmaker data,
+// keeping only the specified players. Each player keeps the team, attributes and latencies from the matchmaker data.
+// The matchmaking configuration ARN is taken from the matchmaker data.
+//
+// Returns a common.ValidationException error if a player is not in the matchmaker data,
+// or if the request does not pass ValidateStartMatchBackfillRequest.
+func BuildStartMatchBackfillRequest(
+	gameSessionArn string,
+	data model.MatchmakerData,
+	playerIDs []string,
+) (request.StartMatchBackfillRequest, error) {
+	players := make(map[string]model.Player, len(data.Players))
+	for _, player := range data.Players {
+		players[player.PlayerID] = player
+	}
+	remaining := make([]model.Player, 0, len(playerIDs))
+	seen := make(map[string]bool, len(playerIDs))
+	for _, playerID := range playerIDs {
+		if seen[playerID] {
+			continue
+		}
+		seen[playerID] = true
+		player, ok := players[playerID]
+		if !ok {
+			return request.StartMatchBackfillRequest{}, common.NewGameLiftError(common.ValidationException, "",
+				fmt.Sprintf("Player %s is not in the matchmaker data of the game session.", playerID))
+		}
+		remaining = append(remaining, player)
+	}
+	req := request.NewStartMatchBackfill(gameSessionArn, data.MatchmakingConfigurationArn, remaining)
+	if err := ValidateStartMatchBackfillRequest(req); err != nil {
+		return request.StartMatchBackfillRequest{}, err
+	}
+	return req, nil
+}
+
+// buildStartMatchBackfillRequest - builds a StartMatchBackfillRequest for the current game session
+// from its last matchmaker data, keeping only the specified players, with their observed latencies.
+func (state *gameLiftServerState) buildStartMatchBackfillRequest(playerIDs []string) (request.StartMatchBackfillRequest, error) {
+	data, err := state.getMatchmakerData()
+	if err != nil {
+		return request.StartMatchBackfillRequest{}, err
+	}
+	req, err := BuildStartMatchBackfillRequest(state.gameSessionID, data, playerIDs)
+	if err != nil {
+		return request.StartMatchBackfillRequest{}, err
+	}
+	req.Players = state.latency.apply(req.Players)
+	return req, nil
+}
+
+// buildStartMatchBackfillRequestForPlayerSessions - same as buildStartMatchBackfillRequest, but resolves the players
+// of the specified player sessions with the ACTIVE player sessions reported by Amazon GameLift Servers.
+func (state *gameLiftServerState) buildStartMatchBackfillRequestForPlayerSessions(
+	ctx context.Context,
+	playerSessionIDs []string,
+) (request.StartMatchBackfillRequest, error) {
+	if state.gameSessionID == "" {
+		return request.StartMatchBackfillRequest{}, common.NewGameLiftError(common.GamesessionIDNotSet, "", "")
+	}
+	active, err := state.describeActivePlayerSessions(ctx)
+	if err != nil {
+		return request.StartMatchBackfillRequest{}, err
+	}
+	playerIDs := make(map[string]string, len(active))
+	for _, session := range active {
+		playerIDs[session.PlayerSessionID] = session.PlayerID
+	}
+	players := make([]string, 0, len(playerSessionIDs))
+	for _, playerSessionID := range playerSessionIDs {
+		playerID, ok := playerIDs[playerSessionID]
+		if !ok {
+			return request.StartMatchBackfillRequest{}, common.NewGameLiftError(common.ValidationException, "",
+				fmt.Sprintf("Player session %s is not ACTIVE in the game session.", playerSessionID))
+		}
+		players = append(players, playerID)
+	}
+	return state.buildStartMatchBackfillRequest(players)
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+)
+
+const testBackfillGameSessionArn = "arn:aws:gamelift:us-west-2::gamesession/fleet-1/gsess-1"
+
+var testBackfillMatchmakerData = model.MatchmakerData{
+	MatchID:                     "match-1",
+	MatchmakingConfigurationArn: testBackfillConfigArn,
+	Players: []model.Player{
+		{PlayerID: "player-1", Team: "red", LatencyInMS: map[string]int{"us-west-2": 20}},
+		{PlayerID: "player-2", Team: "blue", LatencyInMS: map[string]int{"us-west-2": 30}},
+		{PlayerID: "player-3", Team: "blue"},
+	},
+}
+
+func TestBuildStartMatchBackfillRequest_KeepConnectedPlayers(t *testing.T) {
+	// WHEN
+	req, err := BuildStartMatchBackfillRequest(testBackfillGameSessionArn, testBackfillMatchmakerData,
+		[]string{"player-2", "player-1", "player-2"})
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, testBackfillGameSessionArn, req.GameSessionArn)
+	common.AssertEqual(t, testBackfillConfigArn, req.MatchmakingConfigurationArn)
+	common.AssertEqual(t, message.StartMatchBackfill, req.Action)
+	common.AssertEqual(t, 2, len(req.Players))
+	common.AssertEqual(t, "player-2", req.Players[0].PlayerID)
+	common.AssertEqual(t, "blue", req.Players[0].Team)
+	common.AssertEqual(t, 30, req.Players[0].LatencyInMS["us-west-2"])
+	common.AssertEqual(t, "player-1", req.Players[1].PlayerID)
+}
+
+func TestBuildStartMatchBackfillRequest_UnknownPlayer_ReturnValidationError(t *testing.T) {
+	// WHEN
+	_, err := BuildStartMatchBackfillRequest(testBackfillGameSessionArn, testBackfillMatchmakerData,
+		[]string{"player-1", "player-4"})
+
+	// THEN
+	assertValidationException(t, err)
+}
+
+func TestBuildStartMatchBackfillRequest_NoPlayers_ReturnValidationError(t *testing.T) {
+	// WHEN
+	_, err := BuildStartMatchBackfillRequest(testBackfillGameSessionArn, testBackfillMatchmakerData, nil)
+
+	// THEN
+	assertValidationException(t, err)
+}
+
+func TestGameLiftServerState_BuildStartMatchBackfillRequestForPlayerSessions(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{wsGameLift: manager}
+	state.isReadyProcess.Store(true)
+	data, err := json.Marshal(&testBackfillMatchmakerData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.OnStartGameSession(&model.GameSession{GameSessionID: testBackfillGameSessionArn, MatchmakerData: string(data)})
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.AssignableToTypeOf(&request.DescribePlayerSessionsRequest{}), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *request.DescribePlayerSessionsRequest, res any, _ time.Duration) error {
+			common.AssertEqual(t, testBackfillGameSessionArn, req.GameSessionID)
+			*res.(*result.DescribePlayerSessionsResult) = result.DescribePlayerSessionsResult{
+				PlayerSessions: []model.PlayerSession{
+					{PlayerSessionID: "psess-1", PlayerID: "player-1"},
+					{PlayerSessionID: "psess-3", PlayerID: "player-3"},
+				},
+			}
+			return nil
+		}).
+		Times(2)
+
+	// WHEN
+	req, err := state.buildStartMatchBackfillRequestForPlayerSessions(context.Background(), []string{"psess-3"})
+	_, missingErr := state.buildStartMatchBackfillRequestForPlayerSessions(context.Background(), []string{"psess-2"})
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 1, len(req.Players))
+	common.AssertEqual(t, "player-3", req.Players[0].PlayerID)
+	common.AssertEqual(t, "blue", req.Players[0].Team)
+	assertValidationException(t, missingErr)
+}
+
+func assertValidationException(t *testing.T, err error) {
+	t.Helper()
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.ValidationException {
+		t.Fatalf("Expected ValidationException error but got %v", err)
+	}
+}
@@ -0,0 +1,447 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+// Client - a self-contained instance of the server SDK.
+//
+// Each Client owns its own websocket connection, logger, metrics factory and game session state,
+// so several clients can run side by side in one binary (for example, to simulate many server processes
+// in a single integration test). The package-level functions such as InitSDK and ProcessReady operate on a
+// default Client.
+//
+// Please use NewClient to create a new Client.
+type Client struct {
+	state          gameLiftServerState
+	manager        internal.IGameLiftManager
+	metricsFactory metrics.IFactory
+	// ownsMetricsFactory - true if metricsFactory was created by InitMetrics and must be terminated by Destroy.
+	// A factory set by WithMetricsFactory is left to its owner.
+	ownsMetricsFactory bool
+	lg                 log.ILogger
+	events             *eventBus
+	eventHandlers      []func(model.Event)
+	unsubscribe        []func()
+}
+
+// Option - configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithLogger - sets the logger used by the Client, see log.ILogger.
+// If not set, a default logger writing to the logs directory and stdout is created.
+func WithLogger(l log.ILogger) Option {
+	return func(c *Client) {
+		c.lg = l
+	}
+}
+
+// WithMetricsFactory - sets the metrics factory used by the Client to report game session and process metrics.
+// Use this option to share a factory between clients, as the metrics processor is global to the process.
+// The Client does not terminate the factory on Destroy; its owner does.
+func WithMetricsFactory(f metrics.IFactory) Option {
+	return func(c *Client) {
+		c.metricsFactory = f
+	}
+}
+
+// withGameLiftManager - replaces the websocket manager of the Client. Used for testing purposes.
+func withGameLiftManager(m internal.IGameLiftManager) Option {
+	return func(c *Client) {
+		c.manager = m
+	}
+}
+
+// NewClient - creates a new Client and initializes it with the specified ServerParameters.
+// The returned Client is connected to Amazon GameLift Servers and ready for a ProcessReady call.
+// On error, the Client is released before returning, including the handlers added by WithEventHandler.
+//
+//	client, err := server.NewClient(serverParameters, server.WithLogger(logger))
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Destroy()
+//	err = client.ProcessReady(processParameters)
+func NewClient(params ServerParameters, opts ...Option) (*Client, error) {
+	c := newClient(opts...)
+	if err := c.init(params); err != nil {
+		if destroyErr := c.state.destroy(); destroyErr != nil {
+			c.lg.Warnf("Failed to release the client after a failed initialization: %s", destroyErr)
+		}
+		c.unsubscribeAll()
+		return nil, err
+	}
+	return c, nil
+}
+
+// WithEventHandler - subscribes the handler to the events of the Client before it connects,
+// so that the initial connection is reported too. The handler is unsubscribed by Destroy. See server.Subscribe.
+func WithEventHandler(handler func(model.Event)) Option {
+	return func(c *Client) {
+		c.eventHandlers = append(c.eventHandlers, handler)
+	}
+}
+
+// withEvents - sets the event bus used by the Client, used to keep subscriptions of the default Client.
+func withEvents(events *eventBus) Option {
+	return func(c *Client) {
+		c.events = events
+	}
+}
+
+func newClient(opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.events == nil {
+		c.events = &eventBus{}
+	}
+	c.state.events = c.events
+	for _, handler := range c.eventHandlers {
+		c.unsubscribe = append(c.unsubscribe, c.events.subscribe(handler))
+	}
+	return c
+}
+
+func (c *Client) init(params ServerParameters) error {
+	params.ProcessID = common.GetEnvStringOrDefault(common.EnvironmentKeyProcessID, params.ProcessID)
+	if c.lg == nil {
+		c.lg = log.GetDefaultLogger(params.ProcessID)
+	}
+	if c.manager == nil {
+		wsDialer := transport.NewDialer(c.lg)
+		ws := transport.Websocket(c.lg, wsDialer, transport.WithBackoffPolicy(params.ReconnectPolicy.backoffPolicy()))
+		wsTransport := transport.WithRetry(withTrafficRecorder(ws, c.lg), c.lg)
+		client := internal.NewWebsocketClient(wsTransport, c.lg, c.state.onConnectionEvent)
+		httpClient := &http.Client{}
+		c.manager = internal.GetGameLiftManager(&c.state, client, c.lg, httpClient)
+	}
+	c.state.lg = c.lg
+	err := c.state.init(params, c.manager)
+	if c.metricsFactory != nil {
+		c.state.setMetricsFactory(c.metricsFactory)
+	}
+	return err
+}
+
+// withTrafficRecorder - records the websocket traffic to the file set by the
+// GAMELIFT_SDK_TRAFFIC_RECORD_FILE environment variable, if any.
+func withTrafficRecorder(tr transport.ITransport, l log.ILogger) transport.ITransport {
+	path := os.Getenv(common.EnvironmentKeyTrafficRecordFile)
+	if path == "" {
+		return tr
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		l.Warnf("Failed to open traffic recording %s: %s", path, err)
+		return tr
+	}
+	l.Debugf("Recording websocket traffic to %s", path)
+	return transport.WithRecorder(tr, f, l)
+}
+
+// InitMetrics - initializes the metrics system for this Client, see server.InitMetrics.
+// The underlying metrics processor is shared by the whole process, so only one Client can initialize it.
+// Other clients should receive the factory through WithMetricsFactory.
+func (c *Client) InitMetrics(metricsParameters MetricsParameters) (*Metrics, error) {
+	if c.metricsFactory != nil {
+		return nil, common.NewGameLiftError(common.AlreadyInitialized, "Already initialized", "You can only initialize metrics once.")
+	}
+	localMetrics, localMetricsFactory, err := createMetrics(&metricsParameters, &c.state)
+	if err != nil {
+		return nil, err
+	}
+	c.metricsFactory = localMetricsFactory
+	c.ownsMetricsFactory = true
+	return localMetrics, nil
+}
+
+// ProcessReady - notifies Amazon GameLift Servers that the server process is ready to host game sessions,
+// see server.ProcessReady.
+func (c *Client) ProcessReady(param ProcessParameters) error {
+	return c.ProcessReadyContext(context.Background(), param)
+}
+
+// ProcessReadyContext - same as ProcessReady, but the call is abandoned when ctx is done.
+func (c *Client) ProcessReadyContext(ctx context.Context, param ProcessParameters) error {
+	return c.state.processReady(ctx, &param)
+}
+
+// ProcessEnding - notifies Amazon GameLift Servers that the server process is shutting down, see server.ProcessEnding.
+func (c *Client) ProcessEnding() error {
+	return c.ProcessEndingContext(context.Background())
+}
+
+// ProcessEndingContext - same as ProcessEnding, but the call is abandoned when ctx is done.
+func (c *Client) ProcessEndingContext(ctx context.Context) error {
+	return c.state.processEnding(ctx)
+}
+
+// ActivateGameSession - notifies Amazon GameLift Servers that the game session is ready to receive player connections,
+// see server.ActivateGameSession.
+func (c *Client) ActivateGameSession() error {
+	return c.ActivateGameSessionContext(context.Background())
+}
+
+// ActivateGameSessionContext - same as ActivateGameSession, but the call is abandoned when ctx is done.
+func (c *Client) ActivateGameSessionContext(ctx context.Context) error {
+	return c.state.activateGameSession(ctx)
+}
+
+// UpdatePlayerSessionCreationPolicy - updates the current game session's ability to accept new player sessions,
+// see server.UpdatePlayerSessionCreationPolicy.
+func (c *Client) UpdatePlayerSessionCreationPolicy(policy model.PlayerSessionCreationPolicy) error {
+	return c.UpdatePlayerSessionCreationPolicyContext(context.Background(), policy)
+}
+
+// UpdatePlayerSessionCreationPolicyContext - same as UpdatePlayerSessionCreationPolicy,
+// but the call is abandoned when ctx is done.
+func (c *Client) UpdatePlayerSessionCreationPolicyContext(
+	ctx context.Context,
+	policy model.PlayerSessionCreationPolicy,
+) error {
+	return c.state.updatePlayerSessionCreationPolicy(ctx, &policy)
+}
+
+// GetGameSessionID - retrieves the ID of the game session currently hosted by this Client,
+// see server.GetGameSessionID.
+func (c *Client) GetGameSessionID() (string, error) {
+	return c.state.getGameSessionID()
+}
+
+// GetTerminationTime - returns the time in epoch seconds that the server process is scheduled to be shut down,
+// see server.GetTerminationTime.
+func (c *Client) GetTerminationTime() (int64, error) {
+	return c.state.getTerminationTime()
+}
+
+// GetMatchmakerData - returns the matchmaker data last received for the game session,
+// see server.GetMatchmakerData.
+func (c *Client) GetMatchmakerData() (model.MatchmakerData, error) {
+	return c.state.getMatchmakerData()
+}
+
+// GetProcessState - returns the current lifecycle state of the server process, see server.GetProcessState.
+func (c *Client) GetProcessState() model.ProcessState {
+	return c.state.getProcessState()
+}
+
+// GetProcessStateHistory - returns the lifecycle state transitions of the server process,
+// see server.GetProcessStateHistory.
+func (c *Client) GetProcessStateHistory() []model.ProcessStateTransition {
+	return c.state.getProcessStateHistory()
+}
+
+// AcceptPlayerSession - validates a connecting player session, see server.AcceptPlayerSession.
+func (c *Client) AcceptPlayerSession(playerSessionID string) error {
+	return c.AcceptPlayerSessionContext(context.Background(), playerSessionID)
+}
+
+// AcceptPlayerSessionContext - same as AcceptPlayerSession, but the call is abandoned when ctx is done.
+func (c *Client) AcceptPlayerSessionContext(ctx context.Context, playerSessionID string) error {
+	return c.state.acceptPlayerSession(ctx, playerSessionID, nil)
+}
+
+// AcceptPlayerSessionWithMetadata - same as AcceptPlayerSessionContext, and records connection metadata with
+// the accepted player session, see server.AcceptPlayerSessionWithMetadata.
+func (c *Client) AcceptPlayerSessionWithMetadata(
+	ctx context.Context,
+	playerSessionID string,
+	metadata map[string]string,
+) error {
+	return c.state.acceptPlayerSession(ctx, playerSessionID, metadata)
+}
+
+// RegisterHealthCheck - adds a named health check to the heartbeat of this Client, see server.RegisterHealthCheck.
+func (c *Client) RegisterHealthCheck(name string, check HealthCheckFunc, timeout time.Duration) error {
+	return c.state.registerHealthCheck(name, check, timeout)
+}
+
+// HealthCheckResults - returns the last result of every registered health check, see server.HealthCheckResults.
+func (c *Client) HealthCheckResults() []HealthCheckResult {
+	return c.state.healthCheckResults()
+}
+
+// ListAcceptedPlayerSessions - returns the player sessions accepted by this Client and not yet removed,
+// see server.ListAcceptedPlayerSessions.
+func (c *Client) ListAcceptedPlayerSessions() []AcceptedPlayerSession {
+	return c.state.listAcceptedPlayerSessions()
+}
+
+// ReconcilePlayerSessions - compares the accepted player sessions with Amazon GameLift Servers,
+// see server.ReconcilePlayerSessions.
+func (c *Client) ReconcilePlayerSessions(ctx context.Context) (PlayerSessionDrift, error) {
+	return c.state.reconcilePlayerSessions(ctx)
+}
+
+// RemovePlayerSession - notifies Amazon GameLift Servers that a player has disconnected, see server.RemovePlayerSession.
+func (c *Client) RemovePlayerSession(playerSessionID string) error {
+	return c.RemovePlayerSessionContext(context.Background(), playerSessionID)
+}
+
+// RemovePlayerSessionContext - same as RemovePlayerSession, but the call is abandoned when ctx is done.
+func (c *Client) RemovePlayerSessionContext(ctx context.Context, playerSessionID string) error {
+	return c.state.removePlayerSession(ctx, playerSessionID)
+}
+
+// DescribePlayerSessions - retrieves player session data, see server.DescribePlayerSessions.
+func (c *Client) DescribePlayerSessions(req request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error) {
+	return c.DescribePlayerSessionsContext(context.Background(), req)
+}
+
+// DescribePlayerSessionsContext - same as DescribePlayerSessions, but the call is abandoned when ctx is done.
+func (c *Client) DescribePlayerSessionsContext(
+	ctx context.Context,
+	req request.DescribePlayerSessionsRequest,
+) (result.DescribePlayerSessionsResult, error) {
+	return c.state.describePlayerSessions(ctx, &req)
+}
+
+// AllPlayerSessions - returns the player sessions matching req across all pages, see server.AllPlayerSessions.
+func (c *Client) AllPlayerSessions(
+	ctx context.Context,
+	req request.DescribePlayerSessionsRequest,
+) iter.Seq2[model.PlayerSession, error] {
+	return c.state.allPlayerSessions(ctx, req)
+}
+
+// StartMatchBackfill - sends a request to find new players for open slots in the game session,
+// see server.StartMatchBackfill.
+func (c *Client) StartMatchBackfill(req request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, error) {
+	return c.StartMatchBackfillContext(context.Background(), req)
+}
+
+// StartMatchBackfillContext - same as StartMatchBackfill, but the call is abandoned when ctx is done.
+func (c *Client) StartMatchBackfillContext(
+	ctx context.Context,
+	req request.StartMatchBackfillRequest,
+) (result.StartMatchBackfillResult, error) {
+	return c.state.startMatchBackfill(ctx, &req)
+}
+
+// BuildStartMatchBackfillRequestForPlayers - builds a StartMatchBackfillRequest for the game session hosted by this Client,
+// keeping only the specified players, see server.BuildStartMatchBackfillRequestForPlayers.
+func (c *Client) BuildStartMatchBackfillRequestForPlayers(playerIDs []string) (request.StartMatchBackfillRequest, error) {
+	return c.state.buildStartMatchBackfillRequest(playerIDs)
+}
+
+// BuildStartMatchBackfillRequestForPlayerSessions - same as BuildStartMatchBackfillRequest, but for the players
+// of the specified player sessions, see server.BuildStartMatchBackfillRequestForPlayerSessions.
+func (c *Client) BuildStartMatchBackfillRequestForPlayerSessions(
+	ctx context.Context,
+	playerSessionIDs []string,
+) (request.StartMatchBackfillRequest, error) {
+	return c.state.buildStartMatchBackfillRequestForPlayerSessions(ctx, playerSessionIDs)
+}
+
+// RecordPlayerLatency - records a latency sample of a player to a Region, see server.RecordPlayerLatency.
+func (c *Client) RecordPlayerLatency(playerID, region string, latency time.Duration) error {
+	return c.state.recordPlayerLatency(playerID, region, latency)
+}
+
+// ApplyObservedLatencies - returns the players with their observed latencies, see server.ApplyObservedLatencies.
+func (c *Client) ApplyObservedLatencies(players []model.Player) []model.Player {
+	return c.state.applyObservedLatencies(players)
+}
+
+// NewBackfillManager - creates a BackfillManager that starts and stops match backfill requests with this Client,
+// see server.NewBackfillManager. It receives the game session updates of this Client until it is closed.
+func (c *Client) NewBackfillManager(policy BackfillRetryPolicy) *BackfillManager {
+	m := newBackfillManager(c, policy, c.lg, c.state.serviceCallTimeout)
+	m.unsubscribe = c.state.subscribeUpdateGameSession(m.HandleUpdateGameSession)
+	return m
+}
+
+// StopMatchBackfill - cancels an active match backfill request, see server.StopMatchBackfill.
+func (c *Client) StopMatchBackfill(req request.StopMatchBackfillRequest) error {
+	return c.StopMatchBackfillContext(context.Background(), req)
+}
+
+// StopMatchBackfillContext - same as StopMatchBackfill, but the call is abandoned when ctx is done.
+func (c *Client) StopMatchBackfillContext(ctx context.Context, req request.StopMatchBackfillRequest) error {
+	return c.state.stopMatchBackfill(ctx, &req)
+}
+
+// GetComputeCertificate - retrieves the path to the TLS certificate of the compute, see server.GetComputeCertificate.
+func (c *Client) GetComputeCertificate() (result.GetComputeCertificateResult, error) {
+	return c.GetComputeCertificateContext(context.Background())
+}
+
+// GetComputeCertificateContext - same as GetComputeCertificate, but the call is abandoned when ctx is done.
+func (c *Client) GetComputeCertificateContext(ctx context.Context) (result.GetComputeCertificateResult, error) {
+	return c.state.getComputeCertificate(ctx)
+}
+
+// ListContainersNetworkInfo - retrieves network information for all containers running on the same instance,
+// see server.ListContainersNetworkInfo.
+func (c *Client) ListContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error) {
+	return c.ListContainersNetworkInfoContext(context.Background())
+}
+
+// ListContainersNetworkInfoContext - same as ListContainersNetworkInfo, but the call is abandoned when ctx is done.
+func (c *Client) ListContainersNetworkInfoContext(ctx context.Context) (result.ListContainersNetworkInfoResult, error) {
+	return c.state.listContainersNetworkInfo(ctx)
+}
+
+// GetFleetRoleCredentials - retrieves the service role credentials of the fleet, see server.GetFleetRoleCredentials.
+func (c *Client) GetFleetRoleCredentials(
+	req request.GetFleetRoleCredentialsRequest,
+) (result.GetFleetRoleCredentialsResult, error) {
+	return c.GetFleetRoleCredentialsContext(context.Background(), req)
+}
+
+// GetFleetRoleCredentialsContext - same as GetFleetRoleCredentials, but the call is abandoned when ctx is done.
+func (c *Client) GetFleetRoleCredentialsContext(
+	ctx context.Context,
+	req request.GetFleetRoleCredentialsRequest,
+) (result.GetFleetRoleCredentialsResult, error) {
+	return c.state.getFleetRoleCredentials(ctx, &req)
+}
+
+// Destroy - stops heartbeat communication, closes the websocket connection and terminates the metrics factory
+// created by InitMetrics of this Client, see server.Destroy. A destroyed Client cannot be reused; create a new one with NewClient instead.
+func (c *Client) Destroy() error {
+	if err := c.state.destroy(); err != nil {
+		return err
+	}
+	if c.ownsMetricsFactory {
+		terminateMetricsFactory(c.metricsFactory, c.lg)
+	}
+	c.metricsFactory = nil
+	c.ownsMetricsFactory = false
+	c.manager = nil
+	c.unsubscribeAll()
+	return nil
+}
+
+// unsubscribeAll - unsubscribes the handlers added by WithEventHandler.
+func (c *Client) unsubscribeAll() {
+	for _, unsubscribe := range c.unsubscribe {
+		unsubscribe()
+	}
+	c.unsubscribe = nil
+}
+
+// Subscribe - registers a handler for the events of this Client, see server.Subscribe.
+func (c *Client) Subscribe(handler func(model.Event)) func() {
+	return c.events.subscribe(handler)
+}
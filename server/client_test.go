@@ -0,0 +1,229 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+)
+
+func TestNewClient_IndependentInstances(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	clearEnvironmentVariables(t)
+
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	firstParams := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "first-process-id",
+		HostID:       "test-host-id",
+		FleetID:      "test-fleet-id",
+		AuthToken:    "test-auth-token",
+	}
+	secondParams := firstParams
+	secondParams.ProcessID = "second-process-id"
+
+	firstManager := mock.NewMockIGameLiftManager(ctrl)
+	mockSuccessfulConnect(firstManager, 1, firstParams)
+	firstManager.EXPECT().Disconnect().Times(1)
+	secondManager := mock.NewMockIGameLiftManager(ctrl)
+	mockSuccessfulConnect(secondManager, 1, secondParams)
+	secondManager.EXPECT().Disconnect().Times(1)
+
+	// WHEN
+	first := newClient(withGameLiftManager(firstManager), WithLogger(logger))
+	if err := first.init(firstParams); err != nil {
+		t.Fatal(err)
+	}
+	second := newClient(withGameLiftManager(secondManager), WithLogger(logger))
+	if err := second.init(secondParams); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	common.AssertEqual(t, firstParams.ProcessID, first.state.processID)
+	common.AssertEqual(t, secondParams.ProcessID, second.state.processID)
+
+	if err := first.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if second.state.wsGameLift == nil {
+		t.Fatal("Destroying one client should not affect another")
+	}
+	if err := second.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClient_InvalidParameters(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	clearEnvironmentVariables(t)
+
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+
+	// WHEN
+	client, err := NewClient(ServerParameters{}, WithLogger(logger), withGameLiftManager(mock.NewMockIGameLiftManager(ctrl)))
+
+	// THEN
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if client != nil {
+		t.Fatal("Expected nil client on error")
+	}
+}
+
+func TestNewClient_ConnectFailed_ReleasesClient(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	clearEnvironmentVariables(t)
+
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	params := ServerParameters{
+		WebSocketURL: "wss://test.url",
+		ProcessID:    "test-process-id",
+		HostID:       "test-host-id",
+		FleetID:      "test-fleet-id",
+		AuthToken:    "test-auth-token",
+	}
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	manager.
+		EXPECT().
+		Connect(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(common.NewGameLiftError(common.WebsocketConnectFailure, "", "")).
+		Times(1)
+	manager.EXPECT().Disconnect().Times(1)
+	events := &eventBus{}
+
+	// WHEN
+	client, err := NewClient(params,
+		WithLogger(logger),
+		withGameLiftManager(manager),
+		withEvents(events),
+		WithEventHandler(func(model.Event) {}),
+	)
+
+	// THEN
+	if err == nil {
+		t.Fatal("Expected connection error")
+	}
+	if client != nil {
+		t.Fatal("Expected nil client on error")
+	}
+	events.mtx.RLock()
+	defer events.mtx.RUnlock()
+	if len(events.subscribers) != 0 {
+		t.Fatalf("Expected no subscribers after a failed initialization, got %d", len(events.subscribers))
+	}
+}
+
+func TestClient_DescribePlayerSessionsContext_PassesContext(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	client := newClient(withGameLiftManager(manager))
+	client.state.wsGameLift = manager
+	client.state.serviceCallTimeout = time.Second
+	client.state.isReadyProcess.Store(true)
+
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = "test-game-session-id"
+	expectedErr := common.WrapGameLiftError(common.ServiceCallFailed, context.Canceled)
+	manager.
+		EXPECT().
+		HandleRequest(ctx, &req, gomock.Any(), time.Second).
+		Return(expectedErr)
+
+	// WHEN
+	_, err := client.DescribePlayerSessionsContext(ctx, req)
+
+	// THEN
+	common.AssertEqual(t, expectedErr, err)
+}
+
+func TestClient_ListContainersNetworkInfoContext_PassesContext(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	manager := mock.NewMockIGameLiftManager(ctrl)
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	client := newClient(withGameLiftManager(manager), WithLogger(logger))
+	client.state.wsGameLift = manager
+	client.state.lg = logger
+
+	expectedErr := common.WrapGameLiftError(common.InternalServiceException, context.Canceled)
+	manager.
+		EXPECT().
+		FetchContainersNetworkInfo(ctx).
+		Return(result.ListContainersNetworkInfoResult{}, expectedErr)
+
+	// WHEN
+	_, err := client.ListContainersNetworkInfoContext(ctx)
+
+	// THEN
+	common.AssertEqual(t, expectedErr, err)
+}
+
+func TestClient_Destroy_TerminatesOnlyOwnedMetricsFactory(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		owned                  bool
+		expectedTerminateCalls int
+	}{
+		{name: "injected with WithMetricsFactory", owned: false, expectedTerminateCalls: 0},
+		{name: "created by InitMetrics", owned: true, expectedTerminateCalls: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// GIVEN
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			terminateCalls := 0
+			terminateMetricsProcessor = func() error {
+				terminateCalls++
+				return nil
+			}
+			defer func() { terminateMetricsProcessor = metrics.TerminateMetricsProcessor }()
+
+			manager := mock.NewMockIGameLiftManager(ctrl)
+			manager.EXPECT().Disconnect().Times(1)
+			logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+			client := newClient(withGameLiftManager(manager), WithLogger(logger), WithMetricsFactory(&mockFactory{}))
+			client.state.wsGameLift = manager
+			client.ownsMetricsFactory = tc.owned
+
+			// WHEN
+			if err := client.Destroy(); err != nil {
+				t.Fatal(err)
+			}
+
+			// THEN
+			common.AssertEqual(t, tc.expectedTerminateCalls, terminateCalls)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"sync"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+const (
+	// requestBufferDepthMetric - gauge of the requests held back while the websocket connection is re-established.
+	requestBufferDepthMetric = "server_sdk.request_buffer.depth"
+	// requestBufferDroppedMetric - counter of the requests held back that failed without being written.
+	requestBufferDroppedMetric = "server_sdk.request_buffer.dropped"
+	// reconnectRetriesMetric - counter of the failed connection attempts retried, see ReconnectPolicy.
+	reconnectRetriesMetric = "server_sdk.reconnect.retries"
+	// reconnectGiveUpsMetric - counter of the reconnects given up after ReconnectPolicy.MaxAttempts.
+	reconnectGiveUpsMetric = "server_sdk.reconnect.give_ups"
+)
+
+// connectionMetrics - reports the connection events of the websocket client as metrics.
+// The metrics are created on the first event after a metrics factory is set.
+type connectionMetrics struct {
+	mtx     sync.Mutex
+	factory metrics.IFactory
+	depth   *metrics.Gauge
+	dropped *metrics.Counter
+	retries *metrics.Counter
+	giveUps *metrics.Counter
+}
+
+// record - updates the metrics for the event, if it is a connection event.
+func (m *connectionMetrics) record(factory metrics.IFactory, event model.Event, logger log.ILogger) {
+	switch event.Type {
+	case model.EventRequestBuffered, model.EventRequestBufferFlushed, model.EventRequestDropped,
+		model.EventWebsocketConnectRetry, model.EventWebsocketReconnectFailed:
+	default:
+		return
+	}
+	if factory == nil {
+		return
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.factory != factory {
+		m.factory = factory
+		m.depth = newGauge(factory, requestBufferDepthMetric, logger)
+		m.dropped = newCounter(factory, requestBufferDroppedMetric, logger)
+		m.retries = newCounter(factory, reconnectRetriesMetric, logger)
+		m.giveUps = newCounter(factory, reconnectGiveUpsMetric, logger)
+	}
+	switch event.Type {
+	case model.EventWebsocketConnectRetry:
+		increment(m.retries)
+	case model.EventWebsocketReconnectFailed:
+		increment(m.giveUps)
+	default:
+		if m.depth != nil {
+			m.depth.Set(float64(event.BufferedRequests))
+		}
+		if event.Type == model.EventRequestDropped {
+			increment(m.dropped)
+		}
+	}
+}
+
+func newGauge(factory metrics.IFactory, key string, logger log.ILogger) *metrics.Gauge {
+	gauge, err := factory.Gauge(key)
+	if err != nil {
+		logger.Debugf("Could not create gauge %s: %s", key, err)
+	}
+	return gauge
+}
+
+func newCounter(factory metrics.IFactory, key string, logger log.ILogger) *metrics.Counter {
+	counter, err := factory.Counter(key)
+	if err != nil {
+		logger.Debugf("Could not create counter %s: %s", key, err)
+	}
+	return counter
+}
+
+func increment(counter *metrics.Counter) {
+	if counter != nil {
+		counter.Increment()
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// counterRecordingFactory - records the keys of the requested gauges and counters.
+type counterRecordingFactory struct {
+	gaugeRecordingFactory
+	counters []string
+}
+
+func (f *counterRecordingFactory) Counter(key string) (*metrics.Counter, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.counters = append(f.counters, key)
+	return nil, nil
+}
+
+// GIVEN a metrics factory WHEN connection events are handled THEN the connection metrics are created once
+func TestGameLiftServerState_OnConnectionEvent_RecordsConnectionMetrics(t *testing.T) {
+	// GIVEN
+	factory := &counterRecordingFactory{}
+	state := &gameLiftServerState{metricsFactory: factory}
+
+	// WHEN
+	state.onConnectionEvent(model.Event{Type: model.EventWebsocketReconnecting})
+	state.onConnectionEvent(model.Event{Type: model.EventRequestBuffered, BufferedRequests: 1})
+	state.onConnectionEvent(model.Event{Type: model.EventRequestDropped})
+	state.onConnectionEvent(model.Event{Type: model.EventRequestBufferFlushed})
+	state.onConnectionEvent(model.Event{Type: model.EventWebsocketConnectRetry, Attempt: 1})
+
+	// THEN
+	common.AssertEqual(t, 1, len(factory.gauges))
+	common.AssertEqual(t, requestBufferDepthMetric, factory.gauges[0])
+	common.AssertEqual(t, 3, len(factory.counters))
+	common.AssertEqual(t, requestBufferDroppedMetric, factory.counters[0])
+	common.AssertEqual(t, reconnectRetriesMetric, factory.counters[1])
+	common.AssertEqual(t, reconnectGiveUpsMetric, factory.counters[2])
+}
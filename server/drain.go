@@ -0,0 +1,85 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// drainDeadline - returns the time by which the drain must end.
+// terminationTime is the termination time in epoch seconds, or 0 if Amazon GameLift Servers did not send one.
+// The deadline is SafetyMargin before the termination time, and never later than Timeout from now.
+// Without a Timeout, the drain ends SafetyMargin before Amazon GameLift Servers stops waiting for ProcessEnding().
+func drainDeadline(now time.Time, terminationTime int64, params *DrainParameters) time.Time {
+	safetyMargin := params.SafetyMargin
+	if safetyMargin == 0 {
+		safetyMargin = common.DrainSafetyMarginDefault
+	}
+	timeout := params.Timeout
+	if timeout == 0 {
+		timeout = common.DrainTimeoutDefault - safetyMargin
+	}
+	deadline := now.Add(timeout)
+	if terminationTime > 0 {
+		if byTermination := time.Unix(terminationTime, 0).Add(-safetyMargin); byTermination.Before(deadline) {
+			deadline = byTermination
+		}
+	}
+	return deadline
+}
+
+// drainProcess - stops new players from joining, notifies the game and waits for connected players to leave
+// before ending the process, see DrainParameters.
+func (state *gameLiftServerState) drainProcess(params *DrainParameters) {
+	deadline := drainDeadline(time.Now(), state.terminationTime, params)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	state.logger().Debugf("Draining until %s", deadline.Format(time.RFC3339))
+
+	if state.gameSessionID != "" {
+		policy := model.DenyAll
+		if err := state.updatePlayerSessionCreationPolicy(ctx, &policy); err != nil {
+			state.logger().Warnf("Failed to deny new player sessions while draining: %s", err)
+		}
+	}
+	if params.OnDrain != nil {
+		params.OnDrain(ctx)
+	}
+	state.waitForPlayers(ctx, params)
+
+	state.endProcess(params.OnComplete)
+}
+
+// waitForPlayers - blocks until no players are connected or ctx is done.
+func (state *gameLiftServerState) waitForPlayers(ctx context.Context, params *DrainParameters) {
+	playerCount := params.PlayerCount
+	if playerCount == nil {
+		playerCount = state.playerSessions.count
+	}
+	pollInterval := params.PollInterval
+	if pollInterval == 0 {
+		pollInterval = common.DrainPollIntervalDefault
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		count := playerCount()
+		if count == 0 {
+			state.logger().Debugf("All players left, drain completed")
+			return
+		}
+		select {
+		case <-ctx.Done():
+			state.logger().Warnf("Drain deadline reached with %d players still connected", count)
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+)
+
+func TestDrainDeadline(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	cases := map[string]struct {
+		terminationTime int64
+		params          DrainParameters
+		expected        time.Time
+	}{
+		"no termination time uses default timeout": {
+			expected: now.Add(common.DrainTimeoutDefault - common.DrainSafetyMarginDefault),
+		},
+		"spot interruption ends before the default timeout": {
+			terminationTime: now.Add(2 * time.Minute).Unix(),
+			expected:        now.Add(2*time.Minute - common.DrainSafetyMarginDefault),
+		},
+		"custom safety margin": {
+			terminationTime: now.Add(2 * time.Minute).Unix(),
+			params:          DrainParameters{SafetyMargin: 30 * time.Second},
+			expected:        now.Add(90 * time.Second),
+		},
+		"timeout shorter than termination time": {
+			terminationTime: now.Add(2 * time.Minute).Unix(),
+			params:          DrainParameters{Timeout: time.Minute},
+			expected:        now.Add(time.Minute),
+		},
+		"termination time already passed": {
+			terminationTime: now.Add(-time.Minute).Unix(),
+			expected:        now.Add(-time.Minute - common.DrainSafetyMarginDefault),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := drainDeadline(now, c.terminationTime, &c.params)
+			if !actual.Equal(c.expected) {
+				t.Fatalf("Expected %s but got %s", c.expected, actual)
+			}
+		})
+	}
+}
+
+func setupDrainTest(t *testing.T, drain *DrainParameters) (*gameLiftServerState, *int32) {
+	manager := setupNewMockIGameLiftManager(t)
+
+	var exitCalls int32
+	exitFunc = func(code int) {
+		atomic.AddInt32(&exitCalls, 1)
+	}
+
+	state := &gameLiftServerState{
+		wsGameLift:         manager,
+		parameters:         &ProcessParameters{Port: 8080, Drain: drain},
+		gameSessionID:      "test-game-session-id",
+		serviceCallTimeout: time.Second,
+	}
+	state.isReadyProcess.Store(true)
+	for _, next := range []model.ProcessState{model.ProcessReady, model.ProcessSessionAssigned, model.ProcessSessionActive} {
+		if err := state.setProcessState(next, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gomock.InOrder(
+		manager.
+			EXPECT().
+			HandleRequest(gomock.Any(), gomock.AssignableToTypeOf(request.UpdatePlayerSessionCreationPolicyRequest{}), nil, time.Second).
+			Do(func(_ context.Context, req internal.MessageGetter, _ any, _ time.Duration) {
+				policy := req.(request.UpdatePlayerSessionCreationPolicyRequest).PlayerSessionPolicy
+				if *policy != model.DenyAll {
+					t.Errorf("Expected DENY_ALL policy but got %s", policy.String())
+				}
+			}).
+			Return(nil),
+		manager.
+			EXPECT().
+			HandleRequest(gomock.Any(), ignoreRequestID(request.NewTerminateServerProcess()), nil, time.Second).
+			Return(nil),
+		manager.
+			EXPECT().
+			Disconnect().
+			Return(nil),
+	)
+	return state, &exitCalls
+}
+
+// GIVEN drain enabled WHEN OnTerminateProcess THEN deny new players, wait for players to leave and end the process
+func TestGameLiftServerStateOnTerminateProcess_WithDrain_WaitsForPlayers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	var polls int32
+	var drainCalled, completeCalled common.AtomicBool
+	state, exitCalls := setupDrainTest(t, &DrainParameters{
+		OnDrain: func(ctx context.Context) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("Expected drain context with deadline")
+			}
+			drainCalled.Store(true)
+		},
+		PlayerCount: func() int {
+			// two players connected, one leaves between each poll
+			return 3 - int(atomic.AddInt32(&polls, 1))
+		},
+		OnComplete: func(err error) {
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			completeCalled.Store(true)
+		},
+		PollInterval: time.Millisecond,
+	})
+
+	// WHEN
+	state.OnTerminateProcess(time.Now().Add(time.Minute).UnixMilli())
+
+	// THEN
+	if !drainCalled.Load() {
+		t.Fatal("OnDrain was not called")
+	}
+	if !completeCalled.Load() {
+		t.Fatal("OnComplete was not called")
+	}
+	common.AssertEqual(t, int32(3), atomic.LoadInt32(&polls))
+	common.AssertEqual(t, int32(0), atomic.LoadInt32(exitCalls))
+	common.AssertEqual(t, model.ProcessEnded, state.getProcessState())
+}
+
+// GIVEN drain enabled and players never leave WHEN OnTerminateProcess THEN end the process at the deadline
+func TestGameLiftServerStateOnTerminateProcess_WithDrain_EndsAtDeadline(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	state, exitCalls := setupDrainTest(t, &DrainParameters{
+		PlayerCount:  func() int { return 1 },
+		Timeout:      20 * time.Millisecond,
+		PollInterval: time.Millisecond,
+	})
+
+	// WHEN
+	start := time.Now()
+	state.OnTerminateProcess(time.Now().Add(time.Minute).UnixMilli())
+
+	// THEN
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Drain took %s, expected to end at the deadline", elapsed)
+	}
+	common.AssertEqual(t, int32(1), atomic.LoadInt32(exitCalls))
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// eventBufferSize - number of events queued per subscriber before new events are dropped.
+const eventBufferSize = 64
+
+type eventSubscription struct {
+	events chan model.Event
+	once   sync.Once
+}
+
+// eventBus - delivers events to subscribers. Each subscriber receives events in order on its own goroutine,
+// so a slow subscriber neither blocks the server SDK nor other subscribers.
+// The zero value is a bus without subscribers.
+type eventBus struct {
+	mtx         sync.RWMutex
+	nextID      int
+	subscribers map[int]*eventSubscription
+}
+
+// subscribe - registers the handler and returns a function that unregisters it.
+func (b *eventBus) subscribe(handler func(model.Event)) func() {
+	sub := &eventSubscription{events: make(chan model.Event, eventBufferSize)}
+	b.mtx.Lock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[int]*eventSubscription)
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mtx.Unlock()
+
+	go func() {
+		for event := range sub.events {
+			handler(event)
+		}
+	}()
+
+	return func() {
+		sub.once.Do(func() {
+			b.mtx.Lock()
+			delete(b.subscribers, id)
+			b.mtx.Unlock()
+			close(sub.events)
+		})
+	}
+}
+
+// emit - queues the event for every subscriber without blocking and returns the number of subscribers
+// that dropped it because their queue was full. Time is set to now if it is zero.
+func (b *eventBus) emit(event model.Event) int {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	dropped := 0
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+	for _, sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
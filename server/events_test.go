@@ -0,0 +1,120 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+)
+
+// collectEvents - subscribes to the bus and returns a function that waits for n events.
+func collectEvents(t *testing.T, bus *eventBus) (func(n int) []model.Event, func()) {
+	t.Helper()
+	received := make(chan model.Event, eventBufferSize)
+	unsubscribe := bus.subscribe(func(event model.Event) {
+		received <- event
+	})
+	wait := func(n int) []model.Event {
+		t.Helper()
+		events := make([]model.Event, 0, n)
+		for len(events) < n {
+			select {
+			case event := <-received:
+				events = append(events, event)
+			case <-time.After(time.Second):
+				t.Fatalf("Expected %d events but got %v", n, events)
+			}
+		}
+		return events
+	}
+	return wait, unsubscribe
+}
+
+func TestEventBus_DeliversInOrder(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	var bus eventBus
+	wait, unsubscribe := collectEvents(t, &bus)
+
+	// WHEN
+	bus.emit(model.Event{Type: model.EventWebsocketConnected, ConnectionID: 1})
+	bus.emit(model.Event{Type: model.EventWebsocketDisconnected, ConnectionID: 1})
+	events := wait(2)
+	unsubscribe()
+	unsubscribe()
+
+	// THEN
+	common.AssertEqual(t, model.EventWebsocketConnected, events[0].Type)
+	common.AssertEqual(t, model.EventWebsocketDisconnected, events[1].Type)
+	if events[0].Time.IsZero() {
+		t.Fatal("Expected event time to be set")
+	}
+	common.AssertEqual(t, 0, bus.emit(model.Event{Type: model.EventHeartbeatSent}))
+}
+
+func TestEventBus_SlowSubscriber_DropsEvents(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	var bus eventBus
+	release := make(chan struct{})
+	unsubscribe := bus.subscribe(func(model.Event) {
+		<-release
+	})
+	defer unsubscribe()
+	defer close(release)
+
+	// WHEN
+	dropped := 0
+	for i := 0; i < eventBufferSize+2; i++ {
+		dropped += bus.emit(model.Event{Type: model.EventHeartbeatSent})
+	}
+
+	// THEN
+	if dropped == 0 {
+		t.Fatal("Expected events to be dropped")
+	}
+}
+
+func TestGameLiftServerState_Events(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{
+		wsGameLift:         manager,
+		events:             &eventBus{},
+		healthCheckTimeout: time.Second,
+		serviceCallTimeout: time.Second,
+	}
+	state.isReadyProcess.Store(true)
+	wait, unsubscribe := collectEvents(t, state.events)
+	defer unsubscribe()
+	heartbeatErr := errors.New("write failed")
+	gomock.InOrder(
+		manager.EXPECT().HandleRequest(gomock.Any(), gomock.Any(), gomock.Any(), time.Second).Return(nil),
+		manager.EXPECT().HandleRequest(gomock.Any(), gomock.Any(), gomock.Any(), time.Second).Return(heartbeatErr),
+	)
+
+	// WHEN
+	state.OnStartGameSession(&model.GameSession{GameSessionID: "test-game-session-id"})
+	state.heartbeatServerProcess(make(chan bool))
+	state.heartbeatServerProcess(make(chan bool))
+	state.OnRequestTimeout("test-request-id")
+
+	// THEN
+	events := wait(4)
+	common.AssertEqual(t, model.EventCreateGameSessionReceived, events[0].Type)
+	common.AssertEqual(t, "test-game-session-id", events[0].GameSessionID)
+	common.AssertEqual(t, model.EventHeartbeatSent, events[1].Type)
+	common.AssertEqual(t, model.EventHeartbeatFailed, events[2].Type)
+	common.AssertEqual(t, heartbeatErr, events[2].Err)
+	common.AssertEqual(t, model.EventRequestTimedOut, events[3].Type)
+	common.AssertEqual(t, "test-request-id", events[3].RequestID)
+}
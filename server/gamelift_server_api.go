@@ -6,21 +6,29 @@
 package server
 
 import (
-	"net/http"
+	"context"
+	"iter"
+	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
-	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
-	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
 )
 
-var srv iGameLiftServerState
-var state gameLiftServerState
-var manager internal.IGameLiftManager
+// defaultClient - the Client used by the package-level functions, created by InitSDK and released by Destroy.
+var defaultClient *Client
+
+// defaultClientOptions - additional options applied to the default Client by InitSDK.
+var defaultClientOptions []Option
+
+// defaultEvents - subscriptions of the default Client. They outlive InitSDK and Destroy, so Subscribe can be
+// called before InitSDK.
+var defaultEvents eventBus
+
+// metricsFactory - the metrics factory of the default Client. It may be initialized before InitSDK is called.
 var metricsFactory metrics.IFactory
 var lg log.ILogger
 
@@ -57,26 +65,21 @@ func GetSdkVersion() (string, error) {
 // InitSDK sets up communication between the server and the Amazon GameLift Servers service.
 //
 //	err := server.InitSDK(serverParameters)
+//
+// If InitSDK fails, call Destroy before calling InitSDK again.
+//
+// To run several independent instances of the server SDK in one binary, use NewClient instead.
 func InitSDK(params ServerParameters) error {
-	if srv != nil {
+	if defaultClient != nil {
 		return common.NewGameLiftError(common.AlreadyInitialized, "", "")
 	}
-	params.ProcessID = common.GetEnvStringOrDefault(common.EnvironmentKeyProcessID, params.ProcessID)
-	if lg == nil {
-		lg = log.GetDefaultLogger(params.ProcessID)
-	}
-	if manager == nil {
-		wsDialer := transport.NewDialer(lg)
-		wsTransport := transport.WithRetry(transport.Websocket(lg, wsDialer), lg)
-		client := internal.GetWebsocketClient(wsTransport, lg)
-		httpClient := &http.Client{}
-		manager = internal.GetGameLiftManager(&state, client, lg, httpClient)
-	}
-	err := state.init(params, manager)
-	srv = &state
-	if metricsFactory != nil {
-		srv.setMetricsFactory(metricsFactory)
-	}
+	c := newClient(append(
+		[]Option{WithLogger(lg), WithMetricsFactory(metricsFactory), withEvents(&defaultEvents)},
+		defaultClientOptions...,
+	)...)
+	err := c.init(params)
+	lg = c.lg
+	defaultClient = c
 	return err
 }
 
@@ -120,12 +123,18 @@ func InitMetrics(metricsParameters MetricsParameters) (*Metrics, error) {
 	if metricsFactory != nil {
 		return nil, common.NewGameLiftError(common.AlreadyInitialized, "Already initialized", "You can only initialize metrics once.")
 	}
-	var err error
-	var localMetrics *Metrics
-	localMetrics, metricsFactory, err = createMetrics(&metricsParameters, srv)
+	var serverState iGameLiftServerState
+	if defaultClient != nil {
+		serverState = &defaultClient.state
+	}
+	localMetrics, localMetricsFactory, err := createMetrics(&metricsParameters, serverState)
 	if err != nil {
 		return nil, err
 	}
+	metricsFactory = localMetricsFactory
+	if defaultClient != nil {
+		defaultClient.metricsFactory = localMetricsFactory
+	}
 	return localMetrics, nil
 }
 
@@ -192,7 +201,21 @@ func InitMetricsFromEnvironment() (*Metrics, error) {
 //
 // err := server.ProcessReady(processParams);
 func ProcessReady(param ProcessParameters) error {
-	return srv.processReady(&param)
+	return defaultClient.ProcessReady(param)
+}
+
+// ProcessReadyContext - same as ProcessReady, but the call is bound by ctx.
+// If ctx is cancelled or its deadline expires before the service responds, the pending request is abandoned
+// and the returned GameLiftError wraps ctx.Err(), so it can be checked with errors.Is.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	err := server.ProcessReadyContext(ctx, processParams)
+//	if errors.Is(err, context.DeadlineExceeded) {
+//		// the service did not answer in time
+//	}
+func ProcessReadyContext(ctx context.Context, param ProcessParameters) error {
+	return defaultClient.ProcessReadyContext(ctx, param)
 }
 
 // ProcessEnding - notifies the Amazon GameLift Servers service that the server process is shutting down.
@@ -208,7 +231,12 @@ func ProcessReady(param ProcessParameters) error {
 //	// otherwise, exit with error code
 //	os.Exit(errorCode)
 func ProcessEnding() error {
-	return srv.processEnding()
+	return defaultClient.ProcessEnding()
+}
+
+// ProcessEndingContext - same as ProcessEnding, but the call is bound by ctx, see ProcessReadyContext.
+func ProcessEndingContext(ctx context.Context) error {
+	return defaultClient.ProcessEndingContext(ctx)
 }
 
 // ActivateGameSession - notifies Amazon GameLift Servers that the server is requesting a game session and is now ready to
@@ -224,7 +252,12 @@ func ProcessEnding() error {
 //		...
 //	}
 func ActivateGameSession() error {
-	return srv.activateGameSession()
+	return defaultClient.ActivateGameSession()
+}
+
+// ActivateGameSessionContext - same as ActivateGameSession, but the call is bound by ctx, see ProcessReadyContext.
+func ActivateGameSessionContext(ctx context.Context) error {
+	return defaultClient.ActivateGameSessionContext(ctx)
 }
 
 // UpdatePlayerSessionCreationPolicy - updates the current game session's ability to accept new player sessions.
@@ -240,7 +273,13 @@ func ActivateGameSession() error {
 //
 // err := server.UpdatePlayerSessionCreationPolicy(model.AcceptAll)
 func UpdatePlayerSessionCreationPolicy(policy model.PlayerSessionCreationPolicy) error {
-	return srv.updatePlayerSessionCreationPolicy(&policy)
+	return defaultClient.UpdatePlayerSessionCreationPolicy(policy)
+}
+
+// UpdatePlayerSessionCreationPolicyContext - same as UpdatePlayerSessionCreationPolicy,
+// but the call is bound by ctx, see ProcessReadyContext.
+func UpdatePlayerSessionCreationPolicyContext(ctx context.Context, policy model.PlayerSessionCreationPolicy) error {
+	return defaultClient.UpdatePlayerSessionCreationPolicyContext(ctx, policy)
 }
 
 // GetGameSessionID - retrieves the ID of the game session currently being hosted by the server process,
@@ -251,7 +290,7 @@ func UpdatePlayerSessionCreationPolicy(policy model.PlayerSessionCreationPolicy)
 //
 // gameSessionID, err := server.GetGameSessionID()
 func GetGameSessionID() (string, error) {
-	return srv.getGameSessionID()
+	return defaultClient.GetGameSessionID()
 }
 
 // GetTerminationTime - returns the timestamp in epoch seconds that a server process is scheduled to be shut down,
@@ -273,7 +312,44 @@ func GetGameSessionID() (string, error) {
 //
 // terminationTime, err := server.GetTerminationTime()
 func GetTerminationTime() (int64, error) {
-	return srv.getTerminationTime()
+	return defaultClient.GetTerminationTime()
+}
+
+// GetMatchmakerData - returns the matchmaker data last received for the game session hosted by the process,
+// parsed from model.GameSession.MatchmakerData of the CreateGameSession or the latest UpdateGameSession message.
+// ProcessParameters.OnUpdateGameSession receives the differences from the previous matchmaker data
+// in model.UpdateGameSession.MatchmakerDataDiff.
+//
+// If no game session is bound to the process, or none of its matchmaker data could be parsed,
+// returns a common.GamesessionIDNotSet error.
+//
+//	data, err := server.GetMatchmakerData()
+func GetMatchmakerData() (model.MatchmakerData, error) {
+	return defaultClient.GetMatchmakerData()
+}
+
+// GetProcessState - returns the current lifecycle state of the server process, see model.ProcessState.
+// The server SDK moves through the states as ProcessReady, ActivateGameSession and ProcessEnding succeed and as
+// game session and termination messages arrive from Amazon GameLift Servers.
+// Calls that are not allowed in the current state fail with a common.IllegalStateTransition error
+// before any request is sent.
+//
+//	if server.GetProcessState() == model.ProcessSessionActive {
+//		// accept players
+//	}
+func GetProcessState() model.ProcessState {
+	return defaultClient.GetProcessState()
+}
+
+// GetProcessStateHistory - returns all lifecycle state transitions of the server process, oldest first.
+// Each model.ProcessStateTransition holds the previous and the new state, the call or message that caused it
+// and the time it happened.
+//
+//	for _, transition := range server.GetProcessStateHistory() {
+//		fmt.Printf("%s: %s -> %s (%s)\n", transition.Time, transition.From.String(), transition.To.String(), transition.Trigger)
+//	}
+func GetProcessStateHistory() []model.ProcessStateTransition {
+	return defaultClient.GetProcessStateHistory()
 }
 
 // AcceptPlayerSession - notifies the Amazon GameLift Servers service that a player with the specified player session ID has connected
@@ -294,7 +370,85 @@ func GetTerminationTime() (int64, error) {
 //			}
 //		}
 func AcceptPlayerSession(playerSessionID string) error {
-	return srv.acceptPlayerSession(playerSessionID)
+	return defaultClient.AcceptPlayerSession(playerSessionID)
+}
+
+// AcceptPlayerSessionContext - same as AcceptPlayerSession, but the call is bound by ctx, see ProcessReadyContext.
+func AcceptPlayerSessionContext(ctx context.Context, playerSessionID string) error {
+	return defaultClient.AcceptPlayerSessionContext(ctx, playerSessionID)
+}
+
+// AcceptPlayerSessionWithMetadata - same as AcceptPlayerSessionContext, and records connection metadata,
+// such as the remote address of the player, with the accepted player session.
+// The server SDK keeps every accepted player session until RemovePlayerSession is called for it,
+// and refuses to accept the same player session twice with a common.ConflictException error.
+//
+//	err := server.AcceptPlayerSessionWithMetadata(ctx, playerSessionID, map[string]string{"remoteAddr": conn.RemoteAddr().String()})
+func AcceptPlayerSessionWithMetadata(ctx context.Context, playerSessionID string, metadata map[string]string) error {
+	return defaultClient.AcceptPlayerSessionWithMetadata(ctx, playerSessionID, metadata)
+}
+
+// ListAcceptedPlayerSessions - returns the player sessions accepted with AcceptPlayerSession and not yet removed
+// with RemovePlayerSession, oldest first.
+//
+//	for _, session := range server.ListAcceptedPlayerSessions() {
+//		fmt.Printf("%s accepted at %s\n", session.PlayerSessionID, session.AcceptedAt)
+//	}
+func ListAcceptedPlayerSessions() []AcceptedPlayerSession {
+	return defaultClient.ListAcceptedPlayerSessions()
+}
+
+// ReconcilePlayerSessions - compares the player sessions accepted by the server process with the ACTIVE player
+// sessions of the current game session reported by DescribePlayerSessions, and returns the differences.
+// To reconcile periodically, set ProcessParameters.Reconciliation instead.
+//
+//	drift, err := server.ReconcilePlayerSessions(ctx)
+//	if err == nil && drift.HasDrift() {
+//		// for example, disconnect drift.Unknown players
+//	}
+func ReconcilePlayerSessions(ctx context.Context) (PlayerSessionDrift, error) {
+	return defaultClient.ReconcilePlayerSessions(ctx)
+}
+
+// Subscribe - registers a handler for connection and lifecycle events of the server SDK, see model.EventType.
+// Subscribe can be called before InitSDK to observe the initial connection. Each handler receives events
+// in order on its own goroutine; if a handler falls behind by more than 64 events, new events are dropped for it.
+// Returns a function that unregisters the handler.
+//
+//	unsubscribe := server.Subscribe(func(event model.Event) {
+//		if event.Type == model.EventWebsocketDisconnected {
+//			fmt.Printf("connection %d lost: %v\n", event.ConnectionID, event.Err)
+//		}
+//	})
+//	defer unsubscribe()
+func Subscribe(handler func(model.Event)) func() {
+	return defaultEvents.subscribe(handler)
+}
+
+// RegisterHealthCheck - adds a named health check to the heartbeat of the server process.
+// On every heartbeat all registered checks run concurrently within the health check timeout,
+// each one also limited by its own timeout if it is positive. The server process is reported healthy
+// only if every check returns nil and ProcessParameters.OnHealthCheck, if set, returns true.
+// Failed checks are logged, and when metrics are initialized each check reports a
+// "server_sdk.health_check.<name>" gauge set to 1 when healthy and 0 otherwise.
+//
+//	err := server.RegisterHealthCheck("database", func(ctx context.Context) error {
+//		return db.PingContext(ctx)
+//	}, 5*time.Second)
+func RegisterHealthCheck(name string, check HealthCheckFunc, timeout time.Duration) error {
+	return defaultClient.RegisterHealthCheck(name, check, timeout)
+}
+
+// HealthCheckResults - returns the last result of every health check registered with RegisterHealthCheck,
+// ordered by name. Use it to find out which dependency made the server process unhealthy.
+//
+//	for _, res := range server.HealthCheckResults() {
+//		if !res.Healthy {
+//			fmt.Printf("%s failed at %s: %v\n", res.Name, res.CheckedAt, res.Err)
+//		}
+//	}
+func HealthCheckResults() []HealthCheckResult {
+	return defaultClient.HealthCheckResults()
 }
 
 // RemovePlayerSession - notifies the Amazon GameLift Servers service that a player with the specified player session ID
@@ -307,7 +461,12 @@ func AcceptPlayerSession(playerSessionID string) error {
 //
 // err := server.RemovePlayerSession(playerSessionID)
 func RemovePlayerSession(playerSessionID string) error {
-	return srv.removePlayerSession(playerSessionID)
+	return defaultClient.RemovePlayerSession(playerSessionID)
+}
+
+// RemovePlayerSessionContext - same as RemovePlayerSession, but the call is bound by ctx, see ProcessReadyContext.
+func RemovePlayerSessionContext(ctx context.Context, playerSessionID string) error {
+	return defaultClient.RemovePlayerSessionContext(ctx, playerSessionID)
 }
 
 // DescribePlayerSessions - retrieves player session data, including settings, session metadata, and player data.
@@ -330,7 +489,33 @@ func RemovePlayerSession(playerSessionID string) error {
 //	describePlayerSessionsRequest.PlayerSessionStatusFilter = "ACTIVE" // All player sessions actively connected to a specified game session
 //	describePlayerSessionsResult, err := server.DescribePlayerSessions(describePlayerSessionsRequest)
 func DescribePlayerSessions(req request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error) {
-	return srv.describePlayerSessions(&req)
+	return defaultClient.DescribePlayerSessions(req)
+}
+
+// DescribePlayerSessionsContext - same as DescribePlayerSessions, but the call is bound by ctx, see ProcessReadyContext.
+func DescribePlayerSessionsContext(
+	ctx context.Context,
+	req request.DescribePlayerSessionsRequest,
+) (result.DescribePlayerSessionsResult, error) {
+	return defaultClient.DescribePlayerSessionsContext(ctx, req)
+}
+
+// AllPlayerSessions - returns an iterator over the player sessions matching req, following NextToken across pages.
+// Each page is requested with DescribePlayerSessionsContext when the previous one is consumed, and holds up to
+// req.Limit player sessions. Iteration starts at req.NextToken, if set, and stops at the first error,
+// which is yielded with an empty player session. Use CollectPlayerSessions to gather a bounded number of sessions.
+//
+//	req := request.NewDescribePlayerSessions()
+//	req.GameSessionID = gameSessionID
+//	req.Limit = 50
+//	for session, err := range server.AllPlayerSessions(ctx, req) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func AllPlayerSessions(ctx context.Context, req request.DescribePlayerSessionsRequest) iter.Seq2[model.PlayerSession, error] {
+	return defaultClient.AllPlayerSessions(ctx, req)
 }
 
 // StartMatchBackfill - sends a request to find new players for open slots in a game session created with FlexMatch.
@@ -371,7 +556,64 @@ func DescribePlayerSessions(req request.DescribePlayerSessionsRequest) (result.D
 //		// game-specific tasks to prepare for the newly matched players and update matchmaker data as needed
 //	}
 func StartMatchBackfill(req request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, error) {
-	return srv.startMatchBackfill(&req)
+	return defaultClient.StartMatchBackfill(req)
+}
+
+// StartMatchBackfillContext - same as StartMatchBackfill, but the call is bound by ctx, see ProcessReadyContext.
+func StartMatchBackfillContext(
+	ctx context.Context,
+	req request.StartMatchBackfillRequest,
+) (result.StartMatchBackfillResult, error) {
+	return defaultClient.StartMatchBackfillContext(ctx, req)
+}
+
+// BuildStartMatchBackfillRequestForPlayers - builds a StartMatchBackfillRequest for the current game session
+// from its last matchmaker data, see GetMatchmakerData, keeping only the specified players that are still connected.
+// Each player keeps the team, attributes and latencies assigned by FlexMatch, with latencies recorded with
+// RecordPlayerLatency taking precedence, and the game session ARN and matchmaking configuration ARN are filled in. The request is validated with ValidateStartMatchBackfillRequest.
+//
+// Players that left the game session must not be sent in a match backfill request, otherwise FlexMatch keeps
+// their slots filled.
+//
+//	req, err := server.BuildStartMatchBackfillRequestForPlayers(connectedPlayerIDs)
+//	if err != nil {
+//		return err
+//	}
+//	res, err := server.StartMatchBackfill(req)
+func BuildStartMatchBackfillRequestForPlayers(playerIDs []string) (request.StartMatchBackfillRequest, error) {
+	return defaultClient.BuildStartMatchBackfillRequestForPlayers(playerIDs)
+}
+
+// BuildStartMatchBackfillRequestForPlayerSessions - same as BuildStartMatchBackfillRequestForPlayers, but for the
+// players of the specified player sessions, for example the ones returned by ListAcceptedPlayerSessions.
+// The players are resolved with DescribePlayerSessions; a player session that is not ACTIVE
+// fails with a common.ValidationException error.
+func BuildStartMatchBackfillRequestForPlayerSessions(
+	ctx context.Context,
+	playerSessionIDs []string,
+) (request.StartMatchBackfillRequest, error) {
+	return defaultClient.BuildStartMatchBackfillRequestForPlayerSessions(ctx, playerSessionIDs)
+}
+
+// RecordPlayerLatency - records a latency sample of a player to a Region, measured by the game at runtime,
+// for example from pings to the Region. The samples are smoothed with a moving average and replace the
+// latencies reported at matchmaking, see model.Player.LatencyInMS, in match backfill requests built with
+// BuildStartMatchBackfillRequestForPlayers and BuildStartMatchBackfillRequestForPlayerSessions.
+// The samples are forgotten when a new game session starts.
+// Returns a common.ValidationException error if the player or the Region is empty, or if the latency is not positive.
+//
+//	err := server.RecordPlayerLatency(playerID, "us-west-2", rtt)
+func RecordPlayerLatency(playerID, region string, latency time.Duration) error {
+	return defaultClient.RecordPlayerLatency(playerID, region, latency)
+}
+
+// ApplyObservedLatencies - returns copies of the players with the latencies recorded with RecordPlayerLatency
+// replacing the reported ones, for example to balance teams by ping with model.TeamRegionLatencies.
+//
+//	data, _ := server.GetMatchmakerData()
+//	region, stats, ok := model.BestRegion(server.ApplyObservedLatencies(data.Players))
+func ApplyObservedLatencies(players []model.Player) []model.Player {
+	return defaultClient.ApplyObservedLatencies(players)
 }
 
 // StopMatchBackfill - cancels an active match backfill request that was created with StartMatchBackfill().
@@ -390,7 +632,12 @@ func StartMatchBackfill(req request.StartMatchBackfillRequest) (result.StartMatc
 //	stopBackfillRequest.MatchmakingConfigurationArn = "the matchmaker configuration ARN" // from the game session matchmaker data
 //	err := server.StopMatchBackfill(stopBackfillRequest)
 func StopMatchBackfill(req request.StopMatchBackfillRequest) error {
-	return srv.stopMatchBackfill(&req)
+	return defaultClient.StopMatchBackfill(req)
+}
+
+// StopMatchBackfillContext - same as StopMatchBackfill, but the call is bound by ctx, see ProcessReadyContext.
+func StopMatchBackfillContext(ctx context.Context, req request.StopMatchBackfillRequest) error {
+	return defaultClient.StopMatchBackfillContext(ctx, req)
 }
 
 // GetComputeCertificate - retrieves the path to TLS certificate used to encrypt the network connection between your
@@ -404,7 +651,12 @@ func StopMatchBackfill(req request.StopMatchBackfillRequest) error {
 //
 // tlsCertificate, err := server.GetComputeCertificate()
 func GetComputeCertificate() (result.GetComputeCertificateResult, error) {
-	return srv.getComputeCertificate()
+	return defaultClient.GetComputeCertificate()
+}
+
+// GetComputeCertificateContext - same as GetComputeCertificate, but the call is bound by ctx, see ProcessReadyContext.
+func GetComputeCertificateContext(ctx context.Context) (result.GetComputeCertificateResult, error) {
+	return defaultClient.GetComputeCertificateContext(ctx)
 }
 
 // ListContainersNetworkInfo - retrieves network information for all containers running on the same instance.
@@ -422,7 +674,13 @@ func GetComputeCertificate() (result.GetComputeCertificateResult, error) {
 //	    fmt.Printf("Container %s at %s\n", container.ContainerName, container.IPAddress)
 //	}
 func ListContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error) {
-	return srv.listContainersNetworkInfo()
+	return defaultClient.ListContainersNetworkInfo()
+}
+
+// ListContainersNetworkInfoContext - same as ListContainersNetworkInfo, but the requests to the discovery server
+// are abandoned when ctx is done.
+func ListContainersNetworkInfoContext(ctx context.Context) (result.ListContainersNetworkInfoResult, error) {
+	return defaultClient.ListContainersNetworkInfoContext(ctx)
 }
 
 // GetFleetRoleCredentials - retrieves the service role credentials you created to extend permissions to
@@ -447,7 +705,15 @@ func ListContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error)
 func GetFleetRoleCredentials(
 	req request.GetFleetRoleCredentialsRequest,
 ) (result.GetFleetRoleCredentialsResult, error) {
-	return srv.getFleetRoleCredentials(&req)
+	return defaultClient.GetFleetRoleCredentials(req)
+}
+
+// GetFleetRoleCredentialsContext - same as GetFleetRoleCredentials, but the call is bound by ctx, see ProcessReadyContext.
+func GetFleetRoleCredentialsContext(
+	ctx context.Context,
+	req request.GetFleetRoleCredentialsRequest,
+) (result.GetFleetRoleCredentialsResult, error) {
+	return defaultClient.GetFleetRoleCredentialsContext(ctx, req)
 }
 
 // Destroy - deletes the instance of the server SDK on your resource.
@@ -465,14 +731,14 @@ func GetFleetRoleCredentials(
 //		}
 //	}
 func Destroy() error {
-	if srv != nil {
-		if err := srv.destroy(); err != nil {
+	if defaultClient != nil {
+		if err := defaultClient.Destroy(); err != nil {
 			return err
 		}
 	}
-	terminateMetricsFactory(metricsFactory)
-	manager = nil
-	srv = nil
+	// The default Client receives the factory of InitMetrics through WithMetricsFactory, so it is terminated here.
+	terminateMetricsFactory(metricsFactory, lg)
+	defaultClient = nil
 	metricsFactory = nil
 	return nil
 }
@@ -28,6 +28,7 @@ func cleanUpInitSdkTest(t *testing.T, ctrl *gomock.Controller) {
 	if err != nil {
 		t.Fatalf("Failed to call Destroy during clean up: %s", err)
 	}
+	defaultClientOptions = nil
 	ctrl.Finish()
 }
 
@@ -77,7 +78,7 @@ func clearEnvironmentVariables(t *testing.T) {
 
 func mockGameLiftManager(ctrl *gomock.Controller) *mock.MockIGameLiftManager {
 	mockManager := mock.NewMockIGameLiftManager(ctrl)
-	manager = mockManager
+	defaultClientOptions = []Option{withGameLiftManager(mockManager)}
 	return mockManager
 }
 
@@ -560,7 +561,7 @@ func TestInitSDKFromEnvironment_WithLogger(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected a validation error because there is no environment state")
 	}
-	common.AssertEqual(t, logger, manager.GetLogger())
+	common.AssertEqual(t, logger, defaultClient.manager.GetLogger())
 }
 
 func TestDestroy(t *testing.T) {
@@ -579,19 +580,22 @@ func TestDestroy(t *testing.T) {
 	mockSuccessfulConnect(mockManager, 1, inputParameters)
 	mockManager.EXPECT().Disconnect().Times(1)
 
+	defer func() { defaultClientOptions = nil }()
+
 	// WHEN
 	if err := InitSDK(inputParameters); err != nil {
 		t.Fatal(err)
 	}
+	client := defaultClient
 	if err := Destroy(); err != nil {
 		t.Fatal(err)
 	}
 
 	// THEN
-	if manager != nil {
+	if client.manager != nil {
 		t.Fatal("Manager should be uninitialized")
 	}
-	if srv != nil {
+	if defaultClient != nil {
 		t.Fatal("Server should be uninitialized")
 	}
 }
@@ -654,14 +658,14 @@ func TestDestroy_StopMetricsFactory(t *testing.T) {
 
 	mockFactory := &mockFactory{stopCalled: false}
 	metricsFactory = mockFactory
-	srv = nil
+	defaultClient = nil
 
 	// WHEN: Destroy is called
 	Destroy()
 
 	// THEN: State should be reset (TerminateMetricsProcessor is called internally)
-	if srv != nil {
-		t.Error("Expected srv to be nil after Destroy")
+	if defaultClient != nil {
+		t.Error("Expected defaultClient to be nil after Destroy")
 	}
 	if metricsFactory != nil {
 		t.Error("Expected metricsFactory to be nil after Destroy")
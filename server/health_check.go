@@ -0,0 +1,188 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+// healthCheckMetricPrefix - prefix of the per-check gauge, set to 1 when the check passed and to 0 otherwise.
+const healthCheckMetricPrefix = "server_sdk.health_check."
+
+// healthChecksTimeoutPercent - the share of the health check timeout given to the registered checks.
+const healthChecksTimeoutPercent = 80
+
+// HealthCheckFunc - checks a single dependency of the server process.
+// Returns nil if the dependency is healthy. The check should return when ctx is done.
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthCheckResult - the last result of a health check registered with RegisterHealthCheck.
+type HealthCheckResult struct {
+	Name string
+	// Healthy - true if the check returned nil within its timeout.
+	Healthy bool
+	// Err - the error returned by the check, or a context error if the check timed out.
+	Err error
+	// CheckedAt - the time the check was started. It is zero if the check has not run yet.
+	CheckedAt time.Time
+	// Duration - how long the check took.
+	Duration time.Duration
+}
+
+type registeredHealthCheck struct {
+	check   HealthCheckFunc
+	timeout time.Duration
+	last    HealthCheckResult
+	gauge   *metrics.Gauge
+}
+
+// healthCheckRegistry - named health checks aggregated into the heartbeat status.
+// The zero value is an empty registry.
+type healthCheckRegistry struct {
+	mtx    sync.RWMutex
+	checks map[string]*registeredHealthCheck
+}
+
+// register - adds a named health check.
+// Returns a BadRequestException error if the name is empty, check is nil or timeout is negative,
+// and a ConflictException error if a check with the same name is already registered.
+func (r *healthCheckRegistry) register(name string, check HealthCheckFunc, timeout time.Duration) error {
+	if name == "" || check == nil || timeout < 0 {
+		return common.NewGameLiftError(common.BadRequestException, "",
+			"Health check requires a name, a check function and a non-negative timeout.")
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.checks[name]; ok {
+		return common.NewGameLiftError(common.ConflictException, "",
+			fmt.Sprintf("Health check %s is already registered.", name))
+	}
+	if r.checks == nil {
+		r.checks = make(map[string]*registeredHealthCheck)
+	}
+	r.checks[name] = &registeredHealthCheck{
+		check:   check,
+		timeout: timeout,
+		last:    HealthCheckResult{Name: name},
+	}
+	return nil
+}
+
+// empty - reports whether no health check is registered.
+func (r *healthCheckRegistry) empty() bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return len(r.checks) == 0
+}
+
+// results - returns the last result of every registered check, ordered by name.
+func (r *healthCheckRegistry) results() []HealthCheckResult {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	results := make([]HealthCheckResult, 0, len(r.checks))
+	for _, c := range r.checks {
+		results = append(results, c.last)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// run - runs all registered checks concurrently until ctx is done and records their results.
+// Each check is additionally limited by its own timeout, if set.
+// Reports whether every check passed, and emits one gauge per check if factory is not nil.
+func (r *healthCheckRegistry) run(ctx context.Context, factory metrics.IFactory, logger log.ILogger) bool {
+	r.mtx.RLock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	r.mtx.RUnlock()
+
+	results := make([]HealthCheckResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		r.mtx.RLock()
+		c := r.checks[name]
+		r.mtx.RUnlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, name, c.check, c.timeout)
+		}()
+	}
+	wg.Wait()
+
+	healthy := true
+	var failures []string
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for _, res := range results {
+		c := r.checks[res.Name]
+		c.last = res
+		if !res.Healthy {
+			healthy = false
+			failures = append(failures, fmt.Sprintf("%s: %v", res.Name, res.Err))
+		}
+		if factory == nil {
+			continue
+		}
+		if c.gauge == nil {
+			gauge, err := factory.Gauge(healthCheckMetricPrefix + res.Name)
+			if err != nil {
+				logger.Debugf("Could not create gauge for health check %s: %s", res.Name, err)
+				continue
+			}
+			if gauge == nil {
+				continue
+			}
+			c.gauge = gauge
+		}
+		if res.Healthy {
+			c.gauge.Set(1)
+		} else {
+			c.gauge.Set(0)
+		}
+	}
+	if !healthy {
+		sort.Strings(failures)
+		logger.Warnf("Health checks failed: %s", strings.Join(failures, "; "))
+	}
+	return healthy
+}
+
+// runHealthCheck - runs a single check and waits for it no longer than ctx and timeout allow,
+// so that a check ignoring its context does not delay the heartbeat.
+func runHealthCheck(ctx context.Context, name string, check HealthCheckFunc, timeout time.Duration) HealthCheckResult {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	res := HealthCheckResult{Name: name, CheckedAt: time.Now()}
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+	select {
+	case res.Err = <-done:
+	case <-ctx.Done():
+		res.Err = ctx.Err()
+	}
+	res.Duration = time.Since(res.CheckedAt)
+	res.Healthy = res.Err == nil
+	return res
+}
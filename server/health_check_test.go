@@ -0,0 +1,291 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+)
+
+// gaugeRecordingFactory - records the keys of the requested gauges.
+type gaugeRecordingFactory struct {
+	mockFactory
+	mtx    sync.Mutex
+	gauges []string
+}
+
+func (f *gaugeRecordingFactory) Gauge(key string) (*metrics.Gauge, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.gauges = append(f.gauges, key)
+	return nil, nil
+}
+
+func TestHealthCheckRegistry_Register_ReturnError(t *testing.T) {
+	// GIVEN
+	var registry healthCheckRegistry
+	check := func(context.Context) error { return nil }
+	if err := registry.register("database", check, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	errs := map[common.GameLiftErrorType]error{
+		common.ConflictException:   registry.register("database", check, time.Second),
+		common.BadRequestException: registry.register("", check, time.Second),
+	}
+
+	// THEN
+	for errorType, err := range errs {
+		var gameLiftErr *common.GameLiftError
+		if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != errorType {
+			t.Fatalf("Expected %v error but got %v", errorType, err)
+		}
+	}
+	if err := registry.register("cache", nil, time.Second); err == nil {
+		t.Fatal("Expected error for nil check")
+	}
+	if err := registry.register("cache", check, -time.Second); err == nil {
+		t.Fatal("Expected error for negative timeout")
+	}
+}
+
+func TestHealthCheckRegistry_Run(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	var registry healthCheckRegistry
+	release := make(chan struct{})
+	defer close(release)
+	checkErr := errors.New("connection refused")
+	checks := map[string]HealthCheckFunc{
+		"healthy": func(context.Context) error { return nil },
+		"failing": func(context.Context) error { return checkErr },
+		"slow": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		"stuck": func(context.Context) error {
+			<-release
+			return nil
+		},
+	}
+	for name, check := range checks {
+		if err := registry.register(name, check, 20*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	factory := &gaugeRecordingFactory{}
+
+	// WHEN
+	start := time.Now()
+	healthy := registry.run(context.Background(), factory, lg)
+
+	// THEN
+	if healthy {
+		t.Fatal("Expected unhealthy status")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Expected checks to respect their timeout, took %s", elapsed)
+	}
+	results := registry.results()
+	common.AssertEqual(t, 4, len(results))
+	common.AssertEqual(t, "failing", results[0].Name)
+	common.AssertEqual(t, checkErr, results[0].Err)
+	common.AssertEqual(t, "healthy", results[1].Name)
+	common.AssertEqual(t, true, results[1].Healthy)
+	common.AssertEqual(t, context.DeadlineExceeded, results[2].Err)
+	common.AssertEqual(t, context.DeadlineExceeded, results[3].Err)
+	for _, res := range results {
+		if res.CheckedAt.IsZero() {
+			t.Fatalf("Expected %s to record its check time", res.Name)
+		}
+	}
+	common.AssertEqual(t, 4, len(factory.gauges))
+	common.AssertContains(t, factory.gauges[0], healthCheckMetricPrefix)
+}
+
+func TestGameLiftServerState_HeartbeatServerProcess_WithFailingHealthCheck_ReportUnhealthy(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{
+		wsGameLift:         manager,
+		parameters:         &ProcessParameters{OnHealthCheck: func() bool { return true }},
+		healthCheckTimeout: time.Second,
+		serviceCallTimeout: time.Second,
+	}
+	if err := state.registerHealthCheck("database", func(context.Context) error {
+		return errors.New("connection refused")
+	}, 0); err != nil {
+		t.Fatal(err)
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(false)), gomock.Any(), time.Second).
+		Times(1)
+
+	// WHEN
+	state.heartbeatServerProcess(make(chan bool))
+
+	// THEN
+	results := state.healthCheckResults()
+	common.AssertEqual(t, 1, len(results))
+	common.AssertEqual(t, false, results[0].Healthy)
+}
+
+func TestGameLiftServerState_HeartbeatServerProcess_WithHealthChecksOnly_ReportHealthy(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{
+		wsGameLift:         manager,
+		healthCheckTimeout: time.Second,
+		serviceCallTimeout: time.Second,
+	}
+	if err := state.registerHealthCheck("database", func(context.Context) error { return nil }, 0); err != nil {
+		t.Fatal(err)
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), time.Second).
+		Times(1)
+
+	// WHEN
+	state.heartbeatServerProcess(make(chan bool))
+
+	// THEN
+	common.AssertEqual(t, true, state.healthCheckResults()[0].Healthy)
+}
+
+func TestGameLiftServerState_HeartbeatServerProcess_WithSlowHealthCheck_ReportCheckResult(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{
+		wsGameLift:         manager,
+		healthCheckTimeout: 100 * time.Millisecond,
+		serviceCallTimeout: time.Second,
+	}
+	if err := state.registerHealthCheck("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, 0); err != nil {
+		t.Fatal(err)
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(false)), gomock.Any(), time.Second).
+		Times(1)
+
+	// WHEN
+	state.heartbeatServerProcess(make(chan bool))
+
+	// THEN
+	results := state.healthCheckResults()
+	common.AssertEqual(t, 1, len(results))
+	common.AssertEqual(t, context.DeadlineExceeded, results[0].Err)
+}
+
+func TestGameLiftServerState_HeartbeatServerProcess_RunsOnHealthCheckWithHealthChecks(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	callbackStarted := make(chan struct{})
+	state := gameLiftServerState{
+		wsGameLift: manager,
+		parameters: &ProcessParameters{OnHealthCheck: func() bool {
+			close(callbackStarted)
+			return true
+		}},
+		healthCheckTimeout: time.Second,
+		serviceCallTimeout: time.Second,
+	}
+	if err := state.registerHealthCheck("database", func(ctx context.Context) error {
+		// Passes only if the callback runs while the check is still running.
+		select {
+		case <-callbackStarted:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, 0); err != nil {
+		t.Fatal(err)
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), time.Second).
+		Times(1)
+
+	// WHEN
+	state.heartbeatServerProcess(make(chan bool))
+
+	// THEN
+	common.AssertEqual(t, true, state.healthCheckResults()[0].Healthy)
+}
+
+func TestGameLiftServerState_HeartbeatServerProcess_WithSlowOnHealthCheckAndHealthChecks_ReportUnhealthy(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	release := make(chan struct{})
+	defer close(release)
+	state := gameLiftServerState{
+		wsGameLift: manager,
+		parameters: &ProcessParameters{OnHealthCheck: func() bool {
+			<-release
+			return true
+		}},
+		healthCheckTimeout: 100 * time.Millisecond,
+		serviceCallTimeout: time.Second,
+	}
+	if err := state.registerHealthCheck("database", func(context.Context) error { return nil }, 0); err != nil {
+		t.Fatal(err)
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(false)), gomock.Any(), time.Second).
+		Times(1)
+
+	// WHEN
+	state.heartbeatServerProcess(make(chan bool))
+
+	// THEN
+	common.AssertEqual(t, true, state.healthCheckResults()[0].Healthy)
+}
+
+func TestGameLiftServerState_HeartbeatServerProcess_WithSlowOnHealthCheck_DoesNotLeakGoroutine(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	release := make(chan struct{})
+	returned := make(chan struct{})
+	state := gameLiftServerState{
+		wsGameLift: manager,
+		parameters: &ProcessParameters{OnHealthCheck: func() bool {
+			defer close(returned)
+			<-release
+			return true
+		}},
+		healthCheckTimeout: 10 * time.Millisecond,
+		serviceCallTimeout: time.Second,
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(false)), gomock.Any(), time.Second).
+		Times(1)
+
+	// WHEN
+	state.heartbeatServerProcess(make(chan bool))
+	close(release)
+
+	// THEN
+	<-returned
+}
@@ -8,6 +8,7 @@ package internal
 import (
 	"sync/atomic"
 
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
 )
@@ -19,6 +20,11 @@ func (c *WebsocketClient) Init(transport transport.ITransport, logger log.ILogge
 	c.init(transport, logger)
 }
 
+// RunEventHandler expose access private handleEvent method for testing purposes
+func (c *WebsocketClient) RunEventHandler(event model.Event) {
+	c.handleEvent(event)
+}
+
 // RunReadHandler expose access private readHandler method for testing purposes
 func (c *WebsocketClient) RunReadHandler(data []byte) {
 	c.readHandler(data)
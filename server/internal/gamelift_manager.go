@@ -6,6 +6,7 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 	"time"
@@ -26,10 +27,10 @@ import (
 type IGameLiftManager interface {
 	Connect(websocketURL, processID, hostID, fleetID, authToken string, sigV4QueryParameters map[string]string) error
 	Disconnect() error
-	HandleRequest(request MessageGetter, response any, timeout time.Duration) error
+	HandleRequest(ctx context.Context, request MessageGetter, response any, timeout time.Duration) error
 	FetchCredentials(computeType string) (*security.AwsCredentials, error)
 	FetchMetadata(computeType string) (security.ComputeMetadata, error)
-	FetchContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error)
+	FetchContainersNetworkInfo(ctx context.Context) (result.ListContainersNetworkInfoResult, error)
 	GetLogger() log.ILogger
 }
 
@@ -102,10 +103,17 @@ func (manager *gameLiftManager) Disconnect() error {
 }
 
 // HandleRequest - send a request wait the response and parse it
-// return error if timeout was expired or send request failed or can not parse answer.
-func (manager *gameLiftManager) HandleRequest(request MessageGetter, response any, timeout time.Duration) error {
+// return error if timeout was expired, ctx was done, send request failed or can not parse answer.
+// When ctx is done first, the pending request is cancelled and ctx.Err() is returned wrapped in a GameLiftError.
+func (manager *gameLiftManager) HandleRequest(ctx context.Context, request MessageGetter, response any, timeout time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return common.WrapGameLiftError(common.ServiceCallFailed, err)
+	}
+	// The request is not written once the caller stopped waiting for the response.
+	writeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	respData := make(chan common.Outcome, 1)
-	if err := manager.client.SendRequest(request, respData); err != nil {
+	if err := manager.client.SendRequest(writeCtx, request, respData); err != nil {
 		return err
 	}
 
@@ -118,7 +126,14 @@ func (manager *gameLiftManager) HandleRequest(request MessageGetter, response an
 		// the threshold is crossed. The call is non-blocking: reconnect work happens on a
 		// background goroutine so this caller still returns ServiceCallFailed promptly.
 		manager.client.NotifyRequestTimeout()
+		manager.handlers.OnRequestTimeout(request.GetMessage().RequestID)
 		return common.NewGameLiftError(common.ServiceCallFailed, "", "")
+	case <-ctx.Done():
+		// Caller-imposed deadlines say nothing about the transport health, so they do not count
+		// towards the consecutive-timeout reconnect threshold.
+		manager.client.CancelRequest(request.GetMessage().RequestID)
+		manager.lg.Debugf("Request %s cancelled by context: %s", request.GetMessage().RequestID, ctx.Err())
+		return common.WrapGameLiftError(common.ServiceCallFailed, ctx.Err())
 	case resultData := <-respData:
 		if resultData.Error != nil {
 			return resultData.Error
@@ -214,10 +229,10 @@ func (manager *gameLiftManager) FetchMetadata(computeType string) (security.Comp
 	return containerTaskMetadata, nil
 }
 
-func (manager *gameLiftManager) FetchContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error) {
+func (manager *gameLiftManager) FetchContainersNetworkInfo(ctx context.Context) (result.ListContainersNetworkInfoResult, error) {
 	fetcher, err := security.NewContainerNetworkInfoFetcher(manager.httpClient)
 	if err != nil {
 		return result.ListContainersNetworkInfoResult{}, err
 	}
-	return fetcher.FetchContainersNetworkInfo()
+	return fetcher.FetchContainersNetworkInfoContext(ctx)
 }
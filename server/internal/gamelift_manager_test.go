@@ -6,6 +6,7 @@
 package internal_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -113,8 +114,8 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, resp chan<- common.Outcome) error {
 			resp <- common.Outcome{Data: []byte(rawResponse)}
 			return nil
 		})
@@ -135,7 +136,7 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 		},
 	}
 
-	if err := gm.HandleRequest(req, &resp, timeDuration); err != nil {
+	if err := gm.HandleRequest(context.Background(), req, &resp, timeDuration); err != nil {
 		t.Fatal(err)
 	}
 
@@ -145,8 +146,8 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, resp chan<- common.Outcome) error {
 			time.Sleep(time.Millisecond * 5)
 			return nil
 		})
@@ -164,7 +165,11 @@ func TestGameliftManagerHandleRequest_AuthTokenPassed(t *testing.T) {
 		EXPECT().
 		NotifyRequestTimeout()
 
-	err = gm.HandleRequest(req, &resp, timeDuration)
+	gameliftMessageHandlerMock.
+		EXPECT().
+		OnRequestTimeout(req.RequestID)
+
+	err = gm.HandleRequest(context.Background(), req, &resp, timeDuration)
 	if err == nil {
 		t.Fatal(err)
 	}
@@ -266,8 +271,8 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, resp chan<- common.Outcome) error {
 			resp <- common.Outcome{Data: []byte(rawResponse)}
 			return nil
 		})
@@ -288,7 +293,7 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 		},
 	}
 
-	if err := gm.HandleRequest(req, &resp, timeDuration); err != nil {
+	if err := gm.HandleRequest(context.Background(), req, &resp, timeDuration); err != nil {
 		t.Fatal(err)
 	}
 
@@ -298,8 +303,8 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, resp chan<- common.Outcome) error {
 			time.Sleep(time.Millisecond * 5)
 			return nil
 		})
@@ -317,7 +322,11 @@ func TestGameliftManagerHandleRequest_SigV4QueryParametersPassed(t *testing.T) {
 		EXPECT().
 		NotifyRequestTimeout()
 
-	err = gm.HandleRequest(req, &resp, timeDuration)
+	gameliftMessageHandlerMock.
+		EXPECT().
+		OnRequestTimeout(req.RequestID)
+
+	err = gm.HandleRequest(context.Background(), req, &resp, timeDuration)
 	if err == nil {
 		t.Fatal(err)
 	}
@@ -417,8 +426,8 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, resp chan<- common.Outcome) error {
 			resp <- common.Outcome{Data: []byte(rawResponse)}
 			return nil
 		})
@@ -439,7 +448,7 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 		},
 	}
 
-	if err := gm.HandleRequest(req, &resp, timeDuration); err != nil {
+	if err := gm.HandleRequest(context.Background(), req, &resp, timeDuration); err != nil {
 		t.Fatal(err)
 	}
 
@@ -449,8 +458,8 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, resp chan<- common.Outcome) error {
 			time.Sleep(time.Millisecond * 5)
 			return nil
 		})
@@ -468,7 +477,11 @@ func TestGameliftManagerHandleRequest_AuthTokenAndSigV4QueryParametersPassed(t *
 		EXPECT().
 		NotifyRequestTimeout()
 
-	err = gm.HandleRequest(req, &resp, timeDuration)
+	gameliftMessageHandlerMock.
+		EXPECT().
+		OnRequestTimeout(req.RequestID)
+
+	err = gm.HandleRequest(context.Background(), req, &resp, timeDuration)
 	if err == nil {
 		t.Fatal(err)
 	}
@@ -536,14 +549,14 @@ func TestGameliftManagerHandleRequestError(t *testing.T) {
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		DoAndReturn(func(_ internal.MessageGetter, result chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ internal.MessageGetter, result chan<- common.Outcome) error {
 			result <- common.Outcome{Error: expectedError}
 
 			return nil
 		})
 
-	err := gm.HandleRequest(req, nil, time.Second)
+	err := gm.HandleRequest(context.Background(), req, nil, time.Second)
 	if !errors.Is(err, expectedError) {
 		t.Fatalf("unexpected error %s, want %s", err, expectedError)
 	}
@@ -580,8 +593,8 @@ func TestGameliftManagerHandleRequest_Timeout_ReturnError(t *testing.T) {
 
 	websocketClientMock.
 		EXPECT().
-		SendRequest(req, gomock.Any()).
-		Do(func(req internal.MessageGetter, resp chan<- common.Outcome) error {
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, resp chan<- common.Outcome) error {
 			time.Sleep(MockDelayInResponse)
 			return nil
 		})
@@ -594,16 +607,94 @@ func TestGameliftManagerHandleRequest_Timeout_ReturnError(t *testing.T) {
 		EXPECT().
 		NotifyRequestTimeout()
 
+	gameliftMessageHandlerMock.
+		EXPECT().
+		OnRequestTimeout(req.RequestID)
+
 	logger.
 		EXPECT().
 		Errorf("Response not received within time limit for request: %s", "test-request-id").
 		Do(func(format string, args ...any) { t.Logf(format, args...) })
 
 	// WHEN
-	err := gm.HandleRequest(req, nil, DesiredRequestTimeout)
+	err := gm.HandleRequest(context.Background(), req, nil, DesiredRequestTimeout)
 
 	// THEN
 	if err.Error() != expectedError.Error() {
 		t.Fatalf("unexpected error %s, want %s", err, expectedError)
 	}
 }
+
+// GIVEN delayed response from sever WHEN the context of HandleRequest is cancelled THEN cancel the request and return ctx error
+func TestGameliftManagerHandleRequest_ContextCancelled_ReturnError(t *testing.T) {
+	// Set up the test case
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
+	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	httpClientMock := mock.NewMockHttpClient(ctrl)
+
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, httpClientMock)
+
+	req := &request.DescribePlayerSessionsRequest{
+		Message: message.Message{
+			Action:    message.DescribePlayerSessions,
+			RequestID: "test-request-id",
+		},
+		PlayerID: "test-player-id",
+		Limit:    1,
+	}
+
+	// GIVEN
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	websocketClientMock.
+		EXPECT().
+		SendRequest(gomock.Any(), req, gomock.Any()).
+		Return(nil)
+
+	websocketClientMock.
+		EXPECT().
+		CancelRequest(req.RequestID)
+
+	// WHEN
+	err := gm.HandleRequest(ctx, req, nil, time.Minute)
+
+	// THEN
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error %s, want %s", err, context.DeadlineExceeded)
+	}
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.ServiceCallFailed {
+		t.Fatalf("unexpected error %s, want ServiceCallFailed GameLiftError", err)
+	}
+}
+
+// GIVEN cancelled context WHEN HandleRequest is called THEN return ctx error without sending the request
+func TestGameliftManagerHandleRequest_ContextAlreadyCancelled_ReturnError(t *testing.T) {
+	// Set up the test case
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	gameliftMessageHandlerMock := mock.NewMockIGameLiftMessageHandler(ctrl)
+	websocketClientMock := mock.NewMockIWebSocketClient(ctrl)
+	logger := mock.NewTestLogger(t, ctrl)
+	httpClientMock := mock.NewMockHttpClient(ctrl)
+
+	gm := internal.GetGameLiftManager(gameliftMessageHandlerMock, websocketClientMock, logger, httpClientMock)
+
+	// GIVEN
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN
+	err := gm.HandleRequest(ctx, request.NewHeartbeatServerProcess(true), nil, time.Minute)
+
+	// THEN
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error %s, want %s", err, context.Canceled)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+)
+
+// idempotentActions - requests that can be sent again without side effects when their connection is lost.
+var idempotentActions = map[message.MessageAction]bool{
+	message.DescribePlayerSessions:  true,
+	message.HeartbeatServerProcess:  true,
+	message.GetComputeCertificate:   true,
+	message.GetFleetRoleCredentials: true,
+}
+
+// inFlightRequest - a request written to a connection and not answered yet.
+type inFlightRequest struct {
+	write        queuedWrite
+	connectionID int32
+	// seq - the order the request was written in, to send requests again in the same order.
+	seq uint64
+}
+
+// handleEvent - tracks the websocket connections of the transport and passes the event to the event handler, if set.
+func (c *websocketClient) handleEvent(event model.Event) {
+	switch event.Type {
+	case model.EventWebsocketConnected:
+		atomic.StoreInt32(&c.connectionID, int32(event.ConnectionID))
+		c.stopReconnecting(nil)
+	case model.EventWebsocketReconnecting:
+		// A disconnection is not followed by a reconnect if the peer closed the connection normally,
+		// so the requests are held back from the reconnect only.
+		c.startReconnecting()
+	case model.EventWebsocketReconnectFailed:
+		c.stopReconnecting(event.Err)
+	case model.EventWebsocketConnectionRetired:
+		c.recoverInFlight(int32(event.ConnectionID))
+	}
+	c.emitEvent(event)
+}
+
+// emitEvent - passes the event to the event handler, if set.
+func (c *websocketClient) emitEvent(event model.Event) {
+	if c.onEvent != nil {
+		c.onEvent(event)
+	}
+}
+
+// trackInFlight - records that the request is about to be written to the current connection.
+// Requests already answered or cancelled are not tracked.
+func (c *websocketClient) trackInFlight(w queuedWrite) {
+	c.respMtx.Lock()
+	defer c.respMtx.Unlock()
+	if _, ok := c.responses[w.requestID]; !ok {
+		return
+	}
+	c.inFlightSeq++
+	c.inFlight[w.requestID] = inFlightRequest{
+		write:        w,
+		connectionID: atomic.LoadInt32(&c.connectionID),
+		seq:          c.inFlightSeq,
+	}
+}
+
+// untrackInFlight - forgets a request that could not be written.
+// Returns false if the request was answered or cancelled meanwhile.
+func (c *websocketClient) untrackInFlight(requestID string) bool {
+	c.respMtx.Lock()
+	defer c.respMtx.Unlock()
+	delete(c.inFlight, requestID)
+	_, ok := c.responses[requestID]
+	return ok
+}
+
+// recoverInFlight - handles the requests left unanswered on a retired connection: idempotent requests are
+// sent again on the current connection, the others fail with a common.WebsocketRequestInterrupted error
+// instead of waiting for their timeout.
+func (c *websocketClient) recoverInFlight(connectionID int32) {
+	var lost []inFlightRequest
+	c.respMtx.Lock()
+	for requestID, req := range c.inFlight {
+		if req.connectionID <= connectionID {
+			lost = append(lost, req)
+			delete(c.inFlight, requestID)
+		}
+	}
+	c.respMtx.Unlock()
+	if len(lost) == 0 {
+		return
+	}
+	sort.Slice(lost, func(i, j int) bool { return lost[i].seq < lost[j].seq })
+	c.log.Debugf("websocket %d: %d requests were not answered before the connection was retired", connectionID, len(lost))
+	for _, req := range lost {
+		action := req.write.action
+		if !idempotentActions[action] {
+			c.sendResponse(req.write.requestID, nil, common.NewGameLiftError(common.WebsocketRequestInterrupted, "",
+				"The connection was lost before a response to "+string(action)+" was received."))
+			continue
+		}
+		c.log.Debugf("Sending %s request %s again after the connection was retired", action, req.write.requestID)
+		if err := c.enqueueWrite(req.write); err != nil {
+			c.sendResponse(req.write.requestID, nil, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
+)
+
+func setupInFlightClient(t *testing.T) (*internal.WebsocketClient, <-chan []byte) {
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	transportMock := mock.NewMockITransport(ctrl)
+	c := new(internal.WebsocketClient)
+	transportMock.EXPECT().SetReadHandler(gomock.Not(gomock.Nil()))
+	c.Init(transportMock, logger)
+
+	written := make(chan []byte, 4)
+	transportMock.
+		EXPECT().
+		WriteContext(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, data []byte) { written <- data }).
+		AnyTimes()
+	transportMock.EXPECT().PreventAutoReconnect()
+	transportMock.EXPECT().Close()
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketConnected, ConnectionID: 1})
+	return c, written
+}
+
+func waitForWrite(t *testing.T, written <-chan []byte) []byte {
+	select {
+	case data := <-written:
+		return data
+	case <-time.After(time.Second):
+		t.Fatal("Expected a write")
+		return nil
+	}
+}
+
+// GIVEN an idempotent request without response WHEN its connection is retired THEN it is sent again
+func TestWebsocketClient_RetiredConnection_ResendsIdempotentRequest(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	c, written := setupInFlightClient(t)
+	req := newQueuedRequest("test-request-id")
+	respCh := make(chan common.Outcome, 1)
+	if err := c.SendRequest(context.Background(), req, respCh); err != nil {
+		t.Fatal(err)
+	}
+	first := waitForWrite(t, written)
+
+	// WHEN
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketConnected, ConnectionID: 2})
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketConnectionRetired, ConnectionID: 1})
+
+	// THEN
+	if second := waitForWrite(t, written); !bytes.Equal(first, second) {
+		t.Fatalf("Expected %s to be sent again but got %s", first, second)
+	}
+	const rawResponse = `{"Action":"DescribePlayerSessions","RequestId":"test-request-id","StatusCode":200}`
+	c.RunReadHandler([]byte(rawResponse))
+	common.AssertEqual(t, rawResponse, string((<-respCh).Data))
+
+	// WHEN - answered requests are not sent again
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketConnectionRetired, ConnectionID: 2})
+
+	// THEN
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 0, len(written))
+}
+
+// GIVEN a non-idempotent request without response WHEN its connection is retired THEN it fails without waiting
+func TestWebsocketClient_RetiredConnection_FailsNonIdempotentRequest(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	c, written := setupInFlightClient(t)
+	req := request.NewAcceptPlayerSession("test-game-session-id", "test-player-session-id")
+	respCh := make(chan common.Outcome, 1)
+	if err := c.SendRequest(context.Background(), req, respCh); err != nil {
+		t.Fatal(err)
+	}
+	waitForWrite(t, written)
+
+	// WHEN
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketConnectionRetired, ConnectionID: 1})
+
+	// THEN
+	assertOutcomeError(t, respCh, common.WebsocketRequestInterrupted)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 0, len(written))
+}
@@ -6,6 +6,7 @@
 package internal
 
 import (
+	"context"
 	"io"
 	"net/url"
 
@@ -20,7 +21,8 @@ import (
 type IWebSocketClient interface {
 	io.Closer
 	Connect(url *url.URL) error
-	SendRequest(req MessageGetter, resp chan<- common.Outcome) error
+	// SendRequest - queues the request; ctx bounds the time it may wait in the queue and the write itself.
+	SendRequest(ctx context.Context, req MessageGetter, resp chan<- common.Outcome) error
 	AddHandler(action message.MessageAction, handler func([]byte))
 	CancelRequest(requestID string)
 	// NotifyRequestTimeout - notifies the client that a request timed out waiting for a response.
@@ -21,4 +21,6 @@ type IGameLiftMessageHandler interface {
 	)
 	OnTerminateProcess(terminationTime int64)
 	OnRefreshConnection(refreshConnectionEndpoint, authToken string)
+	// OnRequestTimeout - called when no response to the request was received within its timeout.
+	OnRequestTimeout(requestID string)
 }
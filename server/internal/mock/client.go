@@ -5,6 +5,7 @@
 package mock
 
 import (
+	context "context"
 	common "github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
 	message "github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
 	internal "github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
@@ -90,17 +91,17 @@ func (mr *MockIWebSocketClientMockRecorder) Connect(arg0 interface{}) *gomock.Ca
 }
 
 // SendRequest mocks base method.
-func (m *MockIWebSocketClient) SendRequest(arg0 internal.MessageGetter, arg1 chan<- common.Outcome) error {
+func (m *MockIWebSocketClient) SendRequest(arg0 context.Context, arg1 internal.MessageGetter, arg2 chan<- common.Outcome) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendRequest", arg0, arg1)
+	ret := m.ctrl.Call(m, "SendRequest", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SendRequest indicates an expected call of SendRequest.
-func (mr *MockIWebSocketClientMockRecorder) SendRequest(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockIWebSocketClientMockRecorder) SendRequest(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequest", reflect.TypeOf((*MockIWebSocketClient)(nil).SendRequest), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequest", reflect.TypeOf((*MockIWebSocketClient)(nil).SendRequest), arg0, arg1, arg2)
 }
 
 // NotifyRequestTimeout mocks base method.
@@ -46,6 +46,18 @@ func (mr *MockIGameLiftMessageHandlerMockRecorder) OnRefreshConnection(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnRefreshConnection", reflect.TypeOf((*MockIGameLiftMessageHandler)(nil).OnRefreshConnection), arg0, arg1)
 }
 
+// OnRequestTimeout mocks base method.
+func (m *MockIGameLiftMessageHandler) OnRequestTimeout(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnRequestTimeout", arg0)
+}
+
+// OnRequestTimeout indicates an expected call of OnRequestTimeout.
+func (mr *MockIGameLiftMessageHandlerMockRecorder) OnRequestTimeout(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnRequestTimeout", reflect.TypeOf((*MockIGameLiftMessageHandler)(nil).OnRequestTimeout), arg0)
+}
+
 // OnStartGameSession mocks base method.
 func (m *MockIGameLiftMessageHandler) OnStartGameSession(arg0 *model.GameSession) {
 	m.ctrl.T.Helper()
@@ -34,6 +34,21 @@ func (m *MockHttpClient) EXPECT() *MockHttpClientMockRecorder {
 	return m.recorder
 }
 
+// Do mocks base method.
+func (m *MockHttpClient) Do(arg0 *http.Request) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", arg0)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockHttpClientMockRecorder) Do(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockHttpClient)(nil).Do), arg0)
+}
+
 // Get mocks base method.
 func (m *MockHttpClient) Get(arg0 string) (*http.Response, error) {
 	m.ctrl.T.Helper()
@@ -5,6 +5,7 @@
 package mock
 
 import (
+	context "context"
 	internal "github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
 	result "github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
 	security "github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/security"
@@ -97,18 +98,18 @@ func (mr *MockIGameLiftManagerMockRecorder) FetchMetadata(arg0 interface{}) *gom
 }
 
 // FetchContainersNetworkInfo mocks base method.
-func (m *MockIGameLiftManager) FetchContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error) {
+func (m *MockIGameLiftManager) FetchContainersNetworkInfo(arg0 context.Context) (result.ListContainersNetworkInfoResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchContainersNetworkInfo")
+	ret := m.ctrl.Call(m, "FetchContainersNetworkInfo", arg0)
 	ret0, _ := ret[0].(result.ListContainersNetworkInfoResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchContainersNetworkInfo indicates an expected call of FetchContainersNetworkInfo.
-func (mr *MockIGameLiftManagerMockRecorder) FetchContainersNetworkInfo() *gomock.Call {
+func (mr *MockIGameLiftManagerMockRecorder) FetchContainersNetworkInfo(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchContainersNetworkInfo", reflect.TypeOf((*MockIGameLiftManager)(nil).FetchContainersNetworkInfo))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchContainersNetworkInfo", reflect.TypeOf((*MockIGameLiftManager)(nil).FetchContainersNetworkInfo), arg0)
 }
 
 // GetLogger mocks base method.
@@ -126,15 +127,15 @@ func (mr *MockIGameLiftManagerMockRecorder) GetLogger() *gomock.Call {
 }
 
 // HandleRequest mocks base method.
-func (m *MockIGameLiftManager) HandleRequest(arg0 internal.MessageGetter, arg1 interface{}, arg2 time.Duration) error {
+func (m *MockIGameLiftManager) HandleRequest(arg0 context.Context, arg1 internal.MessageGetter, arg2 interface{}, arg3 time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HandleRequest", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "HandleRequest", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // HandleRequest indicates an expected call of HandleRequest.
-func (mr *MockIGameLiftManagerMockRecorder) HandleRequest(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockIGameLiftManagerMockRecorder) HandleRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRequest", reflect.TypeOf((*MockIGameLiftManager)(nil).HandleRequest), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRequest", reflect.TypeOf((*MockIGameLiftManager)(nil).HandleRequest), arg0, arg1, arg2, arg3)
 }
@@ -5,6 +5,7 @@
 package mock
 
 import (
+	context "context"
 	transport "github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
 	url "net/url"
 	reflect "reflect"
@@ -89,6 +90,18 @@ func (mr *MockITransportMockRecorder) Reconnect() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconnect", reflect.TypeOf((*MockITransport)(nil).Reconnect))
 }
 
+// SetEventHandler mocks base method.
+func (m *MockITransport) SetEventHandler(arg0 transport.EventHandler) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetEventHandler", arg0)
+}
+
+// SetEventHandler indicates an expected call of SetEventHandler.
+func (mr *MockITransportMockRecorder) SetEventHandler(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEventHandler", reflect.TypeOf((*MockITransport)(nil).SetEventHandler), arg0)
+}
+
 // SetReadHandler mocks base method.
 func (m *MockITransport) SetReadHandler(arg0 transport.ReadHandler) {
 	m.ctrl.T.Helper()
@@ -114,3 +127,17 @@ func (mr *MockITransportMockRecorder) Write(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockITransport)(nil).Write), arg0)
 }
+
+// WriteContext mocks base method.
+func (m *MockITransport) WriteContext(arg0 context.Context, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteContext", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteContext indicates an expected call of WriteContext.
+func (mr *MockITransportMockRecorder) WriteContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteContext", reflect.TypeOf((*MockITransport)(nil).WriteContext), arg0, arg1)
+}
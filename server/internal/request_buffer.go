@@ -0,0 +1,133 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal
+
+import (
+	"context"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// bufferedWrite - a request held back while the websocket connection is re-established.
+type bufferedWrite struct {
+	write queuedWrite
+	// stopDeadline - stops failing the request when its ctx is done, see context.AfterFunc.
+	stopDeadline func() bool
+}
+
+// startReconnecting - holds back the requests written from now on until the connection is re-established.
+func (c *websocketClient) startReconnecting() {
+	c.bufferMtx.Lock()
+	defer c.bufferMtx.Unlock()
+	if !c.reconnecting {
+		c.log.Debugf("Holding back requests until the websocket connection is re-established")
+		c.reconnecting = true
+	}
+}
+
+// stopReconnecting - writes the requests held back, in order, once the connection is re-established.
+// If the connection could not be re-established, the requests held back fail with err instead.
+func (c *websocketClient) stopReconnecting(err error) {
+	c.bufferMtx.Lock()
+	c.reconnecting = false
+	c.bufferMtx.Unlock()
+	if err != nil {
+		c.dropBuffered(common.WrapGameLiftError(common.WebsocketSendMessageFailure, err))
+		return
+	}
+	select {
+	case c.flush <- struct{}{}:
+	default:
+	}
+}
+
+// buffer - holds back the request if the connection is being re-established.
+// Returns false if the request should be written now.
+func (c *websocketClient) buffer(w queuedWrite) bool {
+	c.bufferMtx.Lock()
+	if !c.reconnecting {
+		c.bufferMtx.Unlock()
+		return false
+	}
+	if len(c.buffered) >= c.bufferSize {
+		depth := len(c.buffered)
+		c.bufferMtx.Unlock()
+		c.log.Warnf("Request %s dropped: %d requests are already waiting for the websocket connection", w.requestID, depth)
+		c.dropWrite(w, depth, common.NewGameLiftError(common.WebsocketSendMessageFailure, "", "request buffer is full"))
+		return true
+	}
+	b := &bufferedWrite{write: w}
+	b.stopDeadline = context.AfterFunc(w.ctx, func() { c.expireBuffered(b) })
+	c.buffered = append(c.buffered, b)
+	depth := len(c.buffered)
+	c.bufferMtx.Unlock()
+	c.log.Debugf("Request %s held back until the websocket connection is re-established", w.requestID)
+	c.emitEvent(model.Event{Type: model.EventRequestBuffered, RequestID: w.requestID, BufferedRequests: depth})
+	return true
+}
+
+// flushBuffered - writes the requests held back in the order they were sent, unless the connection is
+// being re-established again.
+func (c *websocketClient) flushBuffered() {
+	flushed := 0
+	for {
+		c.bufferMtx.Lock()
+		if c.reconnecting || len(c.buffered) == 0 {
+			c.bufferMtx.Unlock()
+			break
+		}
+		b := c.buffered[0]
+		c.buffered = c.buffered[1:]
+		c.bufferMtx.Unlock()
+		// A request whose deadline passed meanwhile fails in write.
+		b.stopDeadline()
+		c.write(b.write)
+		flushed++
+	}
+	if flushed > 0 {
+		c.log.Debugf("Wrote %d requests held back while the websocket connection was re-established", flushed)
+		c.emitEvent(model.Event{Type: model.EventRequestBufferFlushed})
+	}
+}
+
+// expireBuffered - fails a request held back past its deadline.
+func (c *websocketClient) expireBuffered(b *bufferedWrite) {
+	c.bufferMtx.Lock()
+	found := false
+	for i := range c.buffered {
+		if c.buffered[i] == b {
+			c.buffered = append(c.buffered[:i], c.buffered[i+1:]...)
+			found = true
+			break
+		}
+	}
+	depth := len(c.buffered)
+	c.bufferMtx.Unlock()
+	if !found {
+		return
+	}
+	c.log.Debugf("Request %s expired while the websocket connection was re-established", b.write.requestID)
+	c.dropWrite(b.write, depth, common.WrapGameLiftError(common.ServiceCallFailed, b.write.ctx.Err()))
+}
+
+// dropBuffered - fails all the requests held back with err.
+func (c *websocketClient) dropBuffered(err error) {
+	c.bufferMtx.Lock()
+	buffered := c.buffered
+	c.buffered = nil
+	c.bufferMtx.Unlock()
+	for i, b := range buffered {
+		b.stopDeadline()
+		c.dropWrite(b.write, len(buffered)-i-1, err)
+	}
+}
+
+// dropWrite - fails a request that was never written and reports it.
+func (c *websocketClient) dropWrite(w queuedWrite, depth int, err error) {
+	c.sendResponse(w.requestID, nil, err)
+	c.emitEvent(model.Event{Type: model.EventRequestDropped, RequestID: w.requestID, BufferedRequests: depth, Err: err})
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/goleak"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// GIVEN a lost connection WHEN requests are sent THEN they are held back and written in order once it is re-established
+func TestWebsocketClient_Reconnecting_FlushesBufferedRequestsInOrder(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	c, written := setupInFlightClient(t)
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketDisconnected, ConnectionID: 1})
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketReconnecting, ConnectionID: 1})
+
+	// WHEN
+	respChs := make([]chan common.Outcome, 3)
+	for i, id := range []string{"request-1", "request-2", "request-3"} {
+		respChs[i] = make(chan common.Outcome, 1)
+		if err := c.SendRequest(context.Background(), newQueuedRequest(id), respChs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// THEN
+	common.AssertEqual(t, 0, len(written))
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketConnected, ConnectionID: 2})
+	for _, id := range []string{"request-1", "request-2", "request-3"} {
+		common.AssertEqual(t, id, writtenRequestID(t, waitForWrite(t, written)))
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// GIVEN requests held back WHEN the buffer is full, a deadline passes or the reconnect fails THEN they fail without being written
+func TestWebsocketClient_Reconnecting_DropsBufferedRequests(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	t.Setenv(common.RequestBufferSize, "2")
+	c, written := setupInFlightClient(t)
+	c.RunEventHandler(model.Event{Type: model.EventWebsocketReconnecting, ConnectionID: 1})
+	expiring, cancel := context.WithCancel(context.Background())
+	respChs := make([]chan common.Outcome, 3)
+	for i, id := range []string{"request-1", "request-2", "request-3"} {
+		ctx := context.Background()
+		if id == "request-1" {
+			ctx = expiring
+		}
+		respChs[i] = make(chan common.Outcome, 1)
+		if err := c.SendRequest(ctx, newQueuedRequest(id), respChs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// WHEN
+	assertOutcomeError(t, respChs[2], common.WebsocketSendMessageFailure)
+	cancel()
+	assertOutcomeError(t, respChs[0], common.ServiceCallFailed)
+	c.RunEventHandler(model.Event{
+		Type:         model.EventWebsocketReconnectFailed,
+		ConnectionID: 1,
+		Err:          errors.New("dial failed"),
+	})
+
+	// THEN
+	assertOutcomeError(t, respChs[1], common.WebsocketSendMessageFailure)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 0, len(written))
+}
+
+// GIVEN a connection closed by the peer with a normal close frame WHEN a request is sent THEN it is not held back
+func TestWebsocketClient_NormalClosure_DoesNotHoldBackRequests(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	c, written := setupInFlightClient(t)
+	c.RunEventHandler(model.Event{
+		Type:         model.EventWebsocketDisconnected,
+		ConnectionID: 1,
+		Err:          &websocket.CloseError{Code: websocket.CloseNormalClosure, Text: "bye"},
+	})
+
+	// WHEN
+	respCh := make(chan common.Outcome, 1)
+	if err := c.SendRequest(context.Background(), newQueuedRequest("test-request-id"), respCh); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	common.AssertEqual(t, "test-request-id", writtenRequestID(t, waitForWrite(t, written)))
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -6,6 +6,7 @@
 package security
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -39,6 +40,14 @@ func NewContainerNetworkInfoFetcher(httpClient transport.HttpClient) (*Container
 
 // FetchContainersNetworkInfo fetches network info for all containers on the instance.
 func (f *ContainerNetworkInfoFetcher) FetchContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error) {
+	return f.FetchContainersNetworkInfoContext(context.Background())
+}
+
+// FetchContainersNetworkInfoContext fetches network info for all containers on the instance.
+// The requests to the container metadata endpoint and the discovery server are abandoned when ctx is done.
+func (f *ContainerNetworkInfoFetcher) FetchContainersNetworkInfoContext(
+	ctx context.Context,
+) (result.ListContainersNetworkInfoResult, error) {
 	var res result.ListContainersNetworkInfoResult
 
 	computeType := os.Getenv(common.EnvironmentKeyComputeType)
@@ -50,7 +59,7 @@ func (f *ContainerNetworkInfoFetcher) FetchContainersNetworkInfo() (result.ListC
 		)
 	}
 
-	response, err := f.fetchDiscoveryServerResponse()
+	response, err := f.fetchDiscoveryServerResponse(ctx)
 	if err != nil {
 		return res, err
 	}
@@ -89,12 +98,12 @@ func (f *ContainerNetworkInfoFetcher) FetchContainersNetworkInfo() (result.ListC
 //  1. Use GAMELIFT_CONTAINER_DISCOVERY_SERVER_ENDPOINT env var
 //  2. Fallback: derive endpoint from ECS container metadata
 //  3. If env var endpoint fails, retry with metadata-derived endpoint
-func (f *ContainerNetworkInfoFetcher) fetchDiscoveryServerResponse() (*http.Response, error) {
+func (f *ContainerNetworkInfoFetcher) fetchDiscoveryServerResponse(ctx context.Context) (*http.Response, error) {
 	envEndpoint := os.Getenv(common.EnvironmentKeyDiscoveryEndpoint)
 	endpoint := envEndpoint
 
 	if endpoint == "" {
-		endpoint = f.resolveDiscoveryEndpointFromMetadata()
+		endpoint = f.resolveDiscoveryEndpointFromMetadata(ctx)
 		if endpoint == "" {
 			return nil, common.NewGameLiftError(
 				common.InternalServiceException,
@@ -104,13 +113,13 @@ func (f *ContainerNetworkInfoFetcher) fetchDiscoveryServerResponse() (*http.Resp
 		}
 	}
 
-	response, err := f.httpClient.Get(endpoint + discoveryServerPath)
+	response, err := f.get(ctx, endpoint+discoveryServerPath)
 	if err != nil {
 		// If we used the env var, try fallback from metadata
-		if envEndpoint != "" {
-			fallback := f.resolveDiscoveryEndpointFromMetadata()
+		if envEndpoint != "" && ctx.Err() == nil {
+			fallback := f.resolveDiscoveryEndpointFromMetadata(ctx)
 			if fallback != "" && fallback != endpoint {
-				fallbackResp, fallbackErr := f.httpClient.Get(fallback + discoveryServerPath)
+				fallbackResp, fallbackErr := f.get(ctx, fallback+discoveryServerPath)
 				if fallbackErr == nil {
 					return fallbackResp, nil
 				}
@@ -129,13 +138,13 @@ func (f *ContainerNetworkInfoFetcher) fetchDiscoveryServerResponse() (*http.Resp
 // resolveDiscoveryEndpointFromMetadata queries ECS container metadata to derive the bridge gateway IP.
 // ECS metadata returns: {"Networks":[{"NetworkMode":"bridge","IPv4Addresses":["172.17.0.5"]}]}
 // The bridge gateway is always .1 on the container's subnet (e.g., 172.17.0.5 → 172.17.0.1)
-func (f *ContainerNetworkInfoFetcher) resolveDiscoveryEndpointFromMetadata() string {
+func (f *ContainerNetworkInfoFetcher) resolveDiscoveryEndpointFromMetadata(ctx context.Context) string {
 	metadataURI := os.Getenv(EnvironmentVariableContainerMetadataURI)
 	if metadataURI == "" {
 		return ""
 	}
 
-	response, err := f.httpClient.Get(metadataURI)
+	response, err := f.get(ctx, metadataURI)
 	if err != nil {
 		return ""
 	}
@@ -166,3 +175,12 @@ func (f *ContainerNetworkInfoFetcher) resolveDiscoveryEndpointFromMetadata() str
 	gatewayIP := containerIP[:lastDot] + ".1"
 	return fmt.Sprintf("http://%s:%d", gatewayIP, discoveryServerPort)
 }
+
+// get sends a GET request bound by ctx.
+func (f *ContainerNetworkInfoFetcher) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f.httpClient.Do(req)
+}
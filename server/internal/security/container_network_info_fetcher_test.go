@@ -6,6 +6,7 @@
 package security_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -26,6 +27,24 @@ func clearContainerNetworkInfoEnv() {
 	os.Unsetenv(security.EnvironmentVariableContainerMetadataURI)
 }
 
+// getRequestMatcher matches a GET request to url.
+type getRequestMatcher struct {
+	url string
+}
+
+func getRequest(url string) gomock.Matcher {
+	return getRequestMatcher{url: url}
+}
+
+func (m getRequestMatcher) Matches(x interface{}) bool {
+	req, ok := x.(*http.Request)
+	return ok && req.Method == http.MethodGet && req.URL.String() == m.url
+}
+
+func (m getRequestMatcher) String() string {
+	return "is a GET request to " + m.url
+}
+
 func TestContainerNetworkInfoFetcher_NewContainerNetworkInfoFetcher_NilHttpClient(t *testing.T) {
 	var httpClient transport.HttpClient
 	_, err := security.NewContainerNetworkInfoFetcher(httpClient)
@@ -97,7 +116,7 @@ func TestContainerNetworkInfoFetcher_ContainerTypeWithUnreachableEndpoint_Return
 	os.Setenv(common.EnvironmentKeyDiscoveryEndpoint, "http://192.0.2.1:9999")
 
 	mockHttpClient := mock.NewMockHttpClient(ctrl)
-	mockHttpClient.EXPECT().Get("http://192.0.2.1:9999/v1/").Return(nil, errors.New("connection refused"))
+	mockHttpClient.EXPECT().Do(getRequest("http://192.0.2.1:9999/v1/")).Return(nil, errors.New("connection refused"))
 	// Fallback metadata resolution - no metadata URI set, so no additional calls
 
 	fetcher, _ := security.NewContainerNetworkInfoFetcher(mockHttpClient)
@@ -122,7 +141,7 @@ func TestContainerNetworkInfoFetcher_EndpointFromEnvVar_ValidResponse(t *testing
 		{"containerName":"game-server","ipAddress":"172.17.0.3","containerId":"def456ghi789","containerGroupType":"GAME_SERVER"}
 	]`
 	mockHttpClient := mock.NewMockHttpClient(ctrl)
-	mockHttpClient.EXPECT().Get("http://172.17.0.1:4092/v1/").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://172.17.0.1:4092/v1/")).Return(&http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader(body)),
 	}, nil)
@@ -159,14 +178,14 @@ func TestContainerNetworkInfoFetcher_FallbackFromMetadata_ValidResponse(t *testi
 	// First call: ECS metadata to resolve endpoint
 	metadataBody := `{"Networks":[{"NetworkMode":"bridge","IPv4Addresses":["172.17.0.5"]}]}`
 	mockHttpClient := mock.NewMockHttpClient(ctrl)
-	mockHttpClient.EXPECT().Get("http://169.254.170.2/v4/metadata").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://169.254.170.2/v4/metadata")).Return(&http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader(metadataBody)),
 	}, nil)
 
 	// Second call: discovery server at derived gateway
 	discoveryBody := `[{"containerName":"game-server","ipAddress":"172.17.0.5","containerId":"abc123","containerGroupType":"GAME_SERVER"}]`
-	mockHttpClient.EXPECT().Get("http://172.17.0.1:4092/v1/").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://172.17.0.1:4092/v1/")).Return(&http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader(discoveryBody)),
 	}, nil)
@@ -197,18 +216,18 @@ func TestContainerNetworkInfoFetcher_EnvVarFails_FallbackSucceeds(t *testing.T)
 	mockHttpClient := mock.NewMockHttpClient(ctrl)
 
 	// Primary endpoint fails
-	mockHttpClient.EXPECT().Get("http://10.0.0.1:4092/v1/").Return(nil, errors.New("connection refused"))
+	mockHttpClient.EXPECT().Do(getRequest("http://10.0.0.1:4092/v1/")).Return(nil, errors.New("connection refused"))
 
 	// Metadata resolution for fallback - different subnet so fallback != endpoint
 	metadataBody := `{"Networks":[{"NetworkMode":"bridge","IPv4Addresses":["172.17.0.5"]}]}`
-	mockHttpClient.EXPECT().Get("http://169.254.170.2/v4/metadata").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://169.254.170.2/v4/metadata")).Return(&http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader(metadataBody)),
 	}, nil)
 
 	// Fallback endpoint succeeds
 	discoveryBody := `[{"containerName":"game-server","ipAddress":"172.17.0.5","containerId":"xyz789","containerGroupType":"GAME_SERVER"}]`
-	mockHttpClient.EXPECT().Get("http://172.17.0.1:4092/v1/").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://172.17.0.1:4092/v1/")).Return(&http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader(discoveryBody)),
 	}, nil)
@@ -236,7 +255,7 @@ func TestContainerNetworkInfoFetcher_EmptyList(t *testing.T) {
 	os.Setenv(common.EnvironmentKeyDiscoveryEndpoint, "http://172.17.0.1:4092")
 
 	mockHttpClient := mock.NewMockHttpClient(ctrl)
-	mockHttpClient.EXPECT().Get("http://172.17.0.1:4092/v1/").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://172.17.0.1:4092/v1/")).Return(&http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader("[]")),
 	}, nil)
@@ -261,7 +280,7 @@ func TestContainerNetworkInfoFetcher_InvalidJsonResponse(t *testing.T) {
 	os.Setenv(common.EnvironmentKeyDiscoveryEndpoint, "http://172.17.0.1:4092")
 
 	mockHttpClient := mock.NewMockHttpClient(ctrl)
-	mockHttpClient.EXPECT().Get("http://172.17.0.1:4092/v1/").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://172.17.0.1:4092/v1/")).Return(&http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(strings.NewReader("not json")),
 	}, nil)
@@ -283,7 +302,7 @@ func TestContainerNetworkInfoFetcher_HttpNon200Response(t *testing.T) {
 	os.Setenv(common.EnvironmentKeyDiscoveryEndpoint, "http://172.17.0.1:4092")
 
 	mockHttpClient := mock.NewMockHttpClient(ctrl)
-	mockHttpClient.EXPECT().Get("http://172.17.0.1:4092/v1/").Return(&http.Response{
+	mockHttpClient.EXPECT().Do(getRequest("http://172.17.0.1:4092/v1/")).Return(&http.Response{
 		StatusCode: http.StatusInternalServerError,
 		Body:       io.NopCloser(strings.NewReader("")),
 	}, nil)
@@ -295,3 +314,32 @@ func TestContainerNetworkInfoFetcher_HttpNon200Response(t *testing.T) {
 		t.Fatalf("expected HTTP 500 error, got %v", err)
 	}
 }
+
+func TestContainerNetworkInfoFetcher_CancelledContext_AbandonsRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer clearContainerNetworkInfoEnv()
+
+	os.Setenv(common.EnvironmentKeyComputeType, common.ComputeTypeContainer)
+	os.Setenv(common.EnvironmentKeyDiscoveryEndpoint, "http://10.0.0.1:4092")
+	os.Setenv(security.EnvironmentVariableContainerMetadataURI, "http://169.254.170.2/v4/metadata")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mockHttpClient := mock.NewMockHttpClient(ctrl)
+	// The metadata fallback is not tried once ctx is done.
+	mockHttpClient.EXPECT().Do(getRequest("http://10.0.0.1:4092/v1/")).DoAndReturn(
+		func(req *http.Request) (*http.Response, error) {
+			return nil, req.Context().Err()
+		})
+
+	fetcher, _ := security.NewContainerNetworkInfoFetcher(mockHttpClient)
+	_, err := fetcher.FetchContainersNetworkInfoContext(ctx)
+
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+}
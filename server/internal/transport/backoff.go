@@ -0,0 +1,121 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+)
+
+// Jitter - how the delay between two connection attempts is randomized.
+type Jitter int
+
+const (
+	// JitterFull - waits a random delay between 0 and the exponential delay.
+	JitterFull Jitter = iota
+	// JitterDecorrelated - waits a random delay between the base delay and three times the previous delay.
+	JitterDecorrelated
+	// JitterNone - waits the exponential delay.
+	JitterNone
+)
+
+// UnlimitedAttempts - BackoffPolicy.MaxAttempts value to try until the connection is established.
+const UnlimitedAttempts = -1
+
+// BackoffPolicy - the delays between the attempts to establish a websocket connection.
+// Zero values are replaced by the defaults, see DefaultBackoffPolicy.
+type BackoffPolicy struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      Jitter
+	MaxAttempts int
+}
+
+// DefaultBackoffPolicy - returns the policy used when none is configured.
+// It waits the exponential delays without jitter, as the transport did before the policy was configurable.
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{
+		BaseDelay:   common.ReconnectBaseDelayDefault,
+		MaxDelay:    common.ReconnectMaxDelayDefault,
+		Jitter:      JitterNone,
+		MaxAttempts: common.ReconnectMaxAttemptsDefault,
+	}
+}
+
+// withDefaults - returns the policy with its zero values replaced by the defaults.
+func (p BackoffPolicy) withDefaults() BackoffPolicy {
+	defaults := DefaultBackoffPolicy()
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	return p
+}
+
+// backoff - the delays between the attempts of a single Connect call.
+type backoff struct {
+	policy BackoffPolicy
+	rand   *rand.Rand
+	// attempts - the number of failed attempts so far.
+	attempts int
+	previous time.Duration
+}
+
+func newBackoff(policy BackoffPolicy) *backoff {
+	return &backoff{
+		policy: policy.withDefaults(),
+		//nolint:gosec // The jitter only spreads reconnects, cryptographic randomness is not required
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// next - records a failed attempt and returns the delay before the next one.
+// Returns false if no attempts are left.
+func (b *backoff) next() (time.Duration, bool) {
+	b.attempts++
+	if b.policy.MaxAttempts != UnlimitedAttempts && b.attempts >= b.policy.MaxAttempts {
+		return 0, false
+	}
+	var delay time.Duration
+	switch b.policy.Jitter {
+	case JitterDecorrelated:
+		upper := max(b.previous*3, b.policy.BaseDelay)
+		delay = min(b.policy.BaseDelay+b.random(upper-b.policy.BaseDelay), b.policy.MaxDelay)
+	case JitterNone:
+		delay = b.exponential()
+	default:
+		delay = b.random(b.exponential())
+	}
+	b.previous = delay
+	return delay, true
+}
+
+// exponential - BaseDelay doubled for each failed attempt after the first one, up to MaxDelay.
+func (b *backoff) exponential() time.Duration {
+	delay := b.policy.BaseDelay
+	for i := 1; i < b.attempts && delay < b.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, b.policy.MaxDelay)
+}
+
+// random - returns a random delay between 0 and upper, inclusive.
+func (b *backoff) random(upper time.Duration) time.Duration {
+	if upper <= 0 {
+		return 0
+	}
+	return time.Duration(b.rand.Int63n(int64(upper) + 1))
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
+)
+
+// GIVEN the default policy without jitter WHEN connecting fails THEN the delays double up to the max delay
+func TestBackoffPolicy_NoJitter_DoublesUpToMaxDelay(t *testing.T) {
+	// GIVEN
+	policy := transport.BackoffPolicy{Jitter: transport.JitterNone}
+
+	// WHEN
+	delays := transport.BackoffDelays(policy, 100)
+
+	// THEN
+	expected := []time.Duration{8, 16, 32, 32, 32, 32, 32}
+	common.AssertEqual(t, common.ReconnectMaxAttemptsDefault-1, len(delays))
+	for i := range expected {
+		common.AssertEqual(t, expected[i]*time.Second, delays[i])
+	}
+}
+
+// GIVEN a jittered policy WHEN connecting fails THEN the delays stay within their bounds
+func TestBackoffPolicy_Jitter_StaysWithinBounds(t *testing.T) {
+	for _, jitter := range []transport.Jitter{transport.JitterFull, transport.JitterDecorrelated} {
+		// GIVEN
+		policy := transport.BackoffPolicy{
+			BaseDelay:   time.Second,
+			MaxDelay:    10 * time.Second,
+			Jitter:      jitter,
+			MaxAttempts: transport.UnlimitedAttempts,
+		}
+
+		// WHEN
+		delays := transport.BackoffDelays(policy, 1000)
+
+		// THEN
+		common.AssertEqual(t, 1000, len(delays))
+		minDelay := time.Duration(0)
+		if jitter == transport.JitterDecorrelated {
+			minDelay = policy.BaseDelay
+		}
+		for _, delay := range delays {
+			if delay < minDelay || delay > policy.MaxDelay {
+				t.Fatalf("Expected delays between %v and %v with jitter %d but got %v", minDelay, policy.MaxDelay, jitter, delay)
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport
+
+import "time"
+
+// BackoffDelays expose the delays between the connection attempts of the policy for testing purposes,
+// at most limit delays are returned.
+func BackoffDelays(policy BackoffPolicy, limit int) []time.Duration {
+	b := newBackoff(policy)
+	var delays []time.Duration
+	for len(delays) < limit {
+		delay, ok := b.next()
+		if !ok {
+			break
+		}
+		delays = append(delays, delay)
+	}
+	return delays
+}
@@ -10,13 +10,20 @@
 package transport
 
 import (
+	"context"
 	"net/http"
 	"net/url"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
 )
 
 // ReadHandler is a callback function that is called when incoming messages are received.
 type ReadHandler func([]byte)
 
+// EventHandler is a callback function that is called when the connection state changes.
+// It must not block, as it may be called while the connection is locked.
+type EventHandler func(model.Event)
+
 // ITransport is the interface that manages input/output operations on the underlying connection.
 type ITransport interface {
 	// Connect creates a websocket connection with the specified address.
@@ -26,9 +33,15 @@ type ITransport interface {
 	// Write sends message to underlying connection.
 	Write([]byte) error
 
+	// WriteContext sends message to underlying connection, giving up once ctx is done.
+	WriteContext(ctx context.Context, data []byte) error
+
 	// SetReadHandler sets a callback function that is called when incoming messages are received.
 	SetReadHandler(ReadHandler)
 
+	// SetEventHandler sets a callback function that is called when the connection state changes.
+	SetEventHandler(EventHandler)
+
 	// Close closes underlying connections and releases their associated resources.
 	// All Write calls after Close call will return an error.
 	Close() error
@@ -66,4 +79,5 @@ type Dialer interface {
 // HttpClient is the interface that creates a HttpClient.
 type HttpClient interface {
 	Get(url string) (*http.Response, error)
+	Do(req *http.Request) (*http.Response, error)
 }
@@ -0,0 +1,76 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+
+	"github.com/gorilla/websocket"
+)
+
+// keepaliveConn - a Conn that supports websocket ping/pong control frames, such as *websocket.Conn.
+// Connections that do not support them are not kept alive.
+type keepaliveConn interface {
+	Conn
+
+	// WriteControl writes a control message with the given deadline. It may be called concurrently with other writes.
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+
+	// SetPongHandler sets the handler for pong messages received from the peer.
+	SetPongHandler(h func(appData string) error)
+
+	// SetReadDeadline sets the read deadline on the underlying network connection.
+	SetReadDeadline(t time.Time) error
+}
+
+// startKeepalive - pings the connection every ping interval until ctx is done.
+// The keepalive is opt-in: it does nothing unless a ping interval is configured through WEBSOCKET_PING_INTERVAL.
+// Each pong extends the read deadline of the connection, so that when pongs stop the pending read fails
+// and readProcess reconnects, instead of waiting for requests to time out on a half-open connection.
+// Must be called before readProcess starts reading the connection.
+func (tr *websocketTransport) startKeepalive(ctx context.Context, conn Conn, connectionId int) {
+	kc, ok := conn.(keepaliveConn)
+	if !ok || tr.pingInterval <= 0 {
+		return
+	}
+	readTimeout := tr.pingInterval + tr.pongTimeout
+	if err := kc.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
+		tr.log.Debugf("websocket %d: Failed to set read deadline, keepalive disabled: %v", connectionId, err)
+		return
+	}
+	kc.SetPongHandler(func(appData string) error {
+		// The ping payload is the time it was sent.
+		if sent, err := strconv.ParseInt(appData, 10, 64); err == nil {
+			tr.emit(model.Event{
+				Type:          model.EventWebsocketPongReceived,
+				ConnectionID:  connectionId,
+				RoundTripTime: time.Since(time.Unix(0, sent)),
+			})
+		}
+		return kc.SetReadDeadline(time.Now().Add(readTimeout))
+	})
+	go func() {
+		ticker := time.NewTicker(tr.pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			now := time.Now()
+			payload := []byte(strconv.FormatInt(now.UnixNano(), 10))
+			if err := kc.WriteControl(websocket.PingMessage, payload, now.Add(tr.pongTimeout)); err != nil {
+				// A missing pong lets the read deadline expire, which reconnects.
+				tr.log.Debugf("websocket %d: Failed to send ping: %v", connectionId, err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"go.uber.org/goleak"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
+)
+
+// startKeepaliveServer - starts a websocket server that answers pings only if answerPings is true.
+func startKeepaliveServer(t *testing.T, answerPings bool) *url.URL {
+	// Runs last, after the transport and the server are closed.
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if !answerPings {
+			conn.SetPingHandler(func(string) error { return nil })
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse("ws" + server.URL[len("http"):])
+	if err != nil {
+		t.Fatalf("parse url: %s", err)
+	}
+	return u
+}
+
+func createKeepaliveWebsocket(t *testing.T, pingInterval string) (transport.ITransport, <-chan model.Event) {
+	t.Setenv(common.DisconnectWebsocketTimeout, TestDisconnectWebsocketTimeout)
+	if pingInterval != "" {
+		t.Setenv(common.WebsocketPingInterval, pingInterval)
+	}
+	t.Setenv(common.WebsocketPongTimeout, "50ms")
+	logger := mock.NewTestLogger(t, gomock.NewController(t),
+		mock.WithExpectAnyDebug(true), mock.WithExpectAnyWarn(true), mock.WithExpectAnyError(true))
+	tr := transport.Websocket(logger, transport.NewDialer(nil))
+	events := make(chan model.Event, 64)
+	tr.SetEventHandler(func(event model.Event) {
+		select {
+		case events <- event:
+		default:
+		}
+	})
+	t.Cleanup(func() {
+		tr.PreventAutoReconnect()
+		_ = tr.Close()
+	})
+	return tr, events
+}
+
+func waitForEvent(t *testing.T, events <-chan model.Event, eventType model.EventType) model.Event {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("Event %s was not emitted", eventType.String())
+		}
+	}
+}
+
+// GIVEN a connection answering pings WHEN idle THEN pongs are received with their round-trip time
+func TestWebsocketKeepalive_PongReportsRoundTripTime(t *testing.T) {
+	// GIVEN
+	u := startKeepaliveServer(t, true)
+	tr, events := createKeepaliveWebsocket(t, "10ms")
+
+	// WHEN
+	if err := tr.Connect(u); err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+
+	// THEN
+	event := waitForEvent(t, events, model.EventWebsocketPongReceived)
+	common.AssertEqual(t, 1, event.ConnectionID)
+	if event.RoundTripTime <= 0 {
+		t.Fatalf("Expected a positive round-trip time but got %s", event.RoundTripTime)
+	}
+}
+
+// GIVEN a half-open connection that never answers pings WHEN the pong timeout elapses THEN reconnect
+func TestWebsocketKeepalive_MissingPongReconnects(t *testing.T) {
+	// GIVEN
+	u := startKeepaliveServer(t, false)
+	tr, events := createKeepaliveWebsocket(t, "10ms")
+
+	// WHEN
+	if err := tr.Connect(u); err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+
+	// THEN
+	waitForEvent(t, events, model.EventWebsocketDisconnected)
+	waitForEvent(t, events, model.EventWebsocketReconnecting)
+	event := waitForEvent(t, events, model.EventWebsocketConnected)
+	common.AssertEqual(t, 2, event.ConnectionID)
+}
+
+// GIVEN no ping interval configured and a peer that never answers pings WHEN the pong timeout elapses
+// THEN the connection stays up, because the keepalive is opt-in
+func TestWebsocketKeepalive_DisabledByDefault(t *testing.T) {
+	// GIVEN
+	u := startKeepaliveServer(t, false)
+	tr, events := createKeepaliveWebsocket(t, "")
+
+	// WHEN
+	if err := tr.Connect(u); err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+
+	// THEN
+	timeout := time.After(500 * time.Millisecond)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == model.EventWebsocketDisconnected || event.Type == model.EventWebsocketPongReceived {
+				t.Fatalf("Expected no keepalive activity but got %s", event.Type.String())
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+// FrameDirection describes whether a recorded Frame was received, sent or is a connection event.
+type FrameDirection string
+
+// Possible FrameDirection values
+const (
+	FrameInbound  FrameDirection = "IN"
+	FrameOutbound FrameDirection = "OUT"
+	FrameEvent    FrameDirection = "EVENT"
+)
+
+// redactedValue replaces the values of sensitiveFields in recorded frames.
+const redactedValue = "REDACTED"
+
+// sensitiveFields are top-level frame fields holding credentials, which are never written to a recording.
+var sensitiveFields = []string{"AuthToken", "AccessKeyId", "SecretAccessKey", "SessionToken"}
+
+// Frame is a single line of a traffic recording.
+type Frame struct {
+	Time time.Time `json:"Time"`
+	// ConnectionID is the sequence number of the websocket connection the frame was sent or received on.
+	ConnectionID int            `json:"ConnectionId"`
+	Direction    FrameDirection `json:"Direction"`
+	// Data is the frame as sent on the wire, for FrameInbound and FrameOutbound.
+	// A frame that is not valid JSON is stored as a JSON string.
+	Data json.RawMessage `json:"Data,omitempty"`
+	// Event is the model.EventType of a FrameEvent, for example WEBSOCKET_CONNECTED.
+	Event string `json:"Event,omitempty"`
+	// Error is the error of a failed write or the cause of a disconnection.
+	Error string `json:"Error,omitempty"`
+}
+
+// payload returns the frame as it was sent on the wire.
+func (f *Frame) payload() []byte {
+	var text string
+	if err := json.Unmarshal(f.Data, &text); err == nil {
+		return []byte(text)
+	}
+	return f.Data
+}
+
+type recordingTransport struct {
+	ITransport
+	log log.ILogger
+
+	mtx          sync.Mutex
+	w            io.Writer
+	encoder      *json.Encoder
+	connectionID int
+	closed       bool
+
+	eventHandlerMu sync.RWMutex
+	eventHandler   EventHandler
+}
+
+// WithRecorder wraps the specified transport by recording every inbound and outbound frame, together with
+// connection events, as JSON lines to w. Credentials are redacted from the recorded frames.
+// If w is an io.Closer, it is closed by Close. The recording can be read back with ReadFrames.
+func WithRecorder(next ITransport, w io.Writer, l log.ILogger) ITransport {
+	r := &recordingTransport{
+		ITransport: next,
+		log:        l,
+		w:          w,
+		encoder:    json.NewEncoder(w),
+	}
+	next.SetEventHandler(r.handleEvent)
+	return r
+}
+
+func (r *recordingTransport) Write(data []byte) error {
+	return r.WriteContext(context.Background(), data)
+}
+
+func (r *recordingTransport) WriteContext(ctx context.Context, data []byte) error {
+	err := r.ITransport.WriteContext(ctx, data)
+	r.record(Frame{Direction: FrameOutbound, Data: frameData(data), Error: errorString(err)})
+	return err
+}
+
+func (r *recordingTransport) SetReadHandler(handler ReadHandler) {
+	if handler == nil {
+		r.ITransport.SetReadHandler(nil)
+		return
+	}
+	r.ITransport.SetReadHandler(func(data []byte) {
+		r.record(Frame{Direction: FrameInbound, Data: frameData(data)})
+		handler(data)
+	})
+}
+
+func (r *recordingTransport) SetEventHandler(handler EventHandler) {
+	r.eventHandlerMu.Lock()
+	defer r.eventHandlerMu.Unlock()
+
+	r.eventHandler = handler
+}
+
+func (r *recordingTransport) Close() error {
+	err := r.ITransport.Close()
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.closed {
+		return err
+	}
+	r.closed = true
+	if closer, ok := r.w.(io.Closer); ok {
+		if closeErr := closer.Close(); closeErr != nil {
+			r.log.Warnf("Failed to close traffic recording: %s", closeErr)
+		}
+	}
+	return err
+}
+
+// handleEvent records the event and passes it to the event handler, if set.
+func (r *recordingTransport) handleEvent(event model.Event) {
+	r.mtx.Lock()
+	if event.Type == model.EventWebsocketConnected {
+		r.connectionID = event.ConnectionID
+	}
+	r.mtx.Unlock()
+	r.record(Frame{
+		ConnectionID: event.ConnectionID,
+		Direction:    FrameEvent,
+		Event:        event.Type.String(),
+		Error:        errorString(event.Err),
+	})
+
+	r.eventHandlerMu.RLock()
+	handler := r.eventHandler
+	r.eventHandlerMu.RUnlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// record writes the frame, stamped with the current time and, unless set, the current connection.
+func (r *recordingTransport) record(frame Frame) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.closed {
+		return
+	}
+	frame.Time = time.Now()
+	if frame.ConnectionID == 0 {
+		frame.ConnectionID = r.connectionID
+	}
+	if err := r.encoder.Encode(frame); err != nil {
+		r.log.Warnf("Failed to record %s frame: %s", frame.Direction, err)
+	}
+}
+
+// frameData returns the frame with credentials redacted, as JSON suitable for Frame.Data.
+func frameData(data []byte) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		text, _ := json.Marshal(string(data))
+		return text
+	}
+	redacted := false
+	for _, field := range sensitiveFields {
+		if _, ok := fields[field]; ok {
+			fields[field], _ = json.Marshal(redactedValue)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return append(json.RawMessage(nil), data...)
+	}
+	result, _ := json.Marshal(fields)
+	return result
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// ReadFrames reads a traffic recording written by WithRecorder.
+func ReadFrames(r io.Reader) ([]Frame, error) {
+	var frames []Frame
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("invalid traffic recording: %w", err)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, scanner.Err()
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
+)
+
+// closeRecorder - a buffer that records whether it was closed.
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRecorderTransport_RecordFrames(t *testing.T) {
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	transportMock := mock.NewMockITransport(ctrl)
+
+	var transportEvents transport.EventHandler
+	var transportRead transport.ReadHandler
+	transportMock.EXPECT().SetEventHandler(gomock.Any()).Do(func(h transport.EventHandler) { transportEvents = h })
+	transportMock.EXPECT().SetReadHandler(gomock.Any()).Do(func(h transport.ReadHandler) { transportRead = h })
+	transportMock.EXPECT().WriteContext(gomock.Any(), []byte(testMessage)).Return(testError)
+	transportMock.EXPECT().Close()
+
+	output := &closeRecorder{}
+	recorder := transport.WithRecorder(transportMock, output, logger)
+	var events []model.Event
+	recorder.SetEventHandler(func(event model.Event) { events = append(events, event) })
+	var received [][]byte
+	recorder.SetReadHandler(func(data []byte) { received = append(received, data) })
+
+	// WHEN
+	transportEvents(model.Event{Type: model.EventWebsocketConnected, ConnectionID: 2})
+	writeErr := recorder.Write([]byte(testMessage))
+	transportRead([]byte(`{"Action":"RefreshConnection","RequestId":"r-1","AuthToken":"secret-token"}`))
+	transportRead([]byte("not json"))
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	common.AssertEqual(t, testError, writeErr)
+	common.AssertEqual(t, 1, len(events))
+	common.AssertEqual(t, 2, len(received))
+	common.AssertEqual(t, true, output.closed)
+	if strings.Contains(output.String(), "secret-token") {
+		t.Fatalf("Expected the auth token to be redacted: %s", output.String())
+	}
+
+	frames, err := transport.ReadFrames(strings.NewReader(output.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 4, len(frames))
+	common.AssertEqual(t, transport.FrameEvent, frames[0].Direction)
+	common.AssertEqual(t, "WEBSOCKET_CONNECTED", frames[0].Event)
+	common.AssertEqual(t, transport.FrameOutbound, frames[1].Direction)
+	common.AssertEqual(t, 2, frames[1].ConnectionID)
+	common.AssertEqual(t, `{"key":"value"}`, string(frames[1].Data))
+	common.AssertEqual(t, testError.Error(), frames[1].Error)
+	common.AssertEqual(t, transport.FrameInbound, frames[2].Direction)
+	common.AssertContains(t, string(frames[2].Data), `"AuthToken":"REDACTED"`)
+	common.AssertEqual(t, `"not json"`, string(frames[3].Data))
+	for _, frame := range frames {
+		if frame.Time.IsZero() {
+			t.Fatalf("Expected %s frame to be timestamped", frame.Direction)
+		}
+	}
+}
+
+func TestReadFrames_ReturnError(t *testing.T) {
+	// WHEN
+	_, err := transport.ReadFrames(strings.NewReader("{\"Direction\":\"IN\"}\n{invalid\n"))
+
+	// THEN
+	if err == nil {
+		t.Fatal("Expected error for an invalid recording")
+	}
+}
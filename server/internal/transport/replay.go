@@ -0,0 +1,269 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+// ReplayTransport is an ITransport that plays a traffic recording back instead of connecting to a websocket.
+//
+// The first Connect call starts the replay: inbound frames are passed to the read handler and connection
+// events to the event handler, in recorded order. Outbound frames are not replayed; instead, each request
+// written by the client is matched with the next recorded request with the same Action, and the recorded
+// response is delivered with the request ID of the client. A recorded response waits until its request has
+// been written, so the replay follows the client regardless of the timing.
+type ReplayTransport struct {
+	log    log.ILogger
+	frames []Frame
+	speed  float64
+
+	// RequestTimeout is the time a recorded response waits for its request to be written before
+	// it is delivered with the recorded request ID. It must be set before Connect.
+	RequestTimeout time.Duration
+
+	mtx          sync.Mutex
+	readHandler  ReadHandler
+	eventHandler EventHandler
+	started      bool
+	// requestIDs maps the request IDs of recorded outbound frames to the request IDs written by the client.
+	requestIDs map[string]string
+	// pending holds the recorded outbound frames that were not matched with a written request yet.
+	pending []message.Message
+	written [][]byte
+	matched chan struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+}
+
+// Replay creates a ReplayTransport for the frames, read with ReadFrames. With speed 1 the frames are replayed
+// with their original timing, with speed 10 ten times faster, and with speed 0 without any delay.
+func Replay(frames []Frame, speed float64, l log.ILogger) *ReplayTransport {
+	tr := &ReplayTransport{
+		log:            l,
+		frames:         frames,
+		speed:          speed,
+		RequestTimeout: common.ServiceCallTimeoutDefault,
+		requestIDs:     make(map[string]string),
+		matched:        make(chan struct{}),
+		closed:         make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	for i := range frames {
+		if frames[i].Direction != FrameOutbound || frames[i].Error != "" {
+			continue
+		}
+		var msg message.Message
+		if err := json.Unmarshal(frames[i].payload(), &msg); err == nil && msg.RequestID != "" {
+			tr.pending = append(tr.pending, msg)
+			tr.requestIDs[msg.RequestID] = ""
+		}
+	}
+	return tr
+}
+
+// Connect starts the replay on the first call. Later calls, for example due to a replayed
+// RefreshConnection, do nothing.
+func (tr *ReplayTransport) Connect(*url.URL) error {
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	if !tr.started {
+		tr.started = true
+		go tr.replay()
+	}
+	return nil
+}
+
+// WriteContext - same as Write, the replay never blocks.
+func (tr *ReplayTransport) WriteContext(_ context.Context, data []byte) error {
+	return tr.Write(data)
+}
+
+// Write stores the frame and matches it with the next recorded request with the same Action.
+func (tr *ReplayTransport) Write(data []byte) error {
+	select {
+	case <-tr.closed:
+		return common.NewGameLiftError(common.GameLiftServerNotInitialized, "", "")
+	default:
+	}
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	tr.written = append(tr.written, append([]byte(nil), data...))
+
+	var msg message.Message
+	if err := json.Unmarshal(data, &msg); err != nil || msg.RequestID == "" {
+		return nil
+	}
+	for i, recorded := range tr.pending {
+		if recorded.Action == msg.Action {
+			tr.requestIDs[recorded.RequestID] = msg.RequestID
+			tr.pending = append(tr.pending[:i], tr.pending[i+1:]...)
+			close(tr.matched)
+			tr.matched = make(chan struct{})
+			return nil
+		}
+	}
+	tr.log.Debugf("Replay: no recorded %s request left to match request %s", msg.Action, msg.RequestID)
+	return nil
+}
+
+func (tr *ReplayTransport) SetReadHandler(handler ReadHandler) {
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+
+	tr.readHandler = handler
+}
+
+func (tr *ReplayTransport) SetEventHandler(handler EventHandler) {
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+
+	tr.eventHandler = handler
+}
+
+// Close stops the replay. All Write calls after Close call will return an error.
+func (tr *ReplayTransport) Close() error {
+	tr.closeOnce.Do(func() {
+		close(tr.closed)
+		tr.mtx.Lock()
+		defer tr.mtx.Unlock()
+		if !tr.started {
+			tr.started = true
+			close(tr.done)
+		}
+	})
+	return nil
+}
+
+// Reconnect does nothing, as reconnects are part of the recording.
+func (tr *ReplayTransport) Reconnect() error {
+	return nil
+}
+
+func (tr *ReplayTransport) PreventAutoReconnect() {}
+
+// Done returns a channel that is closed once all frames were replayed or the transport was closed.
+func (tr *ReplayTransport) Done() <-chan struct{} {
+	return tr.done
+}
+
+// Written returns the frames written by the client so far, in order.
+func (tr *ReplayTransport) Written() [][]byte {
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	return append([][]byte(nil), tr.written...)
+}
+
+func (tr *ReplayTransport) replay() {
+	defer close(tr.done)
+	var previous time.Time
+	for i := range tr.frames {
+		frame := &tr.frames[i]
+		if !previous.IsZero() && !tr.sleep(frame.Time.Sub(previous)) {
+			return
+		}
+		previous = frame.Time
+
+		switch frame.Direction {
+		case FrameInbound:
+			data, ok := tr.withLiveRequestID(frame.payload())
+			if !ok {
+				return
+			}
+			tr.mtx.Lock()
+			handler := tr.readHandler
+			tr.mtx.Unlock()
+			if handler != nil {
+				// As for a websocket connection, frames are handled concurrently, since handlers may block
+				// until a later frame is delivered.
+				go handler(data)
+			}
+		case FrameEvent:
+			var eventType model.EventType
+			eventType.ToEventType(frame.Event)
+			tr.mtx.Lock()
+			handler := tr.eventHandler
+			tr.mtx.Unlock()
+			if handler != nil {
+				handler(model.Event{Type: eventType, Time: time.Now(), ConnectionID: frame.ConnectionID})
+			}
+		}
+	}
+}
+
+// sleep waits for the recorded delay scaled by the replay speed and returns false if the transport was closed.
+func (tr *ReplayTransport) sleep(delay time.Duration) bool {
+	if tr.speed <= 0 || delay <= 0 {
+		select {
+		case <-tr.closed:
+			return false
+		default:
+			return true
+		}
+	}
+	timer := time.NewTimer(time.Duration(float64(delay) / tr.speed))
+	defer timer.Stop()
+	select {
+	case <-tr.closed:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// withLiveRequestID waits until the request of a recorded response was written and returns the response
+// with the request ID of the client. Frames that are not responses to recorded requests are returned as is.
+// It returns false if the transport was closed.
+func (tr *ReplayTransport) withLiveRequestID(data []byte) ([]byte, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data, true
+	}
+	var requestID string
+	if err := json.Unmarshal(fields["RequestId"], &requestID); err != nil || requestID == "" {
+		return data, true
+	}
+
+	timeout := time.NewTimer(tr.RequestTimeout)
+	defer timeout.Stop()
+	for {
+		tr.mtx.Lock()
+		liveID, recorded := tr.requestIDs[requestID]
+		matched := tr.matched
+		tr.mtx.Unlock()
+		if !recorded {
+			return data, true
+		}
+		if liveID != "" {
+			fields["RequestId"], _ = json.Marshal(liveID)
+			result, err := json.Marshal(fields)
+			if err != nil {
+				return data, true
+			}
+			return result, true
+		}
+		select {
+		case <-tr.closed:
+			return nil, false
+		case <-timeout.C:
+			tr.log.Warnf("Replay: request %s was not written within %s, replaying its response as recorded",
+				requestID, tr.RequestTimeout)
+			return data, true
+		case <-matched:
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package transport_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
+)
+
+// recordedSession - a RefreshConnection pushed while a DescribePlayerSessions request is in flight.
+func recordedSession(start time.Time) []transport.Frame {
+	return []transport.Frame{
+		{Time: start, ConnectionID: 1, Direction: transport.FrameEvent, Event: "WEBSOCKET_CONNECTED"},
+		{Time: start.Add(10 * time.Millisecond), ConnectionID: 1, Direction: transport.FrameOutbound,
+			Data: json.RawMessage(`{"Action":"DescribePlayerSessions","RequestId":"recorded-id"}`)},
+		{Time: start.Add(20 * time.Millisecond), ConnectionID: 1, Direction: transport.FrameInbound,
+			Data: json.RawMessage(`{"Action":"RefreshConnection","RequestId":"push-id","StatusCode":200}`)},
+		{Time: start.Add(30 * time.Millisecond), ConnectionID: 1, Direction: transport.FrameInbound,
+			Data: json.RawMessage(`{"Action":"DescribePlayerSessions","RequestId":"recorded-id","StatusCode":200}`)},
+		{Time: start.Add(40 * time.Millisecond), ConnectionID: 1, Direction: transport.FrameEvent,
+			Event: "WEBSOCKET_DISCONNECTED"},
+	}
+}
+
+func TestReplayTransport_ReplayWithLiveRequestID(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	replay := transport.Replay(recordedSession(time.Now()), 0, logger)
+	defer replay.Close()
+
+	received := make(chan []byte, 2)
+	replay.SetReadHandler(func(data []byte) { received <- data })
+	events := make(chan model.Event, 2)
+	replay.SetEventHandler(func(event model.Event) { events <- event })
+
+	// WHEN
+	if err := replay.Connect(nil); err != nil {
+		t.Fatal(err)
+	}
+	pushed := <-received
+	if err := replay.Write([]byte(`{"Action":"DescribePlayerSessions","RequestId":"live-id"}`)); err != nil {
+		t.Fatal(err)
+	}
+	response := <-received
+	<-replay.Done()
+
+	// THEN
+	var msg struct {
+		Action    string
+		RequestID string `json:"RequestId"`
+	}
+	if err := json.Unmarshal(pushed, &msg); err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, "push-id", msg.RequestID)
+	if err := json.Unmarshal(response, &msg); err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, "DescribePlayerSessions", msg.Action)
+	common.AssertEqual(t, "live-id", msg.RequestID)
+	common.AssertEqual(t, model.EventWebsocketConnected, (<-events).Type)
+	common.AssertEqual(t, model.EventWebsocketDisconnected, (<-events).Type)
+	common.AssertEqual(t, 1, len(replay.Written()))
+}
+
+func TestReplayTransport_AcceleratedTiming(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	start := time.Now()
+	frames := []transport.Frame{
+		{Time: start, Direction: transport.FrameInbound, Data: json.RawMessage(`{"Action":"CreateGameSession"}`)},
+		{Time: start.Add(time.Second), Direction: transport.FrameInbound,
+			Data: json.RawMessage(`{"Action":"TerminateProcess"}`)},
+	}
+	replay := transport.Replay(frames, 20, logger)
+	defer replay.Close()
+	replay.SetReadHandler(func([]byte) {})
+
+	// WHEN
+	begin := time.Now()
+	if err := replay.Connect(nil); err != nil {
+		t.Fatal(err)
+	}
+	<-replay.Done()
+
+	// THEN
+	if elapsed := time.Since(begin); elapsed < 40*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Fatalf("Expected the replay to take about 50ms but took %s", elapsed)
+	}
+}
+
+func TestReplayTransport_Close(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl)
+	replay := transport.Replay(recordedSession(time.Now()), 0, logger)
+	replay.SetReadHandler(func([]byte) {})
+	if err := replay.Connect(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	// The recorded response waits for a request that is never written.
+	err := replay.Close()
+	<-replay.Done()
+
+	// THEN
+	common.AssertEqual(t, nil, err)
+	if err := replay.Write([]byte(testMessage)); err == nil {
+		t.Fatal("Expected error writing to a closed replay")
+	}
+}
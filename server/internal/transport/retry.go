@@ -6,6 +6,7 @@
 package transport
 
 import (
+	"context"
 	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
@@ -32,13 +33,20 @@ func WithRetry(next ITransport, l log.ILogger) ITransport {
 }
 
 func (r *retryTransport) Write(data []byte) error {
+	return r.WriteContext(context.Background(), data)
+}
+
+// WriteContext - retries the write until it succeeds, the attempts are exhausted or ctx is done.
+func (r *retryTransport) WriteContext(ctx context.Context, data []byte) error {
 	for i := 0; i < r.attempt; i++ {
-		err := r.ITransport.Write(data)
+		err := r.ITransport.WriteContext(ctx, data)
 		if err == nil {
 			return nil
 		}
 		r.log.Debugf("Call Failed: %s. Retrying attempt: %d of %d", err.Error(), i+1, r.attempt)
-		time.Sleep(time.Duration((i+1)*r.factor) * r.interval)
+		if err := sleep(ctx, time.Duration((i+1)*r.factor)*r.interval); err != nil {
+			return common.WrapGameLiftError(common.WebsocketRetriableSendMessageFailure, err)
+		}
 	}
 
 	return common.NewGameLiftError(
@@ -47,3 +55,15 @@ func (r *retryTransport) Write(data []byte) error {
 		"write attempt overflow",
 	)
 }
+
+// sleep - waits for d, or returns the error of ctx if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -6,9 +6,11 @@
 package transport_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"go.uber.org/goleak"
@@ -40,7 +42,7 @@ func TestRetryTransportWrite(t *testing.T) {
 
 	transportMock.
 		EXPECT().
-		Write([]byte(testMessage)).
+		WriteContext(gomock.Any(), []byte(testMessage)).
 		Return(testError)
 
 	logger.
@@ -49,7 +51,7 @@ func TestRetryTransportWrite(t *testing.T) {
 
 	transportMock.
 		EXPECT().
-		Write([]byte(testMessage)).
+		WriteContext(gomock.Any(), []byte(testMessage)).
 		Return(nil)
 
 	retryTransport := transport.WithRetry(transportMock, logger)
@@ -74,7 +76,7 @@ func TestRetryTransportWriteMaxAttempts(t *testing.T) {
 	for i := 0; i < common.MaxRetryDefault; i++ {
 		transportMock.
 			EXPECT().
-			Write([]byte(testMessage)).
+			WriteContext(gomock.Any(), []byte(testMessage)).
 			Return(testError)
 
 		logger.
@@ -91,3 +93,36 @@ func TestRetryTransportWriteMaxAttempts(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// GIVEN a failing write WHEN the deadline of the write passes THEN the retries stop without waiting for the backoff
+func TestRetryTransportWriteContextStopsAtDeadline(t *testing.T) {
+	t.Setenv(common.RetryInterval, "1s")
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+
+	logger := mock.NewMockILogger(ctrl)
+	transportMock := mock.NewMockITransport(ctrl)
+
+	transportMock.
+		EXPECT().
+		WriteContext(gomock.Any(), []byte(testMessage)).
+		Return(testError)
+
+	logger.
+		EXPECT().
+		Debugf("Call Failed: %s. Retrying attempt: %d of %d", testError.Error(), 1, common.MaxRetryDefault)
+
+	retryTransport := transport.WithRetry(transportMock, logger)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := retryTransport.WriteContext(ctx, []byte(testMessage))
+	if err == nil || common.GetErrorTypeFromMessage(err.Error()) != common.WebsocketRetriableSendMessageFailure {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Expected the write to stop at its deadline, took %s", elapsed)
+	}
+}
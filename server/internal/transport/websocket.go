@@ -7,14 +7,17 @@ package transport
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
 
 	"github.com/gorilla/websocket"
@@ -41,12 +44,28 @@ type websocketTransport struct {
 	readHandlerMu sync.RWMutex
 	readHandler   ReadHandler
 
+	eventHandlerMu sync.RWMutex
+	eventHandler   EventHandler
+
 	readRetries  int
 	writeRetries int
 
 	connectionId int
 
 	disconnectWebsocketTimeout time.Duration
+	pingInterval               time.Duration
+	pongTimeout                time.Duration
+	backoffPolicy              BackoffPolicy
+}
+
+// WebsocketOption - configures the websocket transport, see Websocket.
+type WebsocketOption func(*websocketTransport)
+
+// WithBackoffPolicy - sets the delays between the attempts to establish a connection.
+func WithBackoffPolicy(policy BackoffPolicy) WebsocketOption {
+	return func(tr *websocketTransport) {
+		tr.backoffPolicy = policy
+	}
 }
 
 // isAbnormalCloseError returns true if the error is not a CloseError or if it is a CloseError with an unexpected status code
@@ -56,8 +75,8 @@ func isAbnormalCloseError(err error) bool {
 }
 
 // Websocket creates a new instance of the ITransport implementation.
-func Websocket(logger log.ILogger, dialer Dialer) ITransport {
-	return &websocketTransport{
+func Websocket(logger log.ILogger, dialer Dialer, opts ...WebsocketOption) ITransport {
+	tr := &websocketTransport{
 		log:    logger,
 		dialer: dialer,
 		disconnectWebsocketTimeout: common.GetEnvDurationOrDefault(
@@ -65,7 +84,14 @@ func Websocket(logger log.ILogger, dialer Dialer) ITransport {
 			common.DisconnectWebsocketTimeoutDefault,
 			logger,
 		),
+		pingInterval:  common.GetEnvDurationOrDefault(common.WebsocketPingInterval, common.WebsocketPingIntervalDefault, logger),
+		pongTimeout:   common.GetEnvDurationOrDefault(common.WebsocketPongTimeout, common.WebsocketPongTimeoutDefault, logger),
+		backoffPolicy: DefaultBackoffPolicy(),
+	}
+	for _, opt := range opts {
+		opt(tr)
 	}
+	return tr
 }
 
 func (tr *websocketTransport) handleNetworkInterrupt(e error) error {
@@ -93,13 +119,28 @@ func (tr *websocketTransport) Connect(u *url.URL) error {
 	tr.log.Debugf("Establishing websocket connection")
 
 	ctx := context.Background()
-	// Exponential doubles the interval between retries
-	backOff := retry.NewExponential(common.ConnectRetryInterval)
-	// We are adding two because we skip the first two retries until the initial duration is 4
-	backOff = retry.WithMaxRetries(common.ConnectMaxRetries+2, backOff)
-	backOff = retry.WithCappedDuration(common.MaxReconnectBackoffDuration, backOff)
-	backOff.Next()
-	backOff.Next()
+	delays := newBackoff(tr.backoffPolicy)
+	var lastErr error
+	backOff := retry.BackoffFunc(func() (time.Duration, bool) {
+		delay, ok := delays.next()
+		switch {
+		case tr.preventAutoReconnect.Load():
+			tr.log.Debugf("Not retrying to connect due to explicit previous call to PreventAutoReconnect()")
+			return 0, true
+		case !ok:
+			tr.log.Errorf("Giving up connecting after %d attempts: %s", delays.attempts, lastErr)
+			return 0, true
+		}
+		tr.log.Warnf("Connection attempt %d failed, retrying in %v: %s", delays.attempts, delay, lastErr)
+		tr.emit(model.Event{
+			Type:         model.EventWebsocketConnectRetry,
+			ConnectionID: oldConnectionId,
+			Attempt:      delays.attempts,
+			RetryDelay:   delay,
+			Err:          lastErr,
+		})
+		return delay, false
+	})
 
 	var connectionLifetimeContext context.Context
 	if err := retry.Do(ctx, backOff, func(ctx context.Context) error {
@@ -113,12 +154,11 @@ func (tr *websocketTransport) Connect(u *url.URL) error {
 				tr.log.Debugf("Response header is: %v", resp.Header)
 				tr.log.Debugf("Response body is: %s", b)
 			}
-			return retry.RetryableError(
-				common.NewGameLiftError(common.WebsocketConnectFailure,
-					"",
-					fmt.Sprintf("connection error %s:%s. %s", reason, dialErr.Error(), websocketConnectFailureMessage),
-				),
+			lastErr = common.NewGameLiftError(common.WebsocketConnectFailure,
+				"",
+				fmt.Sprintf("connection error %s:%s. %s", reason, dialErr.Error(), websocketConnectFailureMessage),
 			)
+			return retry.RetryableError(lastErr)
 		}
 		tr.conn = conn
 		connectionLifetimeContext, tr.cancelConnectionFn = context.WithCancel(context.Background())
@@ -138,7 +178,13 @@ func (tr *websocketTransport) Connect(u *url.URL) error {
 	tr.reconnecting.Store(false)
 
 	tr.connectionId++
-	go tr.readProcess(tr.conn, connectionLifetimeContext, tr.connectionId)
+	keepaliveContext, stopKeepalive := context.WithCancel(connectionLifetimeContext)
+	tr.startKeepalive(keepaliveContext, tr.conn, tr.connectionId)
+	go func(conn Conn, connectionId int) {
+		defer stopKeepalive()
+		tr.readProcess(conn, connectionLifetimeContext, connectionId)
+	}(tr.conn, tr.connectionId)
+	tr.emit(model.Event{Type: model.EventWebsocketConnected, ConnectionID: tr.connectionId})
 
 	// Close the previous connection
 	if err := tr.closeConnectionSafely(oldConn, oldConnectionId); err != nil {
@@ -154,8 +200,12 @@ func (tr *websocketTransport) Reconnect() error {
 		defer tr.writeMtx.Unlock()
 		return nil
 	}
+	tr.emit(model.Event{Type: model.EventWebsocketReconnecting, ConnectionID: tr.connectionId})
 	err := tr.Connect(&tr.connectURL)
 	tr.reconnecting.Store(false)
+	if err != nil {
+		tr.emit(model.Event{Type: model.EventWebsocketReconnectFailed, ConnectionID: tr.connectionId, Err: err})
+	}
 	return err
 }
 
@@ -193,6 +243,11 @@ func (tr *websocketTransport) readProcess(connection Conn, context context.Conte
 				tr.log.Debugf("read goroutine %d: connection marked redundant, error handling can be ignored", connectionId)
 			default:
 				if isAbnormalCloseError(err) {
+					if errors.Is(err, os.ErrDeadlineExceeded) {
+						tr.log.Warnf("read goroutine %d: No pong received within %v, the connection is half-open",
+							connectionId, tr.pingInterval+tr.pongTimeout)
+					}
+					tr.emit(model.Event{Type: model.EventWebsocketDisconnected, ConnectionID: connectionId, Err: err})
 					if !tr.reconnecting.Load() {
 						if !tr.preventAutoReconnect.Load() {
 							tr.log.Errorf("read goroutine %d: Websocket readProcess failed: %v", connectionId, err)
@@ -220,6 +275,7 @@ func (tr *websocketTransport) readProcess(connection Conn, context context.Conte
 		}
 	}
 	tr.log.Debugf("read goroutine %d: ending", connectionId)
+	tr.emit(model.Event{Type: model.EventWebsocketConnectionRetired, ConnectionID: connectionId})
 }
 
 func (tr *websocketTransport) SetReadHandler(handler ReadHandler) {
@@ -236,6 +292,23 @@ func (tr *websocketTransport) getReadHandler() ReadHandler {
 	return tr.readHandler
 }
 
+func (tr *websocketTransport) SetEventHandler(handler EventHandler) {
+	tr.eventHandlerMu.Lock()
+	defer tr.eventHandlerMu.Unlock()
+
+	tr.eventHandler = handler
+}
+
+// emit - passes the event to the event handler, if set.
+func (tr *websocketTransport) emit(event model.Event) {
+	tr.eventHandlerMu.RLock()
+	handler := tr.eventHandler
+	tr.eventHandlerMu.RUnlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
 // markConnectionAsRedundant - cancel further error handling on the corresponding connection.
 // Only short-circuit on errors for best-effort at flushing incoming messages until traffic
 // has been directed elsewhere. This is to avoid a race condition where a new connection
@@ -271,7 +344,9 @@ func (tr *websocketTransport) closeConnection(conn Conn, connectionId int) error
 func (tr *websocketTransport) Close() error {
 	// Set isConnected to false and close connection only if previously isConnected value was true.
 	if tr.isConnected.CompareAndSwap(true, false) {
-		return tr.closeConnection(tr.conn, tr.connectionId)
+		err := tr.closeConnection(tr.conn, tr.connectionId)
+		tr.emit(model.Event{Type: model.EventWebsocketDisconnected, ConnectionID: tr.connectionId, Err: err})
+		return err
 	}
 
 	return nil
@@ -282,6 +357,17 @@ func (tr *websocketTransport) PreventAutoReconnect() {
 }
 
 func (tr *websocketTransport) Write(data []byte) error {
+	return tr.WriteContext(context.Background(), data)
+}
+
+// writeDeadlineConn - optional interface of a Conn that can bound the time a write may block.
+type writeDeadlineConn interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// WriteContext - writes the message, retrying on failures until ctx is done.
+// The deadline of ctx, if any, also bounds a write blocked on the connection.
+func (tr *websocketTransport) WriteContext(ctx context.Context, data []byte) error {
 	tr.writeMtx.Lock()
 	if !tr.isConnected.Load() {
 		tr.writeMtx.Unlock()
@@ -290,7 +376,11 @@ func (tr *websocketTransport) Write(data []byte) error {
 	tr.writeRetries = 0
 	var err error
 	for ; tr.writeRetries < common.MaxReadWriteRetry; tr.writeRetries++ {
-		if err = tr.conn.WriteMessage(websocket.TextMessage, data); err != nil && isAbnormalCloseError(err) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			tr.writeMtx.Unlock()
+			return common.WrapGameLiftError(common.WebsocketSendMessageFailure, ctxErr)
+		}
+		if err = tr.writeMessage(ctx, data); err != nil && isAbnormalCloseError(err) {
 			if tr.writeRetries == common.ReconnectOnReadWriteFailureNumber {
 				tr.writeMtx.Unlock()
 				if err = tr.handleNetworkInterrupt(err); err == nil {
@@ -299,7 +389,10 @@ func (tr *websocketTransport) Write(data []byte) error {
 				tr.writeMtx.Lock()
 			} else {
 				tr.log.Debugf("Failed to write message: %v, retrying...", err)
-				time.Sleep(time.Second)
+				if sleepErr := sleep(ctx, time.Second); sleepErr != nil {
+					tr.writeMtx.Unlock()
+					return common.WrapGameLiftError(common.WebsocketSendMessageFailure, sleepErr)
+				}
 			}
 		} else {
 			tr.writeMtx.Unlock()
@@ -309,3 +402,15 @@ func (tr *websocketTransport) Write(data []byte) error {
 	tr.writeMtx.Unlock()
 	return common.NewGameLiftError(common.WebsocketSendMessageFailure, "Failed write data", err.Error())
 }
+
+// writeMessage - writes the message to the current connection, within the deadline of ctx if any.
+func (tr *websocketTransport) writeMessage(ctx context.Context, data []byte) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		if conn, ok := tr.conn.(writeDeadlineConn); ok {
+			if err := conn.SetWriteDeadline(deadline); err == nil {
+				defer conn.SetWriteDeadline(time.Time{}) //nolint:errcheck // Best effort, the next write sets it again
+			}
+		}
+	}
+	return tr.conn.WriteMessage(websocket.TextMessage, data)
+}
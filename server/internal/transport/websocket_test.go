@@ -21,6 +21,7 @@ import (
 	"go.uber.org/goleak"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
 )
@@ -57,13 +58,16 @@ func expectCloseTimes(times int, logger *mock.MockILogger, conn *mock.MockConn)
 		Times(times * 2)
 }
 
-func createMockWebsocket(t *testing.T) (transport.ITransport, *mock.MockDialer, *mock.MockConn, *mock.MockILogger) {
+func createMockWebsocket(
+	t *testing.T,
+	opts ...transport.WebsocketOption,
+) (transport.ITransport, *mock.MockDialer, *mock.MockConn, *mock.MockILogger) {
 	setWebsocketEnvironmentVariables(t)
 	ctrl := gomock.NewController(t)
 	dialer := mock.NewMockDialer(ctrl)
 	conn := mock.NewMockConn(ctrl)
 	logger := mock.NewMockILogger(ctrl)
-	tr := transport.Websocket(logger, dialer)
+	tr := transport.Websocket(logger, dialer, opts...)
 	logger.EXPECT().Debugf("read goroutine %d: starting", gomock.Any()).AnyTimes()
 	logger.EXPECT().Debugf("read goroutine %d: connection marked redundant, error handling can be ignored", gomock.Any()).AnyTimes()
 	logger.EXPECT().Debugf("read goroutine %d: ending", gomock.Any()).AnyTimes()
@@ -237,7 +241,7 @@ func TestWebsocketTransportWrite(t *testing.T) {
 	}
 }
 
-func TestWebsocketRetryConnection(t *testing.T) {
+func TestWebsocketTransportEvents(t *testing.T) {
 	// GIVEN
 	defer goleak.VerifyNone(t)
 	addr, err := url.Parse(rawAddr)
@@ -245,6 +249,58 @@ func TestWebsocketRetryConnection(t *testing.T) {
 		t.Fatalf("parse url: %s", err)
 	}
 	tr, dialer, conn, logger := createMockWebsocket(t)
+	conn.
+		EXPECT().
+		ReadMessage().
+		Return(-1, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}).
+		AnyTimes()
+	var mtx sync.Mutex
+	var events []model.Event
+	tr.SetEventHandler(func(event model.Event) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		events = append(events, event)
+	})
+
+	// EXPECT
+	expectConnectTimes(1, logger, dialer, conn)
+	expectCloseTimes(1, logger, conn)
+
+	// WHEN
+	err = tr.Connect(addr)
+	if err != nil {
+		t.Fatalf("websocket connect: %v", err)
+	}
+	err = tr.Close()
+	if err != nil {
+		t.Fatalf("websocket close connection: %v", err)
+	}
+
+	// THEN
+	mtx.Lock()
+	defer mtx.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events but got %v", events)
+	}
+	if events[0].Type != model.EventWebsocketConnected || events[1].Type != model.EventWebsocketDisconnected {
+		t.Fatalf("unexpected events: %v", events)
+	}
+	if events[0].ConnectionID != 1 || events[1].ConnectionID != 1 {
+		t.Fatalf("unexpected connection IDs: %v", events)
+	}
+}
+
+func TestWebsocketRetryConnection(t *testing.T) {
+	// GIVEN
+	defer goleak.VerifyNone(t)
+	addr, err := url.Parse(rawAddr)
+	if err != nil {
+		t.Fatalf("parse url: %s", err)
+	}
+	tr, dialer, conn, logger := createMockWebsocket(t, transport.WithBackoffPolicy(transport.BackoffPolicy{
+		BaseDelay: 10 * time.Millisecond,
+		Jitter:    transport.JitterNone,
+	}))
 
 	// Mock failed connection attempt
 	errorResponse := new(http.Response)
@@ -290,6 +346,10 @@ func TestWebsocketRetryConnection(t *testing.T) {
 		EXPECT().
 		Debugf("Response body is: %s", gomock.Any())
 
+	logger.
+		EXPECT().
+		Warnf("Connection attempt %d failed, retrying in %v: %s", 1, 10*time.Millisecond, gomock.Any())
+
 	// WHEN
 	err = tr.Connect(addr)
 	if err != nil {
@@ -6,6 +6,7 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -18,12 +19,7 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
 )
 
-var (
-	initWebsocketOnce sync.Once
-	gameliftWebsocket websocketClient
-)
-
-// websocketClient - Singleton, implements IWebSocketClient interface.
+// websocketClient - implements IWebSocketClient interface.
 // Stores all handlers for requests and messages
 type websocketClient struct {
 	iTransport    transport.ITransport
@@ -41,31 +37,65 @@ type websocketClient struct {
 	consecutiveTimeouts int32
 	// reconnectInFlight ensures only one reconnect is kicked off per streak of timeouts.
 	reconnectInFlight common.AtomicBool
+
+	// writeQueue - requests waiting for the writer goroutine, in the order they were sent.
+	// The writer goroutine is started by the first request and stopped by Close.
+	writeQueue chan queuedWrite
+	writeMtx   sync.Mutex
+	writerStop chan struct{}
+	writerDone chan struct{}
+	closed     bool
+
+	// connectionID - the current connection of the transport, see model.EventWebsocketConnected.
+	// Accessed atomically via sync/atomic primitives.
+	connectionID int32
+	// inFlight - requests written and not answered yet, by request ID. Guarded by respMtx.
+	inFlight    map[string]inFlightRequest
+	inFlightSeq uint64
+	onEvent     transport.EventHandler
+
+	// buffered - requests held back while the connection is re-established, in the order they were sent.
+	// Written by the writer goroutine once the connection is up again, see flush.
+	bufferMtx    sync.Mutex
+	reconnecting bool
+	buffered     []*bufferedWrite
+	bufferSize   int
+	flush        chan struct{}
 }
 
-// GetWebsocketClient - return an implementation of IWebSocketClient.
-func GetWebsocketClient(
+// NewWebsocketClient - return a new implementation of IWebSocketClient bound to the specified transport.
+// Each server SDK client owns its own websocket client, so no state is shared between instances.
+// The events of the transport are passed to onEvent, if not nil.
+func NewWebsocketClient(
 	iTransport transport.ITransport,
 	l log.ILogger,
+	onEvent transport.EventHandler,
 ) IWebSocketClient {
-	initWebsocketOnce.Do(func() {
-		gameliftWebsocket.init(iTransport, l)
-	})
-
-	return &gameliftWebsocket
+	c := new(websocketClient)
+	c.init(iTransport, l)
+	c.onEvent = onEvent
+	c.iTransport.SetEventHandler(c.handleEvent)
+	return c
 }
 
 func (c *websocketClient) init(iTransport transport.ITransport, l log.ILogger) {
 	c.iTransport = iTransport
 	c.log = l
 	c.responses = make(map[string]chan<- common.Outcome)
+	c.inFlight = make(map[string]inFlightRequest)
 	c.asyncHandlers = make(map[message.MessageAction]func([]byte))
-	c.iTransport.SetReadHandler(gameliftWebsocket.readHandler)
+	c.writeQueue = make(chan queuedWrite, common.GetEnvIntOrDefault(common.WriteQueueSize, common.WriteQueueSizeDefault, l))
+	c.bufferSize = common.GetEnvIntOrDefault(common.RequestBufferSize, common.RequestBufferSizeDefault, l)
+	c.flush = make(chan struct{}, 1)
+	c.iTransport.SetReadHandler(c.readHandler)
 }
 
 // Connect creates a websocket connection with the specified address.
 // All Send calls before Connect call will return an error.
 func (c *websocketClient) Connect(connectURL *url.URL) error {
+	c.writeMtx.Lock()
+	c.closed = false
+	c.writeMtx.Unlock()
 	if err := c.iTransport.Connect(connectURL); err != nil {
 		return err
 	}
@@ -74,8 +104,11 @@ func (c *websocketClient) Connect(connectURL *url.URL) error {
 	return nil
 }
 
-// SendRequest - sends message to the game server process via websocket, answer will be sent to the resp channel.
-func (c *websocketClient) SendRequest(req MessageGetter, resp chan<- common.Outcome) error {
+// SendRequest - queues message to the game server process via websocket, answer will be sent to the resp channel.
+// Messages are written in order by a single writer goroutine. While the connection is re-established, messages are
+// held back and written once it is up again. A message still waiting when ctx is done fails without being written,
+// and ctx bounds the write itself; write failures are sent to the resp channel as well.
+func (c *websocketClient) SendRequest(ctx context.Context, req MessageGetter, resp chan<- common.Outcome) error {
 	if resp == nil {
 		return common.NewGameLiftError(common.BadRequestException, "", "invalid input parameters")
 	}
@@ -85,10 +118,14 @@ func (c *websocketClient) SendRequest(req MessageGetter, resp chan<- common.Outc
 		return common.NewGameLiftError(common.BadRequestException, "", "empty RequestID")
 	}
 
+	data, err := json.Marshal(req)
+	if err != nil {
+		return common.NewGameLiftError(common.ServiceCallFailed, "Failed serialize data", err.Error())
+	}
 	if err := c.storeResponse(r.RequestID, resp); err != nil {
 		return err
 	}
-	if err := c.sendMessage(req); err != nil {
+	if err := c.enqueueWrite(queuedWrite{ctx: ctx, requestID: r.RequestID, action: r.Action, data: data}); err != nil {
 		c.sendResponse(r.RequestID, nil, err)
 		return err
 	}
@@ -96,18 +133,6 @@ func (c *websocketClient) SendRequest(req MessageGetter, resp chan<- common.Outc
 	return nil
 }
 
-// SendMessage - sends message to the game server process without waiting for a response.
-func (c *websocketClient) sendMessage(msg any) error {
-	data, err := json.Marshal(msg)
-	if err != nil {
-		return common.NewGameLiftError(common.ServiceCallFailed, "Failed serialize data", err.Error())
-	}
-	if err = c.iTransport.Write(data); err != nil {
-		return common.NewGameLiftError(common.ServiceCallFailed, "Failed write data", err.Error())
-	}
-	return nil
-}
-
 // AddHandler allows to register an incoming message handler with the specified Action.
 func (c *websocketClient) AddHandler(action message.MessageAction, handler func([]byte)) {
 	c.handleMtx.Lock()
@@ -156,14 +181,20 @@ func (c *websocketClient) NotifyRequestTimeout() {
 // Close closes underlying connections and releases their associated resources.
 // All Send calls after Close call will return an error.
 func (c *websocketClient) Close() error {
+	writerDone := c.stopWriter()
+	c.dropBuffered(newNotSentError())
 	c.respMtx.Lock()
 	for reqID, resp := range c.responses {
 		close(resp)
 		delete(c.responses, reqID)
 	}
+	clear(c.inFlight)
 	c.respMtx.Unlock()
 	c.iTransport.PreventAutoReconnect()
-	return c.iTransport.Close()
+	err := c.iTransport.Close()
+	// Closing the transport ends a stalled write.
+	<-writerDone
+	return err
 }
 
 func (c *websocketClient) getHandlerByAction(action message.MessageAction) (func([]byte), bool) {
@@ -226,9 +257,10 @@ func (c *websocketClient) sendResponse(requestID string, data []byte, err error)
 		c.log.Debugf("Response received for message with ID: %s", requestID)
 		return
 	}
-	if data != nil {
+	if data != nil || err != nil {
 		resp <- common.Outcome{Data: data, Error: err}
 	}
 	close(resp)
 	delete(c.responses, requestID)
+	delete(c.inFlight, requestID)
 }
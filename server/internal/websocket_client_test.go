@@ -7,6 +7,7 @@ package internal_test
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -63,9 +64,11 @@ func TestWebsocketClientSendRequest(t *testing.T) {
 		EXPECT().
 		Connect(addr)
 
+	written := make(chan struct{})
 	transportMock.
 		EXPECT().
-		Write([]byte(testRequestJSON))
+		WriteContext(gomock.Any(), []byte(testRequestJSON)).
+		Do(func(context.Context, []byte) { close(written) })
 
 	transportMock.
 		EXPECT().
@@ -81,9 +84,10 @@ func TestWebsocketClientSendRequest(t *testing.T) {
 
 	req := testRequest
 	respCh := make(chan common.Outcome, 1)
-	if err := c.SendRequest(req, respCh); err != nil {
+	if err := c.SendRequest(context.Background(), req, respCh); err != nil {
 		t.Fatal(err)
 	}
+	<-written
 
 	const rawResponse = `{
   "Action": "DescribePlayerSessions",
@@ -211,16 +215,25 @@ func TestWebsocketClientHandlerError(t *testing.T) {
 
 	c.Init(transportMock, logger)
 
+	written := make(chan struct{})
 	transportMock.
 		EXPECT().
-		Write([]byte(testRequestJSON))
+		WriteContext(gomock.Any(), []byte(testRequestJSON)).
+		Do(func(context.Context, []byte) { close(written) })
+	transportMock.
+		EXPECT().
+		PreventAutoReconnect()
+	transportMock.
+		EXPECT().
+		Close()
 
 	req := testRequest
 
 	respCh := make(chan common.Outcome, 1)
-	if err := c.SendRequest(req, respCh); err != nil {
+	if err := c.SendRequest(context.Background(), req, respCh); err != nil {
 		t.Fatal(err)
 	}
+	<-written
 
 	c.RunReadHandler([]byte(`{
 		"Action": null,
@@ -235,6 +248,9 @@ func TestWebsocketClientHandlerError(t *testing.T) {
 	if !reflect.DeepEqual(result.Error, expectedError) {
 		t.Fatalf("unexpected error %s, want %s", result.Error, expectedError)
 	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
 }
 
 // ---------------------------------------------------------------------------
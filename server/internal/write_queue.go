@@ -0,0 +1,152 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal
+
+import (
+	"context"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+)
+
+// queuedWrite - a serialized request waiting to be written to the transport.
+type queuedWrite struct {
+	// ctx - bounds the time the request may wait in the queue and the write itself; it fails once ctx is done.
+	ctx       context.Context
+	requestID string
+	action    message.MessageAction
+	data      []byte
+	// stopExpiry - stops failing the request when ctx is done while it is queued, see context.AfterFunc.
+	// Returns false if the request is already being failed. Nil once the request left the queue.
+	stopExpiry func() bool
+}
+
+// enqueueWrite - queues the request for the writer goroutine, starting it if needed.
+// Returns an error without queueing if the client is closed or the queue is full.
+func (c *websocketClient) enqueueWrite(w queuedWrite) error {
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	if c.closed {
+		return common.NewGameLiftError(common.GameLiftServerNotInitialized, "", "")
+	}
+	if c.writerStop == nil {
+		c.writerStop = make(chan struct{})
+		c.writerDone = make(chan struct{})
+		go c.writeLoop(c.writerStop, c.writerDone)
+	}
+	expire := w
+	w.stopExpiry = context.AfterFunc(w.ctx, func() { c.expireQueued(expire) })
+	select {
+	case c.writeQueue <- w:
+		return nil
+	default:
+		w.stopExpiry()
+		return common.NewGameLiftError(common.WebsocketSendMessageFailure, "", "write queue is full")
+	}
+}
+
+// writeLoop - writes the queued requests in order until stop is closed.
+// A stalled write only delays the requests queued after it, never the callers of SendRequest.
+func (c *websocketClient) writeLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-c.flush:
+			c.flushBuffered()
+		case w := <-c.writeQueue:
+			select {
+			case <-stop:
+				c.failQueuedWrite(w)
+				return
+			default:
+				// Requests held back are written first to keep the order they were sent in.
+				c.flushBuffered()
+				c.write(w)
+			}
+		}
+	}
+}
+
+// write - writes the request, or fails it through its outcome channel if its deadline passed or the write failed.
+// The request is held back instead if the connection is being re-established.
+func (c *websocketClient) write(w queuedWrite) {
+	if w.stopExpiry != nil {
+		if !w.stopExpiry() {
+			// expireQueued fails the request.
+			return
+		}
+		w.stopExpiry = nil
+	}
+	if err := w.ctx.Err(); err != nil {
+		c.log.Debugf("Request %s expired in the write queue: %s", w.requestID, err)
+		c.sendResponse(w.requestID, nil, common.WrapGameLiftError(common.ServiceCallFailed, err))
+		return
+	}
+	if c.buffer(w) {
+		return
+	}
+	c.trackInFlight(w)
+	if err := c.iTransport.WriteContext(w.ctx, w.data); err != nil {
+		// The connection was lost during the write: send the request again once it is re-established.
+		if c.untrackInFlight(w.requestID) && c.buffer(w) {
+			return
+		}
+		c.sendResponse(w.requestID, nil, common.NewGameLiftError(common.ServiceCallFailed, "Failed write data", err.Error()))
+	}
+}
+
+// expireQueued - fails a request whose deadline passed before it was written, without waiting
+// for the writes queued before it. It is not called once the writer goroutine took the request.
+func (c *websocketClient) expireQueued(w queuedWrite) {
+	c.respMtx.Lock()
+	_, waiting := c.responses[w.requestID]
+	c.respMtx.Unlock()
+	if !waiting {
+		return
+	}
+	c.log.Debugf("Request %s expired in the write queue: %s", w.requestID, w.ctx.Err())
+	c.sendResponse(w.requestID, nil, common.WrapGameLiftError(common.ServiceCallFailed, w.ctx.Err()))
+}
+
+// stopWriter - stops the writer goroutine and fails the requests still queued.
+// Further requests are rejected until the client connects again.
+// Returns a channel closed once the write in progress, if any, has returned.
+func (c *websocketClient) stopWriter() <-chan struct{} {
+	c.writeMtx.Lock()
+	c.closed = true
+	stop, done := c.writerStop, c.writerDone
+	c.writerStop, c.writerDone = nil, nil
+	c.writeMtx.Unlock()
+	if stop == nil {
+		done = make(chan struct{})
+		close(done)
+	} else {
+		close(stop)
+	}
+	for {
+		select {
+		case w := <-c.writeQueue:
+			c.failQueuedWrite(w)
+		default:
+			return done
+		}
+	}
+}
+
+// failQueuedWrite - fails a request that was never written because the client was closed.
+func (c *websocketClient) failQueuedWrite(w queuedWrite) {
+	if w.stopExpiry != nil {
+		w.stopExpiry()
+	}
+	c.sendResponse(w.requestID, nil, newNotSentError())
+}
+
+// newNotSentError - the error of the requests never written because the client was closed.
+func newNotSentError() error {
+	return common.NewGameLiftError(common.WebsocketSendMessageFailure, "", "connection closed before the request was sent")
+}
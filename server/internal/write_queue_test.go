@@ -0,0 +1,241 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/goleak"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
+)
+
+func newQueuedRequest(requestID string) request.DescribePlayerSessionsRequest {
+	req := request.NewDescribePlayerSessions()
+	req.RequestID = requestID
+	return req
+}
+
+func writtenRequestID(t *testing.T, data []byte) string {
+	var msg message.Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Errorf("unexpected write %s: %s", data, err)
+	}
+	return msg.RequestID
+}
+
+func assertOutcomeError(t *testing.T, respCh <-chan common.Outcome, errorType common.GameLiftErrorType) {
+	select {
+	case outcome := <-respCh:
+		var gameLiftErr *common.GameLiftError
+		if !errors.As(outcome.Error, &gameLiftErr) || gameLiftErr.ErrorType != errorType {
+			t.Fatalf("Expected %v outcome error but got %v", errorType, outcome.Error)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an outcome")
+	}
+}
+
+// GIVEN a stalled write WHEN more requests are sent THEN callers do not wait, requests are written in order
+// and a request whose deadline passed in the queue fails through its outcome channel
+func TestWebsocketClient_SendRequest_WritesInOrderWithoutBlocking(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	transportMock := mock.NewMockITransport(ctrl)
+	c := new(internal.WebsocketClient)
+	transportMock.EXPECT().SetReadHandler(gomock.Not(gomock.Nil()))
+	c.Init(transportMock, logger)
+
+	release := make(chan struct{})
+	written := make(chan string, 3)
+	transportMock.
+		EXPECT().
+		WriteContext(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, data []byte) {
+			id := writtenRequestID(t, data)
+			if id == "request-1" {
+				<-release
+			}
+			written <- id
+		}).
+		Times(2)
+	transportMock.EXPECT().PreventAutoReconnect()
+	transportMock.EXPECT().Close()
+
+	// WHEN
+	start := time.Now()
+	respChs := make([]chan common.Outcome, 3)
+	expired, cancel := context.WithCancel(context.Background())
+	for i, id := range []string{"request-1", "request-2", "request-3"} {
+		ctx := context.Background()
+		if id == "request-2" {
+			ctx = expired
+		}
+		respChs[i] = make(chan common.Outcome, 1)
+		if err := c.SendRequest(ctx, newQueuedRequest(id), respChs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancel()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("SendRequest blocked for %s behind a stalled write", elapsed)
+	}
+	close(release)
+
+	// THEN
+	common.AssertEqual(t, "request-1", <-written)
+	common.AssertEqual(t, "request-3", <-written)
+	assertOutcomeError(t, respChs[1], common.ServiceCallFailed)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// GIVEN requests waiting behind a stalled write WHEN Close THEN the waiting requests fail and later ones are rejected
+func TestWebsocketClient_Close_FailsQueuedRequests(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	transportMock := mock.NewMockITransport(ctrl)
+	c := new(internal.WebsocketClient)
+	transportMock.EXPECT().SetReadHandler(gomock.Not(gomock.Nil()))
+	c.Init(transportMock, logger)
+
+	writing := make(chan struct{})
+	release := make(chan struct{})
+	transportMock.
+		EXPECT().
+		WriteContext(gomock.Any(), gomock.Any()).
+		Do(func(context.Context, []byte) {
+			close(writing)
+			<-release
+		})
+	transportMock.EXPECT().PreventAutoReconnect()
+	transportMock.EXPECT().Close()
+
+	if err := c.SendRequest(context.Background(), newQueuedRequest("request-1"), make(chan common.Outcome, 1)); err != nil {
+		t.Fatal(err)
+	}
+	<-writing
+	queued := make(chan common.Outcome, 1)
+	if err := c.SendRequest(context.Background(), newQueuedRequest("request-2"), queued); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	closed := make(chan error)
+	go func() { closed <- c.Close() }()
+
+	// THEN
+	assertOutcomeError(t, queued, common.WebsocketSendMessageFailure)
+	close(release)
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	}
+	err := c.SendRequest(context.Background(), newQueuedRequest("request-3"), make(chan common.Outcome, 1))
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.GameLiftServerNotInitialized {
+		t.Fatalf("Expected GameLiftServerNotInitialized error but got %v", err)
+	}
+}
+
+// GIVEN a write stalled on the transport WHEN a heartbeat is queued behind it THEN the heartbeat fails at its deadline
+func TestWebsocketClient_SendRequest_QueuedRequestFailsAtDeadline(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	ctrl := gomock.NewController(t)
+	logger := mock.NewTestLogger(t, ctrl, mock.WithExpectAnyDebug(true))
+	transportMock := mock.NewMockITransport(ctrl)
+	c := new(internal.WebsocketClient)
+	transportMock.EXPECT().SetReadHandler(gomock.Not(gomock.Nil()))
+	c.Init(transportMock, logger)
+
+	writing := make(chan struct{})
+	release := make(chan struct{})
+	transportMock.
+		EXPECT().
+		WriteContext(gomock.Any(), gomock.Any()).
+		Do(func(context.Context, []byte) {
+			close(writing)
+			<-release
+		})
+	transportMock.EXPECT().PreventAutoReconnect()
+	transportMock.EXPECT().Close()
+
+	if err := c.SendRequest(context.Background(), newQueuedRequest("request-1"), make(chan common.Outcome, 1)); err != nil {
+		t.Fatal(err)
+	}
+	<-writing
+
+	// WHEN
+	const deadline = 50 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+	heartbeat := make(chan common.Outcome, 1)
+	start := time.Now()
+	if err := c.SendRequest(ctx, request.NewHeartbeatServerProcess(true), heartbeat); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	assertOutcomeError(t, heartbeat, common.ServiceCallFailed)
+	if elapsed := time.Since(start); elapsed > deadline+200*time.Millisecond {
+		t.Fatalf("Expected the heartbeat to fail at its deadline, took %s", elapsed)
+	}
+	close(release)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// GIVEN an answered request WHEN its ctx is done and its ID is sent again THEN the new request is not failed
+func TestWebsocketClient_SendRequest_ReusedRequestIDNotExpiredByPreviousContext(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// GIVEN
+	c, written := setupInFlightClient(t)
+	const rawResponse = `{"Action":"DescribePlayerSessions","RequestId":"test-request-id","StatusCode":200}`
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan common.Outcome, 1)
+	if err := c.SendRequest(ctx, newQueuedRequest("test-request-id"), first); err != nil {
+		t.Fatal(err)
+	}
+	waitForWrite(t, written)
+	c.RunReadHandler([]byte(rawResponse))
+	common.AssertEqual(t, rawResponse, string((<-first).Data))
+
+	// WHEN
+	second := make(chan common.Outcome, 1)
+	if err := c.SendRequest(context.Background(), newQueuedRequest("test-request-id"), second); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	// THEN
+	waitForWrite(t, written)
+	c.RunReadHandler([]byte(rawResponse))
+	outcome := <-second
+	common.AssertEqual(t, nil, outcome.Error)
+	common.AssertEqual(t, rawResponse, string(outcome.Data))
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
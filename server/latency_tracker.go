@@ -0,0 +1,82 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"maps"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// latencySmoothing - weight of a new latency sample in the moving average of a player's latency to a Region.
+const latencySmoothing = 0.3
+
+// latencyTracker - latencies observed by the game at runtime, by player and Region,
+// smoothed with an exponentially weighted moving average.
+type latencyTracker struct {
+	mtx     sync.Mutex
+	players map[string]map[string]float64
+}
+
+// record - adds a latency sample of the player to the Region.
+func (t *latencyTracker) record(playerID, region string, latency time.Duration) error {
+	if playerID == "" || region == "" {
+		return common.NewGameLiftError(common.ValidationException, "", "PlayerID and Region cannot be empty.")
+	}
+	if latency <= 0 {
+		return common.NewGameLiftError(common.ValidationException, "", "Latency must be positive.")
+	}
+	ms := float64(latency) / float64(time.Millisecond)
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.players == nil {
+		t.players = make(map[string]map[string]float64)
+	}
+	regions, ok := t.players[playerID]
+	if !ok {
+		regions = make(map[string]float64)
+		t.players[playerID] = regions
+	}
+	if previous, ok := regions[region]; ok {
+		ms = previous + latencySmoothing*(ms-previous)
+	}
+	regions[region] = ms
+	return nil
+}
+
+// apply - returns copies of the players with their observed latencies replacing the reported ones.
+// Regions without observed latencies keep the reported values.
+func (t *latencyTracker) apply(players []model.Player) []model.Player {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	updated := make([]model.Player, len(players))
+	for i, player := range players {
+		if observed, ok := t.players[player.PlayerID]; ok {
+			latencies := maps.Clone(player.LatencyInMS)
+			if latencies == nil {
+				latencies = make(map[string]int, len(observed))
+			}
+			for region, ms := range observed {
+				// Amazon GameLift Servers requires latencies of at least 1 millisecond.
+				latencies[region] = max(1, int(math.Round(ms)))
+			}
+			player.LatencyInMS = latencies
+		}
+		updated[i] = player
+	}
+	return updated
+}
+
+// reset - forgets all observed latencies.
+func (t *latencyTracker) reset() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.players = nil
+}
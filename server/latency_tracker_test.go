@@ -0,0 +1,74 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+func TestLatencyTracker_Apply(t *testing.T) {
+	// GIVEN
+	var tracker latencyTracker
+	players := []model.Player{
+		{PlayerID: "player-1", LatencyInMS: map[string]int{"us-west-2": 20, "us-east-1": 80}},
+		{PlayerID: "player-2"},
+	}
+	if err := tracker.record("player-1", "us-west-2", 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.record("player-1", "us-west-2", 200*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.record("player-2", "eu-west-1", 300*time.Microsecond); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	updated := tracker.apply(players)
+
+	// THEN
+	common.AssertEqual(t, 130, updated[0].LatencyInMS["us-west-2"])
+	common.AssertEqual(t, 80, updated[0].LatencyInMS["us-east-1"])
+	common.AssertEqual(t, 1, updated[1].LatencyInMS["eu-west-1"])
+	common.AssertEqual(t, 20, players[0].LatencyInMS["us-west-2"])
+	common.AssertEqual(t, 0, len(players[1].LatencyInMS))
+}
+
+func TestLatencyTracker_Record_ReturnValidationError(t *testing.T) {
+	var tracker latencyTracker
+	assertValidationException(t, tracker.record("", "us-west-2", time.Millisecond))
+	assertValidationException(t, tracker.record("player-1", "us-west-2", 0))
+}
+
+func TestGameLiftServerState_BuildStartMatchBackfillRequest_ObservedLatency(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{}
+	state.isReadyProcess.Store(true)
+	data, err := json.Marshal(&testBackfillMatchmakerData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.OnStartGameSession(&model.GameSession{GameSessionID: testBackfillGameSessionArn, MatchmakerData: string(data)})
+	if err := state.recordPlayerLatency("player-2", "us-west-2", 45*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	req, err := state.buildStartMatchBackfillRequest([]string{"player-1", "player-2"})
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 20, req.Players[0].LatencyInMS["us-west-2"])
+	common.AssertEqual(t, 45, req.Players[1].LatencyInMS["us-west-2"])
+}
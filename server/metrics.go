@@ -12,6 +12,7 @@ import (
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/metrics"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
 )
 
 // Metrics provides a convenient way to create metrics with common configurations.
@@ -129,10 +130,13 @@ func createMetrics(params *MetricsParameters, serverState iGameLiftServerState)
 	return newMetrics(localMetricsFactory), localMetricsFactory, nil
 }
 
-func terminateMetricsFactory(factory metrics.IFactory) {
+// terminateMetricsProcessor - stops the process-wide metrics processor. Replaced for testing purposes.
+var terminateMetricsProcessor = metrics.TerminateMetricsProcessor
+
+func terminateMetricsFactory(factory metrics.IFactory, l log.ILogger) {
 	if factory != nil {
-		if stopErr := metrics.TerminateMetricsProcessor(); stopErr != nil {
-			lg.Warnf("Failed to stop metrics factory: %v", stopErr)
+		if stopErr := terminateMetricsProcessor(); stopErr != nil && l != nil {
+			l.Warnf("Failed to stop metrics factory: %v", stopErr)
 		}
 	}
 }
@@ -6,6 +6,9 @@
 package server
 
 import (
+	"context"
+	"time"
+
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
 )
 
@@ -22,16 +25,63 @@ import (
 //   - AccessKey - the AWS AccessKey of the AWS Credentials with Amazon GameLift Servers Access.
 //   - SecretKey - the AWS SecretKey of the AWS Credentials with Amazon GameLift Servers Access.
 //   - SessionToken - the AWS Token of the AWS Credentials with Amazon GameLift Servers Access if using temporary credentials.
+//   - ReconnectPolicy - optional, how the websocket connection is retried when it cannot be established, see ReconnectPolicy.
 type ServerParameters struct {
-	WebSocketURL string
-	ProcessID    string
-	HostID       string
-	FleetID      string
-	AuthToken    string
-	AwsRegion    string
-	AccessKey    string
-	SecretKey    string
-	SessionToken string
+	WebSocketURL    string
+	ProcessID       string
+	HostID          string
+	FleetID         string
+	AuthToken       string
+	AwsRegion       string
+	AccessKey       string
+	SecretKey       string
+	SessionToken    string
+	ReconnectPolicy *ReconnectPolicy
+}
+
+// ReconnectJitter - how the delay between two connection attempts is randomized, see ReconnectPolicy.
+type ReconnectJitter int
+
+const (
+	// ReconnectJitterFull - waits a random delay between 0 and the exponential delay.
+	// This is the default of a ReconnectPolicy.
+	ReconnectJitterFull ReconnectJitter = iota
+	// ReconnectJitterDecorrelated - waits a random delay between BaseDelay and three times the previous delay.
+	ReconnectJitterDecorrelated
+	// ReconnectJitterNone - waits the exponential delay.
+	ReconnectJitterNone
+)
+
+// UnlimitedReconnectAttempts - ReconnectPolicy.MaxAttempts value to try until the connection is established.
+const UnlimitedReconnectAttempts = -1
+
+// ReconnectPolicy - configures how the server SDK retries to establish the websocket connection
+// to Amazon GameLift Servers, in InitSDK() and when the connection is lost.
+//
+// The exponential delay starts at BaseDelay and doubles after each failed attempt, up to MaxDelay.
+// Jitter spreads the attempts of server processes that lost their connection at the same time,
+// so that they do not reconnect in lockstep.
+// Each failed attempt is logged and reported with a model.EventWebsocketConnectRetry event;
+// giving up is reported with a model.EventWebsocketReconnectFailed event.
+// If ServerParameters.ReconnectPolicy is nil, the default delays and attempts are used without jitter.
+type ReconnectPolicy struct {
+	// BaseDelay - the delay after the first failed attempt. Defaults to 8 seconds.
+	BaseDelay time.Duration
+
+	// MaxDelay - the longest delay between two attempts. Defaults to 32 seconds.
+	MaxDelay time.Duration
+
+	// Jitter - how the delay is randomized. Defaults to ReconnectJitterFull.
+	Jitter ReconnectJitter
+
+	// MaxAttempts - the number of attempts before giving up, or UnlimitedReconnectAttempts. Defaults to 8.
+	MaxAttempts int
+
+	// OnGiveUp - optional, called with the last error when a lost connection could not be re-established.
+	// Return true to shut the server process down gracefully, as on a terminate process signal:
+	// the game session is drained if ProcessParameters.Drain is set, otherwise OnProcessTerminate is called,
+	// or ProcessEnding() and Destroy() if it is not set either.
+	OnGiveUp func(err error) bool
 }
 
 // ProcessParameters - object that communicating the following information about the server process:
@@ -65,6 +115,7 @@ type ProcessParameters struct {
 	// Amazon GameLift Servers calls this function every 60 seconds.
 	// After calling this function Amazon GameLift Servers waits 60 seconds for a response,
 	// and if none is received. records the server process as unhealthy.
+	// Use RegisterHealthCheck to combine several named checks with per-check results instead.
 	OnHealthCheck func() bool
 
 	// Port - the server process listens on for new player connections.
@@ -75,6 +126,97 @@ type ProcessParameters struct {
 
 	// Object with a list of directory paths to game session log files.
 	LogParameters LogParameters
+
+	// Drain - optional, enables a graceful drain when Amazon GameLift Servers terminates the server process.
+	// When set, OnProcessTerminate is not invoked; the server SDK drains the game session and then calls
+	// ProcessEnding() and Destroy() itself, see DrainParameters.
+	Drain *DrainParameters
+
+	// Reconciliation - optional, enables periodic reconciliation of the player sessions accepted by the server process
+	// against the player sessions reported by Amazon GameLift Servers, see ReconciliationParameters.
+	Reconciliation *ReconciliationParameters
+
+	// PlayerSessionPolicy - optional, enables automatic management of the player session creation policy
+	// of the game session by player count and game session age, see PlayerSessionPolicyParameters.
+	PlayerSessionPolicy *PlayerSessionPolicyParameters
+}
+
+// ReconciliationParameters - configures the periodic player session reconciliation.
+//
+// Once the game session is activated, the server SDK periodically calls DescribePlayerSessions() for the ACTIVE
+// player sessions of the game session and compares them with the player sessions accepted with AcceptPlayerSession()
+// and not yet removed with RemovePlayerSession(). Any difference is logged and passed to OnDrift.
+type ReconciliationParameters struct {
+	// Interval - time between reconciliations. Defaults to 60 seconds.
+	Interval time.Duration
+
+	// OnDrift - optional, called when a reconciliation finds a difference.
+	OnDrift func(PlayerSessionDrift)
+}
+
+// PlayerSessionPolicyParameters - configures the automatic player session creation policy management.
+//
+// Once the game session is activated, the server SDK counts its players: the player sessions accepted with
+// AcceptPlayerSession() and not yet removed, plus the player sessions RESERVED for the game session.
+// It calls UpdatePlayerSessionCreationPolicy() with model.DenyAll when the count reaches DenyAt, and with
+// model.AcceptAll when it falls back to AcceptAt. The gap between both thresholds prevents flipping the policy
+// every time a player joins or leaves a full game session.
+// After LockAfter, the policy stays model.DenyAll for the rest of the game session.
+//
+// Do not call UpdatePlayerSessionCreationPolicy() yourself while the policy is managed.
+type PlayerSessionPolicyParameters struct {
+	// DenyAt - player count at which new player sessions are denied.
+	// Defaults to the MaximumPlayerSessionCount of the game session.
+	DenyAt int
+
+	// AcceptAt - player count at or below which new player sessions are accepted again, lower than DenyAt.
+	// Defaults to DenyAt - 1.
+	AcceptAt int
+
+	// LockAfter - optional, time after the activation of the game session when new player sessions
+	// are denied for good, for example when joining a match late makes no sense.
+	LockAfter time.Duration
+
+	// PollInterval - how often the RESERVED player sessions are counted with DescribePlayerSessions().
+	// Accepted and removed player sessions are counted immediately. Defaults to 10 seconds.
+	PollInterval time.Duration
+
+	// OnChange - optional, called after the player session creation policy was changed.
+	OnChange func(PlayerSessionPolicyStatus)
+}
+
+// DrainParameters - configures the graceful drain performed by the server SDK on a terminate process signal.
+//
+// On termination the server SDK:
+//  1. Sets the player session creation policy of the current game session to model.DenyAll.
+//  2. Calls OnDrain so the game can tell connected players that the server is shutting down.
+//  3. Waits until no players are connected, or until SafetyMargin before the termination time (see GetTerminationTime).
+//  4. Calls ProcessEnding() and Destroy(), then calls OnComplete or exits the process.
+type DrainParameters struct {
+	// OnDrain - optional, called once the game session stops accepting new players.
+	// The context expires at the drain deadline. The drain waits for players only after OnDrain returns.
+	OnDrain func(ctx context.Context)
+
+	// PlayerCount - optional, returns the number of players still connected to the server process.
+	// By default, the server SDK counts player sessions accepted with AcceptPlayerSession()
+	// and not yet removed with RemovePlayerSession().
+	PlayerCount func() int
+
+	// OnComplete - optional, called after ProcessEnding() and Destroy() with their combined error.
+	// When nil, the server SDK exits the process, with a non-zero code if any of the calls failed.
+	OnComplete func(err error)
+
+	// SafetyMargin - time reserved before the termination time to call ProcessEnding().
+	// Defaults to 10 seconds.
+	SafetyMargin time.Duration
+
+	// Timeout - the longest the drain can take when it ends before the termination time.
+	// Defaults to 5 minutes minus SafetyMargin, so that ProcessEnding() is called before
+	// Amazon GameLift Servers stops waiting for it.
+	Timeout time.Duration
+
+	// PollInterval - how often the player count is checked. Defaults to 1 second.
+	PollInterval time.Duration
 }
 
 // LogParameters - this data type is used to identify which files generated during a game session
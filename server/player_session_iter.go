@@ -0,0 +1,68 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"iter"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+)
+
+// allPlayerSessions - returns the player sessions matching req across all pages, see AllPlayerSessions.
+func (state *gameLiftServerState) allPlayerSessions(
+	ctx context.Context,
+	req request.DescribePlayerSessionsRequest,
+) iter.Seq2[model.PlayerSession, error] {
+	return func(yield func(model.PlayerSession, error) bool) {
+		nextToken := req.NextToken
+		for {
+			// Each page is a new request, so that every call gets its own RequestID.
+			page := req
+			page.Message = message.NewMessage(message.DescribePlayerSessions)
+			page.NextToken = nextToken
+			res, err := state.describePlayerSessions(ctx, &page)
+			if err != nil {
+				yield(model.PlayerSession{}, err)
+				return
+			}
+			for _, session := range res.PlayerSessions {
+				if !yield(session, nil) {
+					return
+				}
+			}
+			// NextToken is ignored when a player session ID is specified.
+			if res.NextToken == "" || res.NextToken == nextToken || req.PlayerSessionID != "" {
+				return
+			}
+			nextToken = res.NextToken
+		}
+	}
+}
+
+// CollectPlayerSessions - returns the player sessions of seq, stopping after limit player sessions
+// so that no further pages are requested. A limit of zero or less collects all player sessions.
+// Returns the player sessions collected so far and the error if seq fails.
+//
+//	sessions, err := server.CollectPlayerSessions(server.AllPlayerSessions(ctx, req), 500)
+func CollectPlayerSessions(seq iter.Seq2[model.PlayerSession, error], limit int) ([]model.PlayerSession, error) {
+	var sessions []model.PlayerSession
+	if limit > 0 {
+		sessions = make([]model.PlayerSession, 0, min(limit, reconciliationPageSize))
+	}
+	for session, err := range seq {
+		if err != nil {
+			return sessions, err
+		}
+		sessions = append(sessions, session)
+		if limit > 0 && len(sessions) >= limit {
+			break
+		}
+	}
+	return sessions, nil
+}
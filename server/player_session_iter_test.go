@@ -0,0 +1,113 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/mock"
+)
+
+// expectPlayerSessionPages - serves DescribePlayerSessions pages of two player sessions, keyed by NextToken.
+func expectPlayerSessionPages(t *testing.T, manager *mock.MockIGameLiftManager, times int) *[]string {
+	pages := map[string]result.DescribePlayerSessionsResult{
+		"":       {NextToken: "page-2", PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-1"}, {PlayerSessionID: "psess-2"}}},
+		"page-2": {NextToken: "page-3", PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-3"}, {PlayerSessionID: "psess-4"}}},
+		"page-3": {PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-5"}}},
+	}
+	var requestIDs []string
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.AssignableToTypeOf(&request.DescribePlayerSessionsRequest{}), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *request.DescribePlayerSessionsRequest, res any, _ time.Duration) error {
+			common.AssertEqual(t, 2, req.Limit)
+			common.AssertEqual(t, "test-game-session-id", req.GameSessionID)
+			requestIDs = append(requestIDs, req.RequestID)
+			*res.(*result.DescribePlayerSessionsResult) = pages[req.NextToken]
+			return nil
+		}).
+		Times(times)
+	return &requestIDs
+}
+
+func newPagedDescribeRequest() request.DescribePlayerSessionsRequest {
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = "test-game-session-id"
+	req.Limit = 2
+	return req
+}
+
+func TestGameLiftServerState_AllPlayerSessions_FollowsNextToken(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{wsGameLift: manager}
+	state.isReadyProcess.Store(true)
+	requestIDs := expectPlayerSessionPages(t, manager, 3)
+
+	// WHEN
+	sessions, err := CollectPlayerSessions(state.allPlayerSessions(context.Background(), newPagedDescribeRequest()), 0)
+
+	// THEN
+	common.AssertEqual(t, nil, err)
+	common.AssertEqual(t, 5, len(sessions))
+	common.AssertEqual(t, "psess-5", sessions[4].PlayerSessionID)
+	if (*requestIDs)[0] == (*requestIDs)[1] || (*requestIDs)[1] == (*requestIDs)[2] {
+		t.Fatalf("Expected a new request ID for every page but got %v", *requestIDs)
+	}
+}
+
+func TestCollectPlayerSessions_StopAtLimit(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{wsGameLift: manager}
+	state.isReadyProcess.Store(true)
+	expectPlayerSessionPages(t, manager, 2)
+
+	// WHEN
+	sessions, err := CollectPlayerSessions(state.allPlayerSessions(context.Background(), newPagedDescribeRequest()), 3)
+
+	// THEN
+	common.AssertEqual(t, nil, err)
+	common.AssertEqual(t, 3, len(sessions))
+	common.AssertEqual(t, "psess-3", sessions[2].PlayerSessionID)
+}
+
+func TestGameLiftServerState_AllPlayerSessions_YieldError(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{wsGameLift: manager}
+	state.isReadyProcess.Store(true)
+	expectedErr := common.NewGameLiftError(common.InternalServiceException, "", "")
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *request.DescribePlayerSessionsRequest, res any, _ time.Duration) error {
+			if req.NextToken == "" {
+				*res.(*result.DescribePlayerSessionsResult) = result.DescribePlayerSessionsResult{
+					NextToken:      "page-2",
+					PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-1"}},
+				}
+				return nil
+			}
+			return expectedErr
+		}).
+		Times(2)
+
+	// WHEN
+	sessions, err := CollectPlayerSessions(state.allPlayerSessions(context.Background(), newPagedDescribeRequest()), 0)
+
+	// THEN
+	common.AssertEqual(t, expectedErr, err)
+	common.AssertEqual(t, 1, len(sessions))
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+)
+
+// PlayerSessionPolicyStatus - the player session creation policy set by the server SDK
+// and the player count it was decided on, see PlayerSessionPolicyParameters.
+type PlayerSessionPolicyStatus struct {
+	Policy model.PlayerSessionCreationPolicy
+	// Players - accepted and reserved player sessions of the game session.
+	Players int
+	// Locked - LockAfter has elapsed, new player sessions are denied for the rest of the game session.
+	Locked bool
+}
+
+// decidePlayerSessionPolicy - returns the player session creation policy for the player count.
+// Between acceptAt and denyAt the current policy is kept.
+func decidePlayerSessionPolicy(
+	current model.PlayerSessionCreationPolicy,
+	players, denyAt, acceptAt int,
+	locked bool,
+) model.PlayerSessionCreationPolicy {
+	if locked || players >= denyAt {
+		return model.DenyAll
+	}
+	if current == model.DenyAll && players > acceptAt {
+		return model.DenyAll
+	}
+	return model.AcceptAll
+}
+
+// playerSessionPolicy - manages the player session creation policy of the current game session,
+// see PlayerSessionPolicyParameters.
+type playerSessionPolicy struct {
+	state    *gameLiftServerState
+	params   *PlayerSessionPolicyParameters
+	denyAt   int
+	acceptAt int
+
+	// status - the policy last set; game sessions accept new player sessions until told otherwise.
+	status PlayerSessionPolicyStatus
+	// reserved - the RESERVED player sessions reported by the last poll.
+	reserved []string
+}
+
+// newPlayerSessionPolicy - resolves the thresholds of params for a game session of maxPlayers players.
+func newPlayerSessionPolicy(
+	state *gameLiftServerState,
+	params *PlayerSessionPolicyParameters,
+	maxPlayers int,
+) (*playerSessionPolicy, error) {
+	denyAt := params.DenyAt
+	if denyAt == 0 {
+		denyAt = maxPlayers
+	}
+	if denyAt <= 0 {
+		return nil, common.NewGameLiftError(common.ValidationException, "",
+			"Player session policy requires DenyAt or a MaximumPlayerSessionCount for the game session")
+	}
+	acceptAt := params.AcceptAt
+	if acceptAt == 0 || acceptAt >= denyAt {
+		acceptAt = denyAt - 1
+	}
+	return &playerSessionPolicy{
+		state:    state,
+		params:   params,
+		denyAt:   denyAt,
+		acceptAt: acceptAt,
+		status:   PlayerSessionPolicyStatus{Policy: model.AcceptAll},
+	}, nil
+}
+
+// poll - refreshes the RESERVED player sessions of the game session.
+// On failure the previous ones are kept until the next poll.
+func (p *playerSessionPolicy) poll(ctx context.Context) {
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = p.state.gameSessionID
+	req.PlayerSessionStatusFilter = "RESERVED"
+	req.Limit = reconciliationPageSize
+	reserved, err := CollectPlayerSessions(p.state.allPlayerSessions(ctx, req), 0)
+	if err != nil {
+		p.state.logger().Warnf("Could not count reserved player sessions: %s", err)
+		return
+	}
+	p.reserved = make([]string, 0, len(reserved))
+	for _, session := range reserved {
+		p.reserved = append(p.reserved, session.PlayerSessionID)
+	}
+}
+
+// players - counts the accepted, pending and reserved player sessions, once each,
+// as a reserved player session stays in the last poll after it is accepted.
+func (p *playerSessionPolicy) players() int {
+	ids := make(map[string]struct{})
+	for _, session := range p.state.playerSessions.list() {
+		ids[session.PlayerSessionID] = struct{}{}
+	}
+	for _, id := range p.state.playerSessions.pending() {
+		ids[id] = struct{}{}
+	}
+	for _, id := range p.reserved {
+		if !p.state.playerSessions.wasRemoved(id) {
+			ids[id] = struct{}{}
+		}
+	}
+	return len(ids)
+}
+
+// evaluate - updates the player session creation policy if the player count requires it.
+// A failed update is retried on the next evaluation.
+func (p *playerSessionPolicy) evaluate(ctx context.Context) {
+	// Once the process is terminating, the policy belongs to the drain.
+	if p.state.getProcessState() != model.ProcessSessionActive {
+		return
+	}
+	players := p.players()
+	next := decidePlayerSessionPolicy(p.status.Policy, players, p.denyAt, p.acceptAt, p.status.Locked)
+	p.status.Players = players
+	if next == p.status.Policy {
+		return
+	}
+	if err := p.state.updatePlayerSessionCreationPolicy(ctx, &next); err != nil {
+		p.state.logger().Warnf("Could not update player session creation policy to %s: %s", next.String(), err)
+		return
+	}
+	p.state.logger().Debugf("Player session creation policy changed from %s to %s with %d players (locked: %t)",
+		p.status.Policy.String(), next.String(), players, p.status.Locked)
+	p.status.Policy = next
+	if p.params.OnChange != nil {
+		p.params.OnChange(p.status)
+	}
+}
+
+// startPlayerSessionPolicy - manages the player session creation policy until done is closed,
+// see PlayerSessionPolicyParameters.
+func (state *gameLiftServerState) startPlayerSessionPolicy(
+	done <-chan bool,
+	params *PlayerSessionPolicyParameters,
+	maxPlayers int,
+) {
+	p, err := newPlayerSessionPolicy(state, params, maxPlayers)
+	if err != nil {
+		state.logger().Warnf("Player session policy disabled: %s", err)
+		return
+	}
+	interval := params.PollInterval
+	if interval == 0 {
+		interval = common.PlayerSessionPolicyPollIntervalDefault
+	}
+	state.logger().Debugf("Player session policy started, deny at %d players, accept at %d players.", p.denyAt, p.acceptAt)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lock <-chan time.Time
+	if params.LockAfter > 0 {
+		timer := time.NewTimer(params.LockAfter)
+		defer timer.Stop()
+		lock = timer.C
+	}
+	ctx := context.Background()
+	p.poll(ctx)
+	p.evaluate(ctx)
+	for {
+		select {
+		case <-done:
+			return
+		case <-state.playerSessionsChanged:
+		case <-ticker.C:
+			p.poll(ctx)
+		case <-lock:
+			p.status.Locked = true
+		}
+		p.evaluate(ctx)
+	}
+}
+
+// notifyPlayerSessionsChanged - wakes up the player session policy after a player session was accepted or removed.
+func (state *gameLiftServerState) notifyPlayerSessionsChanged() {
+	select {
+	case state.playerSessionsChanged <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
+	"github.com/golang/mock/gomock"
+)
+
+func TestDecidePlayerSessionPolicy(t *testing.T) {
+	cases := map[string]struct {
+		current  model.PlayerSessionCreationPolicy
+		players  int
+		locked   bool
+		expected model.PlayerSessionCreationPolicy
+	}{
+		"below capacity accepts":           {current: model.AcceptAll, players: 7, expected: model.AcceptAll},
+		"capacity reached denies":          {current: model.AcceptAll, players: 10, expected: model.DenyAll},
+		"between thresholds keeps deny":    {current: model.DenyAll, players: 9, expected: model.DenyAll},
+		"between thresholds keeps accept":  {current: model.AcceptAll, players: 9, expected: model.AcceptAll},
+		"accept threshold reached accepts": {current: model.DenyAll, players: 8, expected: model.AcceptAll},
+		"locked denies":                    {current: model.AcceptAll, players: 0, locked: true, expected: model.DenyAll},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := decidePlayerSessionPolicy(c.current, c.players, 10, 8, c.locked)
+			common.AssertEqual(t, c.expected, actual)
+		})
+	}
+}
+
+func TestNewPlayerSessionPolicy_Thresholds(t *testing.T) {
+	// GIVEN / WHEN - thresholds default to the maximum player session count
+	p, err := newPlayerSessionPolicy(nil, &PlayerSessionPolicyParameters{}, 10)
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 10, p.denyAt)
+	common.AssertEqual(t, 9, p.acceptAt)
+
+	// WHEN - no threshold and no maximum player session count
+	_, err = newPlayerSessionPolicy(nil, &PlayerSessionPolicyParameters{}, 0)
+	// THEN
+	assertValidationException(t, err)
+}
+
+// GIVEN a full game session WHEN players leave THEN deny new player sessions, then accept them again below AcceptAt
+func TestPlayerSessionPolicy_Evaluate_Hysteresis(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := &gameLiftServerState{wsGameLift: manager, gameSessionID: "test-game-session-id"}
+	state.isReadyProcess.Store(true)
+	for _, next := range []model.ProcessState{model.ProcessReady, model.ProcessSessionAssigned, model.ProcessSessionActive} {
+		if err := state.setProcessState(next, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var changes []PlayerSessionPolicyStatus
+	p, err := newPlayerSessionPolicy(state, &PlayerSessionPolicyParameters{
+		DenyAt:   3,
+		AcceptAt: 1,
+		OnChange: func(status PlayerSessionPolicyStatus) { changes = append(changes, status) },
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.AssignableToTypeOf(&request.DescribePlayerSessionsRequest{}), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *request.DescribePlayerSessionsRequest, res any, _ time.Duration) error {
+			common.AssertEqual(t, "RESERVED", req.PlayerSessionStatusFilter)
+			*res.(*result.DescribePlayerSessionsResult) = result.DescribePlayerSessionsResult{
+				PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-1"}, {PlayerSessionID: "psess-3"}},
+			}
+			return nil
+		})
+	var policies []model.PlayerSessionCreationPolicy
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.AssignableToTypeOf(request.UpdatePlayerSessionCreationPolicyRequest{}), nil, gomock.Any()).
+		Do(func(_ context.Context, req internal.MessageGetter, _ any, _ time.Duration) {
+			policies = append(policies, *req.(request.UpdatePlayerSessionCreationPolicyRequest).PlayerSessionPolicy)
+		}).
+		Return(nil).
+		Times(2)
+
+	// WHEN - psess-1 reserved then accepted, psess-2 accepted, psess-3 reserved
+	for _, id := range []string{"psess-1", "psess-2"} {
+		if err := state.playerSessions.reserve(id, nil); err != nil {
+			t.Fatal(err)
+		}
+		state.playerSessions.confirm(id)
+	}
+	p.poll(context.Background())
+	p.evaluate(context.Background())
+	// THEN
+	common.AssertEqual(t, 3, p.status.Players)
+	common.AssertEqual(t, model.DenyAll, p.status.Policy)
+
+	// WHEN - one player leaves
+	state.playerSessions.remove("psess-1")
+	p.evaluate(context.Background())
+	// THEN
+	common.AssertEqual(t, 2, p.status.Players)
+	common.AssertEqual(t, model.DenyAll, p.status.Policy)
+
+	// WHEN - another player leaves
+	state.playerSessions.remove("psess-2")
+	p.evaluate(context.Background())
+	// THEN
+	common.AssertEqual(t, 1, p.status.Players)
+	common.AssertEqual(t, model.AcceptAll, p.status.Policy)
+	common.AssertEqual(t, 2, len(policies))
+	common.AssertEqual(t, model.DenyAll, policies[0])
+	common.AssertEqual(t, model.AcceptAll, policies[1])
+	common.AssertEqual(t, 2, len(changes))
+}
+
+// GIVEN LockAfter elapsed WHEN evaluate THEN deny new player sessions regardless of the player count
+func TestPlayerSessionPolicy_Evaluate_Locked(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := &gameLiftServerState{wsGameLift: manager, gameSessionID: "test-game-session-id"}
+	state.isReadyProcess.Store(true)
+	for _, next := range []model.ProcessState{model.ProcessReady, model.ProcessSessionAssigned, model.ProcessSessionActive} {
+		if err := state.setProcessState(next, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := newPlayerSessionPolicy(state, &PlayerSessionPolicyParameters{LockAfter: time.Minute}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.AssignableToTypeOf(request.UpdatePlayerSessionCreationPolicyRequest{}), nil, gomock.Any()).
+		Return(nil)
+
+	// WHEN
+	p.status.Locked = true
+	p.evaluate(context.Background())
+
+	// THEN
+	common.AssertEqual(t, 0, p.status.Players)
+	common.AssertEqual(t, model.DenyAll, p.status.Policy)
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// AcceptedPlayerSession - a player session accepted by the server process with AcceptPlayerSession
+// and not yet removed with RemovePlayerSession.
+type AcceptedPlayerSession struct {
+	PlayerSessionID string
+	// AcceptedAt - the time Amazon GameLift Servers confirmed the player session.
+	AcceptedAt time.Time
+	// Metadata - connection details passed to AcceptPlayerSessionWithMetadata, for example the remote address.
+	Metadata map[string]string
+}
+
+// PlayerSessionDrift - differences between the player sessions accepted by the server process
+// and the ACTIVE player sessions reported by Amazon GameLift Servers, see ReconciliationParameters.
+type PlayerSessionDrift struct {
+	// Removed - player sessions ACTIVE on Amazon GameLift Servers that were already removed by this process.
+	Removed []model.PlayerSession
+	// Unknown - player sessions ACTIVE on Amazon GameLift Servers that were never accepted by this process.
+	Unknown []model.PlayerSession
+	// NotActive - player sessions accepted by this process that Amazon GameLift Servers does not report as ACTIVE.
+	NotActive []AcceptedPlayerSession
+}
+
+// HasDrift - reports whether any difference was found.
+func (d *PlayerSessionDrift) HasDrift() bool {
+	return len(d.Removed) > 0 || len(d.Unknown) > 0 || len(d.NotActive) > 0
+}
+
+type registeredPlayerSession struct {
+	AcceptedPlayerSession
+	// pending - the accept request was sent but not answered yet.
+	pending bool
+}
+
+// playerSessionRegistry - tracks the player sessions accepted by the server process and not yet removed.
+// The zero value is an empty registry.
+type playerSessionRegistry struct {
+	mtx      sync.RWMutex
+	sessions map[string]*registeredPlayerSession
+	removed  map[string]time.Time
+}
+
+// reserve - registers a pending accept of the player session.
+// Returns a ConflictException error if the player session is already accepted or being accepted.
+func (r *playerSessionRegistry) reserve(playerSessionID string, metadata map[string]string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.sessions[playerSessionID]; ok {
+		return common.NewGameLiftError(common.ConflictException, "",
+			fmt.Sprintf("Player session %s has already been accepted.", playerSessionID))
+	}
+	if r.sessions == nil {
+		r.sessions = make(map[string]*registeredPlayerSession)
+	}
+	r.sessions[playerSessionID] = &registeredPlayerSession{
+		AcceptedPlayerSession: AcceptedPlayerSession{
+			PlayerSessionID: playerSessionID,
+			Metadata:        maps.Clone(metadata),
+		},
+		pending: true,
+	}
+	return nil
+}
+
+// confirm - marks a reserved player session as accepted.
+func (r *playerSessionRegistry) confirm(playerSessionID string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if s, ok := r.sessions[playerSessionID]; ok {
+		s.pending = false
+		s.AcceptedAt = time.Now()
+	}
+	delete(r.removed, playerSessionID)
+}
+
+// release - forgets a reserved player session whose accept request failed.
+func (r *playerSessionRegistry) release(playerSessionID string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if s, ok := r.sessions[playerSessionID]; ok && s.pending {
+		delete(r.sessions, playerSessionID)
+	}
+}
+
+func (r *playerSessionRegistry) remove(playerSessionID string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	delete(r.sessions, playerSessionID)
+	if r.removed == nil {
+		r.removed = make(map[string]time.Time)
+	}
+	r.removed[playerSessionID] = time.Now()
+}
+
+// reset - forgets all player sessions, called when a new game session starts
+// so that the sessions of previous game sessions do not accumulate.
+func (r *playerSessionRegistry) reset() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.sessions = nil
+	r.removed = nil
+}
+
+// count - returns the number of accepted player sessions.
+func (r *playerSessionRegistry) count() int {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	n := 0
+	for _, s := range r.sessions {
+		if !s.pending {
+			n++
+		}
+	}
+	return n
+}
+
+// pending - returns the IDs of the player sessions being accepted.
+func (r *playerSessionRegistry) pending() []string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	var ids []string
+	for id, s := range r.sessions {
+		if s.pending {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// wasRemoved - reports whether the player session was removed.
+func (r *playerSessionRegistry) wasRemoved(playerSessionID string) bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	_, ok := r.removed[playerSessionID]
+	return ok
+}
+
+// list - returns the accepted player sessions, oldest first.
+func (r *playerSessionRegistry) list() []AcceptedPlayerSession {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	accepted := make([]AcceptedPlayerSession, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		if s.pending {
+			continue
+		}
+		session := s.AcceptedPlayerSession
+		session.Metadata = maps.Clone(s.Metadata)
+		accepted = append(accepted, session)
+	}
+	sort.Slice(accepted, func(i, j int) bool {
+		if accepted[i].AcceptedAt.Equal(accepted[j].AcceptedAt) {
+			return accepted[i].PlayerSessionID < accepted[j].PlayerSessionID
+		}
+		return accepted[i].AcceptedAt.Before(accepted[j].AcceptedAt)
+	})
+	return accepted
+}
+
+// diff - compares the accepted player sessions with the player sessions Amazon GameLift Servers reports as ACTIVE.
+// Sessions accepted or removed after asOf are skipped, as the service may not have seen the change yet.
+func (r *playerSessionRegistry) diff(active []model.PlayerSession, asOf time.Time) PlayerSessionDrift {
+	var drift PlayerSessionDrift
+	seen := make(map[string]struct{}, len(active))
+	r.mtx.RLock()
+	for _, session := range active {
+		seen[session.PlayerSessionID] = struct{}{}
+		if _, ok := r.sessions[session.PlayerSessionID]; ok {
+			continue
+		}
+		if removedAt, ok := r.removed[session.PlayerSessionID]; ok {
+			if removedAt.Before(asOf) {
+				drift.Removed = append(drift.Removed, session)
+			}
+			continue
+		}
+		drift.Unknown = append(drift.Unknown, session)
+	}
+	r.mtx.RUnlock()
+	for _, accepted := range r.list() {
+		if _, ok := seen[accepted.PlayerSessionID]; !ok && accepted.AcceptedAt.Before(asOf) {
+			drift.NotActive = append(drift.NotActive, accepted)
+		}
+	}
+	return drift
+}
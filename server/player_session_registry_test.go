@@ -0,0 +1,199 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPlayerSessionRegistry_ReserveTwice_ReturnConflict(t *testing.T) {
+	// GIVEN
+	var registry playerSessionRegistry
+	if err := registry.reserve("psess-1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	pendingErr := registry.reserve("psess-1", nil)
+	registry.confirm("psess-1")
+	acceptedErr := registry.reserve("psess-1", nil)
+
+	// THEN
+	for _, err := range []error{pendingErr, acceptedErr} {
+		var gameLiftErr *common.GameLiftError
+		if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.ConflictException {
+			t.Fatalf("Expected ConflictException error but got %v", err)
+		}
+	}
+	common.AssertEqual(t, 1, registry.count())
+}
+
+func TestPlayerSessionRegistry_Release(t *testing.T) {
+	// GIVEN
+	var registry playerSessionRegistry
+	if err := registry.reserve("psess-1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	registry.release("psess-1")
+
+	// THEN
+	common.AssertEqual(t, 0, registry.count())
+	if err := registry.reserve("psess-1", nil); err != nil {
+		t.Fatalf("Expected released player session to be accepted again but got %v", err)
+	}
+}
+
+func TestPlayerSessionRegistry_Reset(t *testing.T) {
+	// GIVEN
+	var registry playerSessionRegistry
+	for _, id := range []string{"psess-1", "psess-2"} {
+		if err := registry.reserve(id, nil); err != nil {
+			t.Fatal(err)
+		}
+		registry.confirm(id)
+	}
+	registry.remove("psess-2")
+
+	// WHEN
+	registry.reset()
+
+	// THEN
+	common.AssertEqual(t, 0, registry.count())
+	common.AssertEqual(t, false, registry.wasRemoved("psess-2"))
+	if err := registry.reserve("psess-1", nil); err != nil {
+		t.Fatalf("Expected the player session to be accepted again after reset but got %v", err)
+	}
+}
+
+func TestGameLiftServerState_OnStartGameSession_ResetsPlayerSessions(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{}
+	state.isReadyProcess.Store(true)
+	if err := state.playerSessions.reserve("psess-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	state.playerSessions.confirm("psess-1")
+	state.playerSessions.remove("psess-1")
+
+	// WHEN
+	state.OnStartGameSession(&model.GameSession{GameSessionID: "test-game-session-id"})
+
+	// THEN
+	common.AssertEqual(t, false, state.playerSessions.wasRemoved("psess-1"))
+}
+
+func TestPlayerSessionRegistry_List(t *testing.T) {
+	// GIVEN
+	var registry playerSessionRegistry
+	for _, id := range []string{"psess-2", "psess-1", "psess-3"} {
+		if err := registry.reserve(id, map[string]string{"remoteAddr": id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	registry.confirm("psess-2")
+	registry.confirm("psess-1")
+	registry.remove("psess-2")
+
+	// WHEN
+	accepted := registry.list()
+	accepted[0].Metadata["remoteAddr"] = "modified"
+
+	// THEN
+	common.AssertEqual(t, 1, len(accepted))
+	common.AssertEqual(t, "psess-1", accepted[0].PlayerSessionID)
+	common.AssertEqual(t, "psess-1", registry.list()[0].Metadata["remoteAddr"])
+}
+
+func TestPlayerSessionRegistry_Diff(t *testing.T) {
+	// GIVEN
+	var registry playerSessionRegistry
+	for _, id := range []string{"psess-accepted", "psess-not-active", "psess-removed"} {
+		if err := registry.reserve(id, nil); err != nil {
+			t.Fatal(err)
+		}
+		registry.confirm(id)
+	}
+	registry.remove("psess-removed")
+	asOf := time.Now()
+	if err := registry.reserve("psess-new", nil); err != nil {
+		t.Fatal(err)
+	}
+	registry.confirm("psess-new")
+	active := []model.PlayerSession{
+		{PlayerSessionID: "psess-accepted"},
+		{PlayerSessionID: "psess-removed"},
+		{PlayerSessionID: "psess-unknown"},
+	}
+
+	// WHEN
+	drift := registry.diff(active, asOf)
+
+	// THEN
+	if !drift.HasDrift() {
+		t.Fatal("Expected drift")
+	}
+	common.AssertEqual(t, 1, len(drift.Removed))
+	common.AssertEqual(t, "psess-removed", drift.Removed[0].PlayerSessionID)
+	common.AssertEqual(t, 1, len(drift.Unknown))
+	common.AssertEqual(t, "psess-unknown", drift.Unknown[0].PlayerSessionID)
+	common.AssertEqual(t, 1, len(drift.NotActive))
+	common.AssertEqual(t, "psess-not-active", drift.NotActive[0].PlayerSessionID)
+}
+
+func TestGameLiftServerState_ReconcilePlayerSessions_FollowsNextToken(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{wsGameLift: manager, gameSessionID: "test-game-session-id"}
+	state.isReadyProcess.Store(true)
+	if err := state.playerSessions.reserve("psess-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	state.playerSessions.confirm("psess-1")
+	time.Sleep(time.Millisecond)
+
+	pages := map[string]result.DescribePlayerSessionsResult{
+		"": {
+			NextToken:      "page-2",
+			PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-1"}},
+		},
+		"page-2": {
+			PlayerSessions: []model.PlayerSession{{PlayerSessionID: "psess-2"}},
+		},
+	}
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.AssignableToTypeOf(&request.DescribePlayerSessionsRequest{}), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *request.DescribePlayerSessionsRequest, res any, _ time.Duration) error {
+			common.AssertEqual(t, "test-game-session-id", req.GameSessionID)
+			common.AssertEqual(t, "ACTIVE", req.PlayerSessionStatusFilter)
+			*res.(*result.DescribePlayerSessionsResult) = pages[req.NextToken]
+			return nil
+		}).
+		Times(2)
+
+	// WHEN
+	drift, err := state.reconcilePlayerSessions(context.Background())
+
+	// THEN
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 0, len(drift.NotActive))
+	common.AssertEqual(t, 1, len(drift.Unknown))
+	common.AssertEqual(t, "psess-2", drift.Unknown[0].PlayerSessionID)
+}
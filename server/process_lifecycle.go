@@ -0,0 +1,104 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+)
+
+// Names of the calls and messages that trigger a process state transition.
+const (
+	triggerProcessReady        = "ProcessReady"
+	triggerOnStartGameSession  = "OnStartGameSession"
+	triggerActivateGameSession = "ActivateGameSession"
+	triggerOnTerminateProcess  = "OnTerminateProcess"
+	triggerReconnectGiveUp     = "ReconnectPolicy.OnGiveUp"
+	triggerProcessEnding       = "ProcessEnding"
+)
+
+// processLifecycle - tracks the model.ProcessState of the server process and the history of its transitions.
+// The zero value is a lifecycle in the model.ProcessInitialized state.
+type processLifecycle struct {
+	mtx     sync.RWMutex
+	current model.ProcessState
+	history []model.ProcessStateTransition
+}
+
+// state - returns the current state of the server process.
+func (l *processLifecycle) state() model.ProcessState {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	return l.current
+}
+
+// transitions - returns a copy of all transitions made so far, oldest first.
+func (l *processLifecycle) transitions() []model.ProcessStateTransition {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	history := make([]model.ProcessStateTransition, len(l.history))
+	copy(history, l.history)
+	return history
+}
+
+// check - returns an IllegalStateTransition error if the process cannot move to the next state.
+// The state is not changed, use check to validate a call before sending its request.
+func (l *processLifecycle) check(next model.ProcessState, trigger string) error {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	return l.checkLocked(next, trigger)
+}
+
+// transition - moves the process to the next state and records it in the history.
+// Returns an IllegalStateTransition error and keeps the current state if the transition is not allowed.
+func (l *processLifecycle) transition(next model.ProcessState, trigger string) (model.ProcessStateTransition, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if err := l.checkLocked(next, trigger); err != nil {
+		return model.ProcessStateTransition{}, err
+	}
+	t := model.ProcessStateTransition{
+		From:    l.current,
+		To:      next,
+		Trigger: trigger,
+		Time:    time.Now(),
+	}
+	l.current = next
+	l.history = append(l.history, t)
+	return t, nil
+}
+
+// require - returns an IllegalStateTransition error if the current state is not one of the allowed states.
+func (l *processLifecycle) require(trigger string, allowed ...model.ProcessState) error {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	for _, s := range allowed {
+		if l.current == s {
+			return nil
+		}
+	}
+	return common.NewGameLiftError(
+		common.IllegalStateTransition,
+		"",
+		fmt.Sprintf("%s is not allowed in the %s state.", trigger, l.current.String()),
+	)
+}
+
+func (l *processLifecycle) checkLocked(next model.ProcessState, trigger string) error {
+	if l.current.CanTransitionTo(next) {
+		return nil
+	}
+	return common.NewGameLiftError(
+		common.IllegalStateTransition,
+		"",
+		fmt.Sprintf("%s cannot move the server process from the %s state to the %s state.",
+			trigger, l.current.String(), next.String()),
+	)
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/golang/mock/gomock"
+)
+
+func assertIllegalStateTransition(t *testing.T, err error) {
+	t.Helper()
+	var gameLiftErr *common.GameLiftError
+	if !errors.As(err, &gameLiftErr) || gameLiftErr.ErrorType != common.IllegalStateTransition {
+		t.Fatalf("Expected IllegalStateTransition error but got %v", err)
+	}
+}
+
+func TestProcessLifecycle_Transition(t *testing.T) {
+	// GIVEN
+	var lifecycle processLifecycle
+
+	// WHEN
+	for _, next := range []model.ProcessState{
+		model.ProcessReady,
+		model.ProcessSessionAssigned,
+		model.ProcessSessionActive,
+		model.ProcessTerminating,
+		model.ProcessEnded,
+	} {
+		if _, err := lifecycle.transition(next, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// THEN
+	common.AssertEqual(t, model.ProcessEnded, lifecycle.state())
+	history := lifecycle.transitions()
+	common.AssertEqual(t, 5, len(history))
+	common.AssertEqual(t, model.ProcessInitialized, history[0].From)
+	common.AssertEqual(t, model.ProcessReady, history[0].To)
+	common.AssertEqual(t, "test", history[0].Trigger)
+	common.AssertEqual(t, model.ProcessTerminating, history[4].From)
+	for i := 1; i < len(history); i++ {
+		if history[i].Time.Before(history[i-1].Time) {
+			t.Fatalf("History is not ordered: %v", history)
+		}
+	}
+}
+
+func TestProcessLifecycle_IllegalTransition(t *testing.T) {
+	// GIVEN
+	var lifecycle processLifecycle
+
+	// WHEN
+	_, err := lifecycle.transition(model.ProcessSessionActive, triggerActivateGameSession)
+
+	// THEN
+	assertIllegalStateTransition(t, err)
+	common.AssertEqual(t, model.ProcessInitialized, lifecycle.state())
+	common.AssertEqual(t, 0, len(lifecycle.transitions()))
+}
+
+func TestProcessLifecycle_Require(t *testing.T) {
+	// GIVEN
+	var lifecycle processLifecycle
+	if _, err := lifecycle.transition(model.ProcessReady, triggerProcessReady); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	allowedErr := lifecycle.require("test", model.ProcessInitialized, model.ProcessReady)
+	deniedErr := lifecycle.require("test", model.ProcessSessionActive)
+
+	// THEN
+	if allowedErr != nil {
+		t.Fatal(allowedErr)
+	}
+	assertIllegalStateTransition(t, deniedErr)
+}
+
+func TestGameLiftServerState_ActivateGameSessionTwice_ReturnIllegalStateTransition(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{wsGameLift: manager}
+	state.isReadyProcess.Store(true)
+	if err := state.setProcessState(model.ProcessReady, triggerProcessReady); err != nil {
+		t.Fatal(err)
+	}
+	state.OnStartGameSession(&model.GameSession{GameSessionID: "test-game-session-id"})
+	manager.
+		EXPECT().
+		HandleRequest(gomock.Any(), gomock.Any(), nil, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// WHEN
+	firstErr := state.activateGameSession(context.Background())
+	secondErr := state.activateGameSession(context.Background())
+
+	// THEN
+	if firstErr != nil {
+		t.Fatal(firstErr)
+	}
+	assertIllegalStateTransition(t, secondErr)
+	common.AssertEqual(t, model.ProcessSessionActive, state.getProcessState())
+	common.AssertEqual(t, 3, len(state.getProcessStateHistory()))
+}
+
+func TestGameLiftServerState_AcceptPlayerSessionBeforeActivation_ReturnIllegalStateTransition(t *testing.T) {
+	// GIVEN
+	manager := setupNewMockIGameLiftManager(t)
+	state := gameLiftServerState{wsGameLift: manager}
+	state.isReadyProcess.Store(true)
+	if err := state.setProcessState(model.ProcessReady, triggerProcessReady); err != nil {
+		t.Fatal(err)
+	}
+	state.OnStartGameSession(&model.GameSession{GameSessionID: "test-game-session-id"})
+
+	// WHEN
+	err := state.acceptPlayerSession(context.Background(), "psess-test", nil)
+
+	// THEN
+	assertIllegalStateTransition(t, err)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+)
+
+// reconciliationPageSize - number of player sessions requested per DescribePlayerSessions call.
+const reconciliationPageSize = 50
+
+// reconcilePlayerSessions - compares the accepted player sessions with the ACTIVE player sessions
+// of the current game session reported by Amazon GameLift Servers.
+func (state *gameLiftServerState) reconcilePlayerSessions(ctx context.Context) (PlayerSessionDrift, error) {
+	if state.gameSessionID == "" {
+		return PlayerSessionDrift{}, common.NewGameLiftError(common.GamesessionIDNotSet, "", "")
+	}
+	asOf := time.Now()
+	active, err := state.describeActivePlayerSessions(ctx)
+	if err != nil {
+		return PlayerSessionDrift{}, err
+	}
+	return state.playerSessions.diff(active, asOf), nil
+}
+
+// describeActivePlayerSessions - returns all ACTIVE player sessions of the current game session,
+// following NextToken across pages.
+func (state *gameLiftServerState) describeActivePlayerSessions(ctx context.Context) ([]model.PlayerSession, error) {
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = state.gameSessionID
+	req.PlayerSessionStatusFilter = "ACTIVE"
+	req.Limit = reconciliationPageSize
+	active, err := CollectPlayerSessions(state.allPlayerSessions(ctx, req), 0)
+	if err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// startReconciliation - periodically reconciles player sessions until done is closed, see ReconciliationParameters.
+func (state *gameLiftServerState) startReconciliation(done <-chan bool, params *ReconciliationParameters) {
+	interval := params.Interval
+	if interval == 0 {
+		interval = common.ReconciliationIntervalDefault
+	}
+	state.logger().Debugf("Player session reconciliation started.")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		drift, err := state.reconcilePlayerSessions(context.Background())
+		if err != nil {
+			state.logger().Warnf("Could not reconcile player sessions: %s", err)
+			continue
+		}
+		if !drift.HasDrift() {
+			continue
+		}
+		state.logger().Warnf("Player session drift detected: %d removed, %d unknown, %d not active",
+			len(drift.Removed), len(drift.Unknown), len(drift.NotActive))
+		if params.OnDrift != nil {
+			params.OnDrift(drift)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
+)
+
+// backoffPolicy - returns the transport policy for the reconnect policy, or the default one if p is nil.
+func (p *ReconnectPolicy) backoffPolicy() transport.BackoffPolicy {
+	if p == nil {
+		return transport.DefaultBackoffPolicy()
+	}
+	policy := transport.BackoffPolicy{
+		BaseDelay:   p.BaseDelay,
+		MaxDelay:    p.MaxDelay,
+		Jitter:      transport.JitterFull,
+		MaxAttempts: p.MaxAttempts,
+	}
+	switch p.Jitter {
+	case ReconnectJitterDecorrelated:
+		policy.Jitter = transport.JitterDecorrelated
+	case ReconnectJitterNone:
+		policy.Jitter = transport.JitterNone
+	}
+	if p.MaxAttempts == UnlimitedReconnectAttempts {
+		policy.MaxAttempts = transport.UnlimitedAttempts
+	}
+	return policy
+}
+
+// onConnectionEvent - handles the events of the websocket client: reports the connection metrics,
+// applies the reconnect policy and passes the event to the subscribers.
+func (state *gameLiftServerState) onConnectionEvent(event model.Event) {
+	state.connection.record(state.getMetricsFactory(), event, state.logger())
+	if event.Type == model.EventWebsocketReconnectFailed {
+		// The event is emitted by the transport goroutine that the shutdown closes.
+		go state.onReconnectGaveUp(event.Err)
+	}
+	state.emitEvent(event)
+}
+
+// onReconnectGaveUp - calls ReconnectPolicy.OnGiveUp once a lost connection could not be re-established,
+// and ends the server process if it asks to.
+func (state *gameLiftServerState) onReconnectGaveUp(err error) {
+	policy := state.reconnectPolicy
+	if policy == nil || policy.OnGiveUp == nil {
+		return
+	}
+	if !policy.OnGiveUp(err) {
+		state.logger().Warnf("The websocket connection could not be re-established, the server process keeps running: %s", err)
+		return
+	}
+	state.logger().Warnf("Shutting down the server process, the websocket connection could not be re-established: %s", err)
+	state.terminate(triggerReconnectGiveUp)
+}
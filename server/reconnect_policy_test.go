@@ -0,0 +1,69 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/transport"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+func TestReconnectPolicy_BackoffPolicy(t *testing.T) {
+	// GIVEN
+	var unset *ReconnectPolicy
+	policy := &ReconnectPolicy{
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+		Jitter:      ReconnectJitterDecorrelated,
+		MaxAttempts: UnlimitedReconnectAttempts,
+	}
+
+	// WHEN
+	defaults := unset.backoffPolicy()
+	converted := policy.backoffPolicy()
+
+	// THEN
+	common.AssertEqual(t, transport.DefaultBackoffPolicy(), defaults)
+	common.AssertEqual(t, transport.JitterNone, defaults.Jitter)
+	common.AssertEqual(t, transport.BackoffPolicy{
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+		Jitter:      transport.JitterDecorrelated,
+		MaxAttempts: transport.UnlimitedAttempts,
+	}, converted)
+}
+
+// GIVEN an OnGiveUp hook asking to shut down WHEN the reconnect fails THEN the server process is terminated
+func TestGameLiftServerState_OnConnectionEvent_ReconnectGaveUp_Terminates(t *testing.T) {
+	// GIVEN
+	reconnectErr := errors.New("dial failed")
+	var gaveUpWith error
+	terminated := make(chan struct{})
+	state := &gameLiftServerState{
+		reconnectPolicy: &ReconnectPolicy{OnGiveUp: func(err error) bool {
+			gaveUpWith = err
+			return true
+		}},
+		parameters: &ProcessParameters{OnProcessTerminate: func() { close(terminated) }},
+		lg:         log.GetDefaultLogger("test"),
+	}
+
+	// WHEN
+	state.onConnectionEvent(model.Event{Type: model.EventWebsocketReconnectFailed, Err: reconnectErr})
+
+	// THEN
+	select {
+	case <-terminated:
+	case <-time.After(time.Second):
+		t.Fatal("Expected OnProcessTerminate to be called")
+	}
+	common.AssertEqual(t, reconnectErr, gaveUpWith)
+}
@@ -6,8 +6,11 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"iter"
 	"math/rand"
 	"os"
 	"sync"
@@ -21,10 +24,14 @@ import (
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/internal/security"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
 )
 
 var localRnd *rand.Rand
 
+// localRndMtx guards localRnd, which is shared by every server SDK client in the process.
+var localRndMtx sync.Mutex
+
 // store in a variable in order to unit test
 var exitFunc = os.Exit
 
@@ -36,20 +43,32 @@ func init() {
 }
 
 type iGameLiftServerState interface {
-	processReady(*ProcessParameters) error
-	processEnding() error
-	activateGameSession() error
-	updatePlayerSessionCreationPolicy(*model.PlayerSessionCreationPolicy) error
+	processReady(context.Context, *ProcessParameters) error
+	processEnding(context.Context) error
+	activateGameSession(context.Context) error
+	updatePlayerSessionCreationPolicy(context.Context, *model.PlayerSessionCreationPolicy) error
 	getGameSessionID() (string, error)
 	getTerminationTime() (int64, error)
-	acceptPlayerSession(playerSessionID string) error
-	removePlayerSession(playerSessionID string) error
-	describePlayerSessions(*request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error)
-	startMatchBackfill(*request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, error)
-	stopMatchBackfill(*request.StopMatchBackfillRequest) error
-	getComputeCertificate() (result.GetComputeCertificateResult, error)
-	getFleetRoleCredentials(*request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error)
-	listContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error)
+	getMatchmakerData() (model.MatchmakerData, error)
+	getProcessState() model.ProcessState
+	getProcessStateHistory() []model.ProcessStateTransition
+	acceptPlayerSession(ctx context.Context, playerSessionID string, metadata map[string]string) error
+	listAcceptedPlayerSessions() []AcceptedPlayerSession
+	reconcilePlayerSessions(context.Context) (PlayerSessionDrift, error)
+	registerHealthCheck(name string, check HealthCheckFunc, timeout time.Duration) error
+	healthCheckResults() []HealthCheckResult
+	removePlayerSession(ctx context.Context, playerSessionID string) error
+	describePlayerSessions(context.Context, *request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error)
+	allPlayerSessions(context.Context, request.DescribePlayerSessionsRequest) iter.Seq2[model.PlayerSession, error]
+	startMatchBackfill(context.Context, *request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, error)
+	buildStartMatchBackfillRequest(playerIDs []string) (request.StartMatchBackfillRequest, error)
+	recordPlayerLatency(playerID, region string, latency time.Duration) error
+	applyObservedLatencies(players []model.Player) []model.Player
+	buildStartMatchBackfillRequestForPlayerSessions(ctx context.Context, playerSessionIDs []string) (request.StartMatchBackfillRequest, error)
+	stopMatchBackfill(context.Context, *request.StopMatchBackfillRequest) error
+	getComputeCertificate(context.Context) (result.GetComputeCertificateResult, error)
+	getFleetRoleCredentials(context.Context, *request.GetFleetRoleCredentialsRequest) (result.GetFleetRoleCredentialsResult, error)
+	listContainersNetworkInfo(context.Context) (result.ListContainersNetworkInfoResult, error)
 	setMetricsFactory(metrics.IFactory)
 	destroy() error
 }
@@ -61,24 +80,58 @@ type gameLiftServerState struct {
 	processID string
 	hostID    string
 	fleetID   string
+	// reconnectPolicy - the policy passed to InitSDK(), if any, see onReconnectGaveUp.
+	reconnectPolicy *ReconnectPolicy
 
 	gameSessionID   string
 	terminationTime int64
+	// maxPlayerSessions - the MaximumPlayerSessionCount of the current game session.
+	maxPlayerSessions int
 
 	isReadyProcess common.AtomicBool
 	onManagedEC2   bool
+	lifecycle      processLifecycle
+	playerSessions playerSessionRegistry
+	healthChecks   healthCheckRegistry
+	connection     connectionMetrics
+	events         *eventBus
 
 	fleetRoleResultCache map[string]result.GetFleetRoleCredentialsResult
 	mtx                  sync.Mutex
 
+	// matchmakerData - the last matchmaker data parsed for matchmakerDataSessionID, used to diff game session updates.
+	matchmakerData          *model.MatchmakerData
+	matchmakerDataSessionID string
+	matchmakerDataMtx       sync.Mutex
+	latency                 latencyTracker
+
 	defaultJitterIntervalMs int64
 	healthCheckInterval     time.Duration
 	healthCheckTimeout      time.Duration
 	serviceCallTimeout      time.Duration
 
-	metricsFactory metrics.IFactory
+	// metricsFactory - read by the goroutines of the websocket client, guarded by metricsFactoryMtx.
+	metricsFactory    metrics.IFactory
+	metricsFactoryMtx sync.RWMutex
+	lg                log.ILogger
+
+	// updateGameSessionHandlers - called with every game session update before
+	// ProcessParameters.OnUpdateGameSession, see subscribeUpdateGameSession.
+	updateGameSessionHandlers    map[int]func(model.UpdateGameSession)
+	nextUpdateGameSessionHandler int
+	updateGameSessionHandlersMtx sync.Mutex
 
 	shutdown chan bool
+	// playerSessionsChanged - signals the player session policy that a player session was accepted or removed.
+	playerSessionsChanged chan struct{}
+}
+
+// logger - returns the logger bound to this state, or the package logger if none was bound.
+func (state *gameLiftServerState) logger() log.ILogger {
+	if state.lg != nil {
+		return state.lg
+	}
+	return lg
 }
 
 func (state *gameLiftServerState) init(params ServerParameters, wsGameLift internal.IGameLiftManager) error {
@@ -111,26 +164,27 @@ func (state *gameLiftServerState) init(params ServerParameters, wsGameLift inter
 	state.processID = params.ProcessID
 	state.hostID = params.HostID
 	state.fleetID = params.FleetID
+	state.reconnectPolicy = params.ReconnectPolicy
 	state.onManagedEC2 = true
 	state.defaultJitterIntervalMs = common.GetEnvDurationOrDefault(
 		common.HealthcheckMaxJitter,
 		common.HealthcheckMaxJitterDefault,
-		lg,
+		state.logger(),
 	).Milliseconds()
 	state.healthCheckInterval = common.GetEnvDurationOrDefault(
 		common.HealthcheckInterval,
 		common.HealthcheckIntervalDefault,
-		lg,
+		state.logger(),
 	)
 	state.healthCheckTimeout = common.GetEnvDurationOrDefault(
 		common.HealthcheckTimeout,
 		common.HealthcheckTimeoutDefault,
-		lg,
+		state.logger(),
 	)
 	state.serviceCallTimeout = common.GetEnvDurationOrDefault(
 		common.ServiceCallTimeout,
 		common.ServiceCallTimeoutDefault,
-		lg,
+		state.logger(),
 	)
 
 	var sigV4QueryParameters map[string]string
@@ -151,7 +205,7 @@ func (state *gameLiftServerState) init(params ServerParameters, wsGameLift inter
 
 			state.hostID = metadata.GetHostId()
 		}
-		sigV4QueryParameters = getSigV4QueryParameters(params.AwsRegion, params.AccessKey, params.SecretKey, params.SessionToken)
+		sigV4QueryParameters = state.getSigV4QueryParameters(params.AwsRegion, params.AccessKey, params.SecretKey, params.SessionToken)
 	}
 
 	state.wsGameLift = wsGameLift
@@ -169,7 +223,7 @@ func (state *gameLiftServerState) init(params ServerParameters, wsGameLift inter
 	return nil
 }
 
-func getSigV4QueryParameters(awsRegion, accessKey, secretKey, sessionToken string) map[string]string {
+func (state *gameLiftServerState) getSigV4QueryParameters(awsRegion, accessKey, secretKey, sessionToken string) map[string]string {
 	awsCredentials := security.AwsCredentials{AccessKey: accessKey, SecretKey: secretKey, SessionToken: sessionToken}
 	queryParamsToSign := map[string]string{
 		common.ComputeIDKey: state.hostID,
@@ -186,12 +240,12 @@ func getSigV4QueryParameters(awsRegion, accessKey, secretKey, sessionToken strin
 
 	sigV4QueryParameters, err := security.GenerateSigV4QueryParameters(sigV4Parameters)
 	if err != nil {
-		log.Fatalf("Error generating SigV4 query string: %v\n", err)
+		log.Fatalf(state.logger(), "Error generating SigV4 query string: %v\n", err)
 	}
 	return sigV4QueryParameters
 }
 
-func (state *gameLiftServerState) processReady(params *ProcessParameters) error {
+func (state *gameLiftServerState) processReady(ctx context.Context, params *ProcessParameters) error {
 	if params == nil {
 		return common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
@@ -199,6 +253,9 @@ func (state *gameLiftServerState) processReady(params *ProcessParameters) error
 	if err != nil {
 		return err
 	}
+	if err := state.lifecycle.check(model.ProcessReady, triggerProcessReady); err != nil {
+		return err
+	}
 	var res message.ResponseMessage
 	state.parameters = params
 	req := request.NewActivateServerProcess(
@@ -221,41 +278,66 @@ func (state *gameLiftServerState) processReady(params *ProcessParameters) error
 	}
 
 	// Wait for response from ActivateServerProcess() request
-	err = state.wsGameLift.HandleRequest(req, &res, ActivateServerProcessRequestTimeoutInSeconds)
+	err = state.wsGameLift.HandleRequest(ctx, req, &res, ActivateServerProcessRequestTimeoutInSeconds)
 
 	if err != nil {
-		return common.NewGameLiftError(common.ProcessNotReady, "", err.Error())
+		return common.WrapGameLiftError(common.ProcessNotReady, err)
+	}
+	if err := state.setProcessState(model.ProcessReady, triggerProcessReady); err != nil {
+		return err
 	}
 	state.isReadyProcess.Store(true)
 	state.shutdown = make(chan bool)
+	state.playerSessionsChanged = make(chan struct{}, 1)
 	go state.startHealthCheck(state.shutdown)
 	return nil
 }
 
-func (state *gameLiftServerState) processEnding() error {
-	err := state.wsGameLift.HandleRequest(request.NewTerminateServerProcess(), nil, state.serviceCallTimeout)
+func (state *gameLiftServerState) processEnding(ctx context.Context) error {
+	if err := state.lifecycle.check(model.ProcessEnded, triggerProcessEnding); err != nil {
+		return err
+	}
+	err := state.wsGameLift.HandleRequest(ctx, request.NewTerminateServerProcess(), nil, state.serviceCallTimeout)
 
 	if err != nil {
-		return common.NewGameLiftError(common.ProcessEndingFailed, "", err.Error())
+		return common.WrapGameLiftError(common.ProcessEndingFailed, err)
 	}
+	state.emitEvent(model.Event{Type: model.EventProcessEndingSent, GameSessionID: state.gameSessionID})
 	state.stopServerProcess()
+	if err := state.setProcessState(model.ProcessEnded, triggerProcessEnding); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (state *gameLiftServerState) activateGameSession() error {
+func (state *gameLiftServerState) activateGameSession(ctx context.Context) error {
 	if !state.isReadyProcess.Load() {
 		return common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
 	if state.gameSessionID == "" {
 		return common.NewGameLiftError(common.GamesessionIDNotSet, "", "")
 	}
+	if err := state.lifecycle.check(model.ProcessSessionActive, triggerActivateGameSession); err != nil {
+		return err
+	}
 	req := request.NewActivateGameSession(state.gameSessionID)
-	err := state.wsGameLift.HandleRequest(req, nil, state.serviceCallTimeout)
-	return err
+	if err := state.wsGameLift.HandleRequest(ctx, req, nil, state.serviceCallTimeout); err != nil {
+		return err
+	}
+	if err := state.setProcessState(model.ProcessSessionActive, triggerActivateGameSession); err != nil {
+		return err
+	}
+	if state.parameters != nil && state.parameters.Reconciliation != nil {
+		go state.startReconciliation(state.shutdown, state.parameters.Reconciliation)
+	}
+	if state.parameters != nil && state.parameters.PlayerSessionPolicy != nil {
+		go state.startPlayerSessionPolicy(state.shutdown, state.parameters.PlayerSessionPolicy, state.maxPlayerSessions)
+	}
+	return nil
 }
 
-func (state *gameLiftServerState) updatePlayerSessionCreationPolicy(policy *model.PlayerSessionCreationPolicy) error {
+func (state *gameLiftServerState) updatePlayerSessionCreationPolicy(ctx context.Context, policy *model.PlayerSessionCreationPolicy) error {
 	if !state.isReadyProcess.Load() {
 		return common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
@@ -265,12 +347,17 @@ func (state *gameLiftServerState) updatePlayerSessionCreationPolicy(policy *mode
 	if policy == nil {
 		return common.NewGameLiftError(common.BadRequestException, "", "PlayerSessionCreationPolicy is required.")
 	}
-	err := ValidatePlayerSessionCreationPolicy(*policy)
+	err := state.lifecycle.require("UpdatePlayerSessionCreationPolicy",
+		model.ProcessSessionAssigned, model.ProcessSessionActive, model.ProcessTerminating)
+	if err != nil {
+		return err
+	}
+	err = ValidatePlayerSessionCreationPolicy(*policy)
 	if err != nil {
 		return err
 	}
 	req := request.NewUpdatePlayerSessionCreationPolicy(state.gameSessionID, *policy)
-	err = state.wsGameLift.HandleRequest(req, nil, state.serviceCallTimeout)
+	err = state.wsGameLift.HandleRequest(ctx, req, nil, state.serviceCallTimeout)
 	return err
 }
 
@@ -278,6 +365,26 @@ func (state *gameLiftServerState) getGameSessionID() (string, error) {
 	return state.gameSessionID, nil
 }
 
+// getProcessState - returns the current lifecycle state of the server process.
+func (state *gameLiftServerState) getProcessState() model.ProcessState {
+	return state.lifecycle.state()
+}
+
+// getProcessStateHistory - returns all lifecycle state transitions of the server process, oldest first.
+func (state *gameLiftServerState) getProcessStateHistory() []model.ProcessStateTransition {
+	return state.lifecycle.transitions()
+}
+
+// setProcessState - moves the server process to the next lifecycle state and logs the transition.
+func (state *gameLiftServerState) setProcessState(next model.ProcessState, trigger string) error {
+	t, err := state.lifecycle.transition(next, trigger)
+	if err != nil {
+		return err
+	}
+	state.logger().Debugf("Process state changed from %s to %s by %s", t.From.String(), t.To.String(), trigger)
+	return nil
+}
+
 // getTerminationTime - returns number of seconds that have elapsed since Unix epoch time begins (00:00:00 UTC Jan 1 1970).
 func (state *gameLiftServerState) getTerminationTime() (int64, error) {
 	if state.terminationTime == 0 {
@@ -286,7 +393,7 @@ func (state *gameLiftServerState) getTerminationTime() (int64, error) {
 	return state.terminationTime, nil
 }
 
-func (state *gameLiftServerState) acceptPlayerSession(playerSessionID string) error {
+func (state *gameLiftServerState) acceptPlayerSession(ctx context.Context, playerSessionID string, metadata map[string]string) error {
 	if !state.isReadyProcess.Load() {
 		return common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
@@ -297,12 +404,31 @@ func (state *gameLiftServerState) acceptPlayerSession(playerSessionID string) er
 	if err != nil {
 		return err
 	}
+	err = state.lifecycle.require("AcceptPlayerSession", model.ProcessSessionActive, model.ProcessTerminating)
+	if err != nil {
+		return err
+	}
+	if err := state.playerSessions.reserve(playerSessionID, metadata); err != nil {
+		return err
+	}
 	req := request.NewAcceptPlayerSession(state.gameSessionID, playerSessionID)
-	err = state.wsGameLift.HandleRequest(req, nil, state.serviceCallTimeout)
-	return err
+	err = state.wsGameLift.HandleRequest(ctx, req, nil, state.serviceCallTimeout)
+	if err != nil {
+		state.playerSessions.release(playerSessionID)
+		state.notifyPlayerSessionsChanged()
+		return err
+	}
+	state.playerSessions.confirm(playerSessionID)
+	state.notifyPlayerSessionsChanged()
+	return nil
+}
+
+// listAcceptedPlayerSessions - returns the player sessions accepted by this process and not yet removed, oldest first.
+func (state *gameLiftServerState) listAcceptedPlayerSessions() []AcceptedPlayerSession {
+	return state.playerSessions.list()
 }
 
-func (state *gameLiftServerState) removePlayerSession(playerSessionID string) error {
+func (state *gameLiftServerState) removePlayerSession(ctx context.Context, playerSessionID string) error {
 	if !state.isReadyProcess.Load() {
 		return common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
@@ -313,12 +439,21 @@ func (state *gameLiftServerState) removePlayerSession(playerSessionID string) er
 	if err != nil {
 		return err
 	}
+	err = state.lifecycle.require("RemovePlayerSession", model.ProcessSessionActive, model.ProcessTerminating)
+	if err != nil {
+		return err
+	}
 	req := request.NewRemovePlayerSession(state.gameSessionID, playerSessionID)
-	err = state.wsGameLift.HandleRequest(req, nil, state.serviceCallTimeout)
-	return err
+	err = state.wsGameLift.HandleRequest(ctx, req, nil, state.serviceCallTimeout)
+	if err != nil {
+		return err
+	}
+	state.playerSessions.remove(playerSessionID)
+	state.notifyPlayerSessionsChanged()
+	return nil
 }
 
-func (state *gameLiftServerState) describePlayerSessions(req *request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error) {
+func (state *gameLiftServerState) describePlayerSessions(ctx context.Context, req *request.DescribePlayerSessionsRequest) (result.DescribePlayerSessionsResult, error) {
 	var playerSessionResult result.DescribePlayerSessionsResult
 	if !state.isReadyProcess.Load() {
 		return playerSessionResult, common.NewGameLiftError(common.ProcessNotReady, "", "")
@@ -330,11 +465,11 @@ func (state *gameLiftServerState) describePlayerSessions(req *request.DescribePl
 	if err != nil {
 		return playerSessionResult, err
 	}
-	err = state.wsGameLift.HandleRequest(req, &playerSessionResult, state.serviceCallTimeout)
+	err = state.wsGameLift.HandleRequest(ctx, req, &playerSessionResult, state.serviceCallTimeout)
 	return playerSessionResult, err
 }
 
-func (state *gameLiftServerState) startMatchBackfill(req *request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, error) {
+func (state *gameLiftServerState) startMatchBackfill(ctx context.Context, req *request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, error) {
 	var startMatchBackfillResult result.StartMatchBackfillResult
 	if !state.isReadyProcess.Load() {
 		return startMatchBackfillResult, common.NewGameLiftError(common.ProcessNotReady, "", "")
@@ -346,11 +481,11 @@ func (state *gameLiftServerState) startMatchBackfill(req *request.StartMatchBack
 	if err != nil {
 		return startMatchBackfillResult, err
 	}
-	err = state.wsGameLift.HandleRequest(req, &startMatchBackfillResult, state.serviceCallTimeout)
+	err = state.wsGameLift.HandleRequest(ctx, req, &startMatchBackfillResult, state.serviceCallTimeout)
 	return startMatchBackfillResult, err
 }
 
-func (state *gameLiftServerState) stopMatchBackfill(req *request.StopMatchBackfillRequest) error {
+func (state *gameLiftServerState) stopMatchBackfill(ctx context.Context, req *request.StopMatchBackfillRequest) error {
 	if !state.isReadyProcess.Load() {
 		return common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
@@ -361,23 +496,25 @@ func (state *gameLiftServerState) stopMatchBackfill(req *request.StopMatchBackfi
 	if err != nil {
 		return err
 	}
-	err = state.wsGameLift.HandleRequest(req, nil, state.serviceCallTimeout)
+	err = state.wsGameLift.HandleRequest(ctx, req, nil, state.serviceCallTimeout)
 	return err
 }
 
-func (state *gameLiftServerState) getComputeCertificate() (result.GetComputeCertificateResult, error) {
-	lg.Debugf("Calling GetComputeCertificate")
+func (state *gameLiftServerState) getComputeCertificate(ctx context.Context) (result.GetComputeCertificateResult, error) {
+	state.logger().Debugf("Calling GetComputeCertificate")
 	var res result.GetComputeCertificateResult
 	if !state.isReadyProcess.Load() {
 		return res, common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
-	err := state.wsGameLift.HandleRequest(request.NewGetComputeCertificate(), &res, state.serviceCallTimeout)
+	err := state.wsGameLift.HandleRequest(ctx, request.NewGetComputeCertificate(), &res, state.serviceCallTimeout)
 	return res, err
 }
 
-func (state *gameLiftServerState) listContainersNetworkInfo() (result.ListContainersNetworkInfoResult, error) {
-	lg.Debugf("Calling ListContainersNetworkInfo")
-	return state.wsGameLift.FetchContainersNetworkInfo()
+func (state *gameLiftServerState) listContainersNetworkInfo(
+	ctx context.Context,
+) (result.ListContainersNetworkInfoResult, error) {
+	state.logger().Debugf("Calling ListContainersNetworkInfo")
+	return state.wsGameLift.FetchContainersNetworkInfo(ctx)
 }
 
 func (state *gameLiftServerState) getRoleCredentialsFromCache(roleArn string) (result.GetFleetRoleCredentialsResult, bool) {
@@ -394,9 +531,10 @@ func (state *gameLiftServerState) getRoleCredentialsFromCache(roleArn string) (r
 }
 
 func (state *gameLiftServerState) getFleetRoleCredentials(
+	ctx context.Context,
 	req *request.GetFleetRoleCredentialsRequest,
 ) (result.GetFleetRoleCredentialsResult, error) {
-	lg.Debugf("Calling GetFleetRoleCredentials")
+	state.logger().Debugf("Calling GetFleetRoleCredentials")
 	if req == nil {
 		return result.GetFleetRoleCredentialsResult{},
 			common.NewGameLiftError(common.BadRequestException, "", "GetFleetRoleCredentialsRequest is required.")
@@ -426,7 +564,7 @@ func (state *gameLiftServerState) getFleetRoleCredentials(
 		return res, common.NewGameLiftError(common.ProcessNotReady, "", "")
 	}
 
-	err = state.wsGameLift.HandleRequest(req, &res, state.serviceCallTimeout)
+	err = state.wsGameLift.HandleRequest(ctx, req, &res, state.serviceCallTimeout)
 	if err != nil {
 		return res, err
 	}
@@ -449,7 +587,7 @@ func (state *gameLiftServerState) destroy() error {
 	}
 	err := state.wsGameLift.Disconnect()
 	state.wsGameLift = nil
-	state.metricsFactory = nil
+	state.setMetricsFactory(nil)
 	return err
 }
 
@@ -462,7 +600,7 @@ func (state *gameLiftServerState) stopServerProcess() {
 }
 
 func (state *gameLiftServerState) startHealthCheck(done <-chan bool) {
-	lg.Debugf("HealthCheck thread started.")
+	state.logger().Debugf("HealthCheck thread started.")
 	for state.isReadyProcess.Load() {
 		timeout := time.After(state.getNextHealthCheckIntervalSeconds())
 		go state.heartbeatServerProcess(done)
@@ -476,35 +614,88 @@ func (state *gameLiftServerState) startHealthCheck(done <-chan bool) {
 }
 
 func (state *gameLiftServerState) heartbeatServerProcess(done <-chan bool) {
-	res := make(chan bool)
+	// Buffered so that a response arriving after the timeout does not block the goroutine forever.
+	res := make(chan bool, 1)
 	go func(res chan<- bool) {
-		if state.parameters != nil && state.parameters.OnHealthCheck != nil {
-			lg.Debugf("Reporting health using the OnHealthCheck callback.")
-			res <- state.parameters.OnHealthCheck()
-		} else {
+		hasCallback := state.parameters != nil && state.parameters.OnHealthCheck != nil
+		hasChecks := !state.healthChecks.empty()
+		if !hasCallback && !hasChecks {
 			close(res)
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), state.healthChecksTimeout())
+		defer cancel()
+		// The callback runs concurrently with the registered checks, so that slow checks do not leave it
+		// only the rest of the health check timeout.
+		callback := make(chan bool, 1)
+		if hasCallback {
+			onHealthCheck := state.parameters.OnHealthCheck
+			go func() {
+				state.logger().Debugf("Reporting health using the OnHealthCheck callback.")
+				callback <- onHealthCheck()
+			}()
+		}
+		healthy := true
+		if hasChecks {
+			state.logger().Debugf("Running registered health checks.")
+			healthy = state.healthChecks.run(ctx, state.getMetricsFactory(), state.logger())
+		}
+		if hasCallback {
+			// Without registered checks the callback keeps the whole health check timeout, as before.
+			var deadline <-chan struct{}
+			if hasChecks {
+				deadline = ctx.Done()
+			}
+			select {
+			case callbackHealthy := <-callback:
+				healthy = callbackHealthy && healthy
+			case <-deadline:
+				state.logger().Debugf("OnHealthCheck callback did not return before the health checks deadline.")
+				healthy = false
+			}
 		}
+		res <- healthy
 	}(res)
 	timeout := time.After(state.healthCheckTimeout)
 	status := false
 	select {
 	case <-timeout:
-		lg.Debugf("Timed out waiting for health response from the server process. Reporting as unhealthy.")
+		state.logger().Debugf("Timed out waiting for health response from the server process. Reporting as unhealthy.")
 		status = false
 	case status = <-res:
-		lg.Debugf("Received health response from the server process: %v", status)
+		state.logger().Debugf("Received health response from the server process: %v", status)
 	case <-done:
 		return
 	}
 	var response message.Message
 	err := state.wsGameLift.HandleRequest(
+		context.Background(),
 		request.NewHeartbeatServerProcess(status),
 		&response,
 		state.serviceCallTimeout,
 	)
 	if err != nil {
-		lg.Warnf("Could not send health status: %s", err)
+		state.logger().Warnf("Could not send health status: %s", err)
+		state.emitEvent(model.Event{Type: model.EventHeartbeatFailed, Healthy: status, Err: err})
+		return
 	}
+	state.emitEvent(model.Event{Type: model.EventHeartbeatSent, Healthy: status})
+}
+
+// healthChecksTimeout - the deadline of the registered health checks. It is shorter than the wait for the
+// health response, so that the results of the checks are reported rather than a timeout.
+func (state *gameLiftServerState) healthChecksTimeout() time.Duration {
+	return state.healthCheckTimeout * healthChecksTimeoutPercent / 100
+}
+
+// registerHealthCheck - adds a named health check to the heartbeat, see RegisterHealthCheck.
+func (state *gameLiftServerState) registerHealthCheck(name string, check HealthCheckFunc, timeout time.Duration) error {
+	return state.healthChecks.register(name, check, timeout)
+}
+
+// healthCheckResults - returns the last result of every registered health check, ordered by name.
+func (state *gameLiftServerState) healthCheckResults() []HealthCheckResult {
+	return state.healthChecks.results()
 }
 
 // getNextHealthCheckIntervalSeconds - return a healthCheck interval +/- a random value
@@ -512,27 +703,37 @@ func (state *gameLiftServerState) heartbeatServerProcess(done <-chan bool) {
 //
 //nolint:gosec // weak math random generator is enough in this case
 func (state *gameLiftServerState) getNextHealthCheckIntervalSeconds() time.Duration {
+	localRndMtx.Lock()
 	jitterMs := 2*localRnd.Int63n(state.defaultJitterIntervalMs) - state.defaultJitterIntervalMs
+	localRndMtx.Unlock()
 	return state.healthCheckInterval - time.Duration(jitterMs)*time.Millisecond
 }
 
 // OnStartGameSession handler for message.CreateGameSessionMessage (already started in a separate goroutine).
 func (state *gameLiftServerState) OnStartGameSession(session *model.GameSession) {
 	if session == nil {
-		lg.Warnf("OnStartGameSession was called with nil game session")
+		state.logger().Warnf("OnStartGameSession was called with nil game session")
 		return
 	}
-	if state.metricsFactory != nil {
-		state.metricsFactory.OnStartGameSession(session.GameSessionID)
+	if factory := state.getMetricsFactory(); factory != nil {
+		factory.OnStartGameSession(session.GameSessionID)
 	}
+	state.emitEvent(model.Event{Type: model.EventCreateGameSessionReceived, GameSessionID: session.GameSessionID})
 	// Inject data that already exists on the server
 	session.FleetID = state.fleetID
-	lg.Debugf("server got the startGameSession signal. GameSession : %s", session.GameSessionID)
+	state.logger().Debugf("server got the startGameSession signal. GameSession : %s", session.GameSessionID)
 	if !state.isReadyProcess.Load() {
-		lg.Debugf("Got a game session on inactive process. Ignoring.")
+		state.logger().Debugf("Got a game session on inactive process. Ignoring.")
 		return
 	}
+	if err := state.setProcessState(model.ProcessSessionAssigned, triggerOnStartGameSession); err != nil {
+		state.logger().Warnf("Unexpected game session %s: %s", session.GameSessionID, err)
+	}
 	state.gameSessionID = session.GameSessionID
+	state.maxPlayerSessions = session.MaximumPlayerSessionCount
+	state.latency.reset()
+	state.playerSessions.reset()
+	state.diffMatchmakerData(session)
 	if state.parameters != nil && state.parameters.OnStartGameSession != nil {
 		state.parameters.OnStartGameSession(*session)
 	}
@@ -545,57 +746,163 @@ func (state *gameLiftServerState) OnUpdateGameSession(
 	backfillTicketID string,
 ) {
 	if gameSession == nil {
-		lg.Warnf("OnUpdateGameSession was called with nil game session")
+		state.logger().Warnf("OnUpdateGameSession was called with nil game session")
 		return
 	}
-	lg.Debugf("ServerState got the updateGameSession signal. GameSession : %s", gameSession.GameSessionID)
+	state.logger().Debugf("ServerState got the updateGameSession signal. GameSession : %s", gameSession.GameSessionID)
+	state.emitEvent(model.Event{Type: model.EventUpdateGameSessionReceived, GameSessionID: gameSession.GameSessionID})
 	if !state.isReadyProcess.Load() {
-		lg.Warnf("Got an updated game session on inactive process.")
+		state.logger().Warnf("Got an updated game session on inactive process.")
 		return
 	}
 	if updateReason == nil {
-		lg.Warnf("OnUpdateGameSession was called with nil update reason")
+		state.logger().Warnf("OnUpdateGameSession was called with nil update reason")
+	}
+	update := model.UpdateGameSession{
+		GameSession:        *gameSession,
+		UpdateReason:       updateReason,
+		BackfillTicketID:   backfillTicketID,
+		MatchmakerDataDiff: state.diffMatchmakerData(gameSession),
+	}
+	state.updateGameSessionHandlersMtx.Lock()
+	handlers := make([]func(model.UpdateGameSession), 0, len(state.updateGameSessionHandlers))
+	for _, handler := range state.updateGameSessionHandlers {
+		handlers = append(handlers, handler)
+	}
+	state.updateGameSessionHandlersMtx.Unlock()
+	for _, handler := range handlers {
+		handler(update)
 	}
 	if state.parameters != nil && state.parameters.OnUpdateGameSession != nil {
-		state.parameters.OnUpdateGameSession(
-			model.UpdateGameSession{
-				GameSession:      *gameSession,
-				UpdateReason:     updateReason,
-				BackfillTicketID: backfillTicketID,
-			},
-		)
+		state.parameters.OnUpdateGameSession(update)
+	}
+}
+
+// subscribeUpdateGameSession - registers a handler called with every game session update, before
+// ProcessParameters.OnUpdateGameSession. Returns a function that unregisters it.
+func (state *gameLiftServerState) subscribeUpdateGameSession(handler func(model.UpdateGameSession)) func() {
+	state.updateGameSessionHandlersMtx.Lock()
+	defer state.updateGameSessionHandlersMtx.Unlock()
+	if state.updateGameSessionHandlers == nil {
+		state.updateGameSessionHandlers = make(map[int]func(model.UpdateGameSession))
+	}
+	id := state.nextUpdateGameSessionHandler
+	state.nextUpdateGameSessionHandler++
+	state.updateGameSessionHandlers[id] = handler
+	return func() {
+		state.updateGameSessionHandlersMtx.Lock()
+		defer state.updateGameSessionHandlersMtx.Unlock()
+		delete(state.updateGameSessionHandlers, id)
+	}
+}
+
+// diffMatchmakerData - parses the matchmaker data of the game session and returns its differences from the
+// matchmaker data previously received for the same game session, or from empty data for a new game session.
+// Returns nil if the matchmaker data cannot be parsed.
+func (state *gameLiftServerState) diffMatchmakerData(session *model.GameSession) *model.MatchmakerDataDiff {
+	var current model.MatchmakerData
+	if session.MatchmakerData != "" {
+		if err := json.Unmarshal([]byte(session.MatchmakerData), &current); err != nil {
+			state.logger().Warnf("Failed to parse matchmaker data of game session %s: %s", session.GameSessionID, err)
+			return nil
+		}
+	}
+	state.matchmakerDataMtx.Lock()
+	defer state.matchmakerDataMtx.Unlock()
+	previous := &model.MatchmakerData{}
+	if state.matchmakerData != nil && state.matchmakerDataSessionID == session.GameSessionID {
+		previous = state.matchmakerData
+	}
+	diff := model.DiffMatchmakerData(previous, &current)
+	state.matchmakerData = &current
+	state.matchmakerDataSessionID = session.GameSessionID
+	return &diff
+}
+
+func (state *gameLiftServerState) recordPlayerLatency(playerID, region string, latency time.Duration) error {
+	return state.latency.record(playerID, region, latency)
+}
+
+func (state *gameLiftServerState) applyObservedLatencies(players []model.Player) []model.Player {
+	return state.latency.apply(players)
+}
+
+// getMatchmakerData - returns the last matchmaker data parsed for the current game session.
+func (state *gameLiftServerState) getMatchmakerData() (model.MatchmakerData, error) {
+	state.matchmakerDataMtx.Lock()
+	defer state.matchmakerDataMtx.Unlock()
+	if state.matchmakerData == nil || state.matchmakerDataSessionID != state.gameSessionID {
+		return model.MatchmakerData{}, common.NewGameLiftError(common.GamesessionIDNotSet, "", "")
 	}
+	return *state.matchmakerData, nil
 }
 
 func (state *gameLiftServerState) setMetricsFactory(metricsFactory metrics.IFactory) {
+	state.metricsFactoryMtx.Lock()
+	defer state.metricsFactoryMtx.Unlock()
 	state.metricsFactory = metricsFactory
 }
 
+// getMetricsFactory - returns the metrics factory, or nil if none is set or the state was destroyed.
+func (state *gameLiftServerState) getMetricsFactory() metrics.IFactory {
+	state.metricsFactoryMtx.RLock()
+	defer state.metricsFactoryMtx.RUnlock()
+	return state.metricsFactory
+}
+
 // OnTerminateProcess - handler for message.TerminateProcessMessage (already started in a separate goroutine).
 func (state *gameLiftServerState) OnTerminateProcess(terminationTime int64) {
 	// terminationTime is milliseconds that have elapsed since Unix epoch time begins (00:00:00 UTC Jan 1 1970).
 	state.terminationTime = terminationTime / 1000
-	lg.Debugf("ServerState got the terminateProcess signal. termination time : %d", state.terminationTime)
-	if state.metricsFactory != nil {
-		state.metricsFactory.OnProcessTermination()
-	}
-	if state.parameters != nil && state.parameters.OnProcessTerminate != nil {
+	state.logger().Debugf("ServerState got the terminateProcess signal. termination time : %d", state.terminationTime)
+	state.emitEvent(model.Event{
+		Type:            model.EventTerminateProcessReceived,
+		GameSessionID:   state.gameSessionID,
+		TerminationTime: time.UnixMilli(terminationTime),
+	})
+	state.terminate(triggerOnTerminateProcess)
+}
+
+// terminate - ends the server process as configured in ProcessParameters: drains the game session,
+// calls OnProcessTerminate, or calls ProcessEnding() and Destroy().
+func (state *gameLiftServerState) terminate(trigger string) {
+	if err := state.setProcessState(model.ProcessTerminating, trigger); err != nil {
+		state.logger().Warnf("Unexpected process termination by %s: %s", trigger, err)
+	}
+	if factory := state.getMetricsFactory(); factory != nil {
+		factory.OnProcessTermination()
+	}
+	switch {
+	case state.parameters != nil && state.parameters.Drain != nil:
+		state.logger().Debugf("Drain is enabled. Draining the game session before calling ProcessEnding() and Destroy()")
+		state.drainProcess(state.parameters.Drain)
+	case state.parameters != nil && state.parameters.OnProcessTerminate != nil:
 		state.parameters.OnProcessTerminate()
+	default:
+		state.logger().Debugf("OnProcessTerminate handler is not defined. Calling ProcessEnding() and Destroy()")
+		state.endProcess(nil)
+	}
+}
+
+// endProcess - calls ProcessEnding() and Destroy(), then passes their combined error to onComplete.
+// If onComplete is nil, exits the process with a status code reflecting the result.
+func (state *gameLiftServerState) endProcess(onComplete func(error)) {
+	processEndingErr := state.processEnding(context.Background())
+	destroyErr := state.destroy()
+	if processEndingErr != nil {
+		state.logger().Errorf("ProcessEnding failed: %s", processEndingErr)
+	}
+	if destroyErr != nil {
+		state.logger().Errorf("Destroy failed: %s", destroyErr)
+	}
+	if onComplete != nil {
+		onComplete(errors.Join(processEndingErr, destroyErr))
+		return
+	}
+	if processEndingErr == nil && destroyErr == nil {
+		exitFunc(0)
 	} else {
-		lg.Debugf("OnProcessTerminate handler is not defined. Calling ProcessEnding() and Destroy()")
-		processEndingErr := state.processEnding()
-		destroyErr := state.destroy()
-		if processEndingErr == nil && destroyErr == nil {
-			exitFunc(0)
-		} else {
-			if processEndingErr != nil {
-				lg.Errorf("ProcessEnding failed: %s", processEndingErr)
-			}
-			if destroyErr != nil {
-				lg.Errorf("Destroy failed: %s", destroyErr)
-			}
-			exitFunc(-1)
-		}
+		exitFunc(-1)
 	}
 }
 
@@ -611,8 +918,25 @@ func (state *gameLiftServerState) OnRefreshConnection(refreshConnectionEndpoint,
 		nil,
 	)
 	if err != nil {
-		lg.Errorf("Failed to refresh websocket connection. The sever SDK will try again each minute "+
+		state.logger().Errorf("Failed to refresh websocket connection. The sever SDK will try again each minute "+
 			"until the refresh succeeds, or the websocket is forcibly closed: %s", err)
+		return
+	}
+	state.emitEvent(model.Event{Type: model.EventConnectionRefreshed})
+}
+
+// OnRequestTimeout - handler called when no response to a request was received within its timeout.
+func (state *gameLiftServerState) OnRequestTimeout(requestID string) {
+	state.emitEvent(model.Event{Type: model.EventRequestTimedOut, RequestID: requestID})
+}
+
+// emitEvent - passes the event to the subscribers, see Subscribe.
+func (state *gameLiftServerState) emitEvent(event model.Event) {
+	if state.events == nil {
+		return
+	}
+	if dropped := state.events.emit(event); dropped > 0 {
+		state.logger().Debugf("Event %s dropped by %d slow subscribers", event.Type.String(), dropped)
 	}
 }
 
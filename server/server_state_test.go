@@ -166,7 +166,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.ActivateServerProcessRequest{
+		HandleRequest(gomock.Any(), ignoreRequestID(request.ActivateServerProcessRequest{
 			Message: message.Message{
 				RequestID: "cbb9ba51-1351-415a-9c52-380347d099f7",
 				Action:    message.ActivateServerProcess,
@@ -180,7 +180,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
 		MinTimes(1)
 
 	const (
@@ -195,7 +195,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
 		Times(1)
 
 	manager.
@@ -209,7 +209,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = state.processReady(processParams)
+	err = state.processReady(context.Background(), processParams)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -224,7 +224,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenPassed(t *testing.T) {
 	t.Logf("Tests are running, please wait")
 	time.Sleep(state.healthCheckInterval)
 
-	err = state.processEnding()
+	err = state.processEnding(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -290,7 +290,7 @@ func TestGameLiftServerStateProcessReady_WithActivateServerProcessErrorResponse_
 	// mocking to return an error in response when ActivateServerProcess() request is sent via websocket
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.ActivateServerProcessRequest{
+		HandleRequest(gomock.Any(), ignoreRequestID(request.ActivateServerProcessRequest{
 			Message: message.Message{
 				RequestID: "cbb9ba51-1351-415a-9c52-380347d099f7",
 				Action:    message.ActivateServerProcess,
@@ -315,7 +315,7 @@ func TestGameLiftServerStateProcessReady_WithActivateServerProcessErrorResponse_
 	}
 
 	// WHEN
-	err = state.processReady(processParams)
+	err = state.processReady(context.Background(), processParams)
 
 	// THEN
 	// err should NOT be nil as ProcessReady() should fail
@@ -376,7 +376,7 @@ func TestGameLiftServerStateOnTerminateProcess_WithNilOnProcessTerminateHandler_
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
 		Times(1)
 
 	manager.
@@ -453,7 +453,7 @@ func TestGameLiftServerStateOnTerminateProcess_WithNilOnProcessTerminateHandler_
 	// mocking to return an error in response when TerminateServerProcess() request is sent via websocket
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
 		Times(1).
 		Return(expectedError)
 
@@ -531,7 +531,7 @@ func TestGameLiftServerStateOnTerminateProcess_WithNilOnProcessTerminateHandler_
 	// mocking to return an error in response when TerminateServerProcess() request is sent via websocket
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
 		Times(1)
 
 	manager.
@@ -607,7 +607,7 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.ActivateServerProcessRequest{
+		HandleRequest(gomock.Any(), ignoreRequestID(request.ActivateServerProcessRequest{
 			Message: message.Message{
 				RequestID: "cbb9ba51-1351-415a-9c52-380347d099f7",
 				Action:    message.ActivateServerProcess,
@@ -621,7 +621,7 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
 		MinTimes(1)
 
 	const (
@@ -636,7 +636,7 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
 		Times(1)
 
 	manager.
@@ -650,7 +650,7 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 		t.Fatal(err)
 	}
 
-	err = state.processReady(processParams)
+	err = state.processReady(context.Background(), processParams)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -665,7 +665,7 @@ func TestGameLiftServerStateLifecycle_AwsCredentialsAndRegionPassed(t *testing.T
 	t.Logf("Tests are running, please wait")
 	time.Sleep(state.healthCheckInterval)
 
-	err = state.processEnding()
+	err = state.processEnding(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -772,7 +772,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionAndTool
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.ActivateServerProcessRequest{
+		HandleRequest(gomock.Any(), ignoreRequestID(request.ActivateServerProcessRequest{
 			Message: message.Message{
 				RequestID: "cbb9ba51-1351-415a-9c52-380347d099f7",
 				Action:    message.ActivateServerProcess,
@@ -788,7 +788,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionAndTool
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewHeartbeatServerProcess(true)), gomock.Any(), 20*time.Second).
 		MinTimes(1)
 
 	const (
@@ -803,7 +803,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionAndTool
 
 	manager.
 		EXPECT().
-		HandleRequest(ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
+		HandleRequest(gomock.Any(), ignoreRequestID(request.NewTerminateServerProcess()), nil, 20*time.Second).
 		Times(1)
 
 	manager.
@@ -817,7 +817,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionAndTool
 		t.Fatal(err)
 	}
 
-	err = state.processReady(processParams)
+	err = state.processReady(context.Background(), processParams)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -834,7 +834,7 @@ func TestGameLiftServerStateLifecycle_AuthTokenAndAwsCredentialsAndRegionAndTool
 
 	state.OnUpdateGameSession(&gameSession, nil, "backfillTicketId")
 
-	err = state.processEnding()
+	err = state.processEnding(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1063,3 +1063,64 @@ func TestDestroy_SetsMetricsFactoryToNil(t *testing.T) {
 		t.Error("Expected metricsFactory to be nil after destroy()")
 	}
 }
+
+func TestGameLiftServerState_OnUpdateGameSession_PassMatchmakerDataDiff(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	updates := make(chan model.UpdateGameSession, 1)
+	state := gameLiftServerState{parameters: &ProcessParameters{
+		OnUpdateGameSession: func(update model.UpdateGameSession) { updates <- update },
+	}}
+	state.isReadyProcess.Store(true)
+	if err := state.setProcessState(model.ProcessReady, triggerProcessReady); err != nil {
+		t.Fatal(err)
+	}
+	state.OnStartGameSession(&model.GameSession{
+		GameSessionID:  "test-game-session-id",
+		MatchmakerData: `{"matchId":"match-1","teams":[{"name":"red","players":[{"playerId":"player-1"}]}]}`,
+	})
+
+	reason := model.MatchmakingDataUpdated
+
+	// WHEN
+	state.OnUpdateGameSession(&model.GameSession{
+		GameSessionID: "test-game-session-id",
+		MatchmakerData: `{"matchId":"match-1","autoBackfillTicketId":"ticket-1",` +
+			`"teams":[{"name":"red","players":[{"playerId":"player-1"},{"playerId":"player-2"}]}]}`,
+	}, &reason, "ticket-1")
+
+	// THEN
+	update := <-updates
+	if update.MatchmakerDataDiff == nil {
+		t.Fatal("Expected a matchmaker data diff")
+	}
+	added := update.MatchmakerDataDiff.AddedPlayers()
+	common.AssertEqual(t, 1, len(added))
+	common.AssertEqual(t, "player-2", added[0].PlayerID)
+	common.AssertEqual(t, 0, len(update.MatchmakerDataDiff.PlayersRemoved))
+	common.AssertEqual(t, true, update.MatchmakerDataDiff.AutoBackfillTicketChanged)
+	data, err := state.getMatchmakerData()
+	common.AssertEqual(t, nil, err)
+	common.AssertEqual(t, 2, len(data.Players))
+}
+
+func TestGameLiftServerState_OnUpdateGameSession_InvalidMatchmakerData(t *testing.T) {
+	// GIVEN
+	setupNewMockIGameLiftManager(t)
+	updates := make(chan model.UpdateGameSession, 1)
+	state := gameLiftServerState{parameters: &ProcessParameters{
+		OnUpdateGameSession: func(update model.UpdateGameSession) { updates <- update },
+	}}
+	state.isReadyProcess.Store(true)
+	reason := model.MatchmakingDataUpdated
+
+	// WHEN
+	state.OnUpdateGameSession(&model.GameSession{
+		GameSessionID:  "test-game-session-id",
+		MatchmakerData: "{invalid",
+	}, &reason, "")
+
+	// THEN
+	update := <-updates
+	common.AssertEqual(t, (*model.MatchmakerDataDiff)(nil), update.MatchmakerDataDiff)
+}
@@ -0,0 +1,380 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package servertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/result"
+)
+
+// requestError - a failed request, answered with the status code and message.
+type requestError struct {
+	statusCode int
+	message    string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...any) *requestError {
+	return &requestError{statusCode: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...any) *requestError {
+	return &requestError{statusCode: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+// handleRequest - applies the request to the simulator state and returns the response to send back.
+func (s *Service) handleRequest(processID string, data []byte) any {
+	var msg message.Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return errorResponse(msg, badRequest("invalid request: %s", err))
+	}
+	s.logf("Process %s sent %s", processID, string(msg.Action))
+
+	var res any
+	var err *requestError
+	switch msg.Action {
+	case message.ActivateServerProcess:
+		err = decodeAndHandle(data, func(req *request.ActivateServerProcessRequest) *requestError {
+			return s.activateServerProcess(processID, req)
+		})
+	case message.HeartbeatServerProcess:
+		err = decodeAndHandle(data, func(req *request.HeartbeatServerProcessRequest) *requestError {
+			return s.heartbeatServerProcess(processID, req)
+		})
+	case message.TerminateServerProcess:
+		err = s.terminateServerProcess(processID)
+	case message.ActivateGameSession:
+		err = decodeAndHandle(data, func(req *request.ActivateGameSessionRequest) *requestError {
+			return s.activateGameSession(processID, req)
+		})
+	case message.UpdatePlayerSessionCreationPolicy:
+		err = decodeAndHandle(data, func(req *request.UpdatePlayerSessionCreationPolicyRequest) *requestError {
+			return s.updatePlayerSessionCreationPolicy(processID, req)
+		})
+	case message.AcceptPlayerSession:
+		err = decodeAndHandle(data, func(req *request.AcceptPlayerSessionRequest) *requestError {
+			return s.acceptPlayerSession(processID, req)
+		})
+	case message.RemovePlayerSession:
+		err = decodeAndHandle(data, func(req *request.RemovePlayerSessionRequest) *requestError {
+			return s.removePlayerSession(processID, req)
+		})
+	case message.DescribePlayerSessions:
+		var describeResult result.DescribePlayerSessionsResult
+		err = decodeAndHandle(data, func(req *request.DescribePlayerSessionsRequest) *requestError {
+			var reqErr *requestError
+			describeResult, reqErr = s.describePlayerSessions(req)
+			return reqErr
+		})
+		res = describeResult
+	case message.StartMatchBackfill:
+		var backfillResult result.StartMatchBackfillResult
+		err = decodeAndHandle(data, func(req *request.StartMatchBackfillRequest) *requestError {
+			var reqErr *requestError
+			backfillResult, reqErr = s.startMatchBackfill(req)
+			return reqErr
+		})
+		res = backfillResult
+	case message.StopMatchBackfill:
+		err = decodeAndHandle(data, func(req *request.StopMatchBackfillRequest) *requestError {
+			return s.stopMatchBackfill(req)
+		})
+	case message.GetComputeCertificate:
+		res = result.GetComputeCertificateResult{
+			CertificatePath: "/local/game/servertest/certificate.pem",
+			ComputeName:     s.hostID(processID),
+		}
+	case message.GetFleetRoleCredentials:
+		res = s.getFleetRoleCredentials(data)
+	default:
+		err = badRequest("unsupported action %s", string(msg.Action))
+	}
+	if err != nil {
+		s.logf("Request %s of process %s failed: %s", msg.RequestID, processID, err.message)
+		return errorResponse(msg, err)
+	}
+	ok := message.ResponseMessage{Message: msg, StatusCode: http.StatusOK}
+	// The result fields are flattened next to the status, as the service does.
+	switch r := res.(type) {
+	case result.DescribePlayerSessionsResult:
+		return struct {
+			message.ResponseMessage
+			result.DescribePlayerSessionsResult
+		}{ok, r}
+	case result.StartMatchBackfillResult:
+		return struct {
+			message.ResponseMessage
+			result.StartMatchBackfillResult
+		}{ok, r}
+	case result.GetComputeCertificateResult:
+		return struct {
+			message.ResponseMessage
+			result.GetComputeCertificateResult
+		}{ok, r}
+	case result.GetFleetRoleCredentialsResult:
+		return struct {
+			message.ResponseMessage
+			result.GetFleetRoleCredentialsResult
+		}{ok, r}
+	}
+	return ok
+}
+
+func errorResponse(msg message.Message, err *requestError) message.ResponseMessage {
+	return message.ResponseMessage{Message: msg, StatusCode: err.statusCode, ErrorMessage: err.message}
+}
+
+// decodeAndHandle - decodes the request and passes it to the handler.
+func decodeAndHandle[T any](data []byte, handler func(*T) *requestError) *requestError {
+	req := new(T)
+	if err := json.Unmarshal(data, req); err != nil {
+		return badRequest("invalid request: %s", err)
+	}
+	return handler(req)
+}
+
+func (s *Service) activateServerProcess(processID string, req *request.ActivateServerProcessRequest) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	p := s.processes[processID]
+	if p.info.Ended {
+		return badRequest("process %s has already ended", processID)
+	}
+	p.info.Active = true
+	p.info.Port = req.Port
+	p.info.LogPaths = append([]string(nil), req.LogPaths...)
+	s.notifyLocked()
+	return nil
+}
+
+func (s *Service) heartbeatServerProcess(processID string, req *request.HeartbeatServerProcessRequest) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	p := s.processes[processID]
+	p.info.Healthy = req.HealthStatus
+	p.info.LastHeartbeat = time.Now()
+	s.notifyLocked()
+	return nil
+}
+
+func (s *Service) terminateServerProcess(processID string) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	p := s.processes[processID]
+	p.info.Active = false
+	p.info.Ended = true
+	if gs, ok := s.gameSessions[p.info.GameSessionID]; ok {
+		gs.active = false
+		now := time.Now().UnixMilli()
+		for _, ps := range gs.playerSessions {
+			if status := ps.status(); status == statusReserved || status == statusActive {
+				ps.session = ps.session.WithStatus(model.PlayerCompleted)
+				ps.session.TerminationTime = now
+			}
+		}
+	}
+	s.notifyLocked()
+	return nil
+}
+
+// hostedGameSessionLocked - returns the game session hosted by the process, if it matches gameSessionID.
+func (s *Service) hostedGameSessionLocked(processID, gameSessionID string) (*gameSession, *requestError) {
+	gs, ok := s.gameSessions[gameSessionID]
+	if !ok || gs.processID != processID {
+		return nil, notFound("game session %s is not hosted by process %s", gameSessionID, processID)
+	}
+	return gs, nil
+}
+
+func (s *Service) activateGameSession(processID string, req *request.ActivateGameSessionRequest) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	gs, err := s.hostedGameSessionLocked(processID, req.GameSessionID)
+	if err != nil {
+		return err
+	}
+	if gs.active {
+		return badRequest("game session %s is already active", req.GameSessionID)
+	}
+	gs.active = true
+	s.notifyLocked()
+	return nil
+}
+
+func (s *Service) updatePlayerSessionCreationPolicy(
+	processID string,
+	req *request.UpdatePlayerSessionCreationPolicyRequest,
+) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	gs, err := s.hostedGameSessionLocked(processID, req.GameSessionID)
+	if err != nil {
+		return err
+	}
+	if req.PlayerSessionPolicy == nil || *req.PlayerSessionPolicy == model.NotSet {
+		return badRequest("player session creation policy is required")
+	}
+	gs.policy = *req.PlayerSessionPolicy
+	return nil
+}
+
+func (s *Service) acceptPlayerSession(processID string, req *request.AcceptPlayerSessionRequest) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	gs, err := s.hostedGameSessionLocked(processID, req.GameSessionID)
+	if err != nil {
+		return err
+	}
+	s.expireReservationsLocked(gs)
+	ps := gs.findPlayerSession(req.PlayerSessionID)
+	if ps == nil {
+		return notFound("player session %s does not exist", req.PlayerSessionID)
+	}
+	if status := ps.status(); status != statusReserved {
+		return badRequest("player session %s is %s, only RESERVED player sessions can be accepted",
+			req.PlayerSessionID, status)
+	}
+	ps.session = ps.session.WithStatus(model.PlayerActive)
+	return nil
+}
+
+func (s *Service) removePlayerSession(processID string, req *request.RemovePlayerSessionRequest) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	gs, err := s.hostedGameSessionLocked(processID, req.GameSessionID)
+	if err != nil {
+		return err
+	}
+	ps := gs.findPlayerSession(req.PlayerSessionID)
+	if ps == nil {
+		return notFound("player session %s does not exist", req.PlayerSessionID)
+	}
+	if status := ps.status(); status != statusReserved && status != statusActive {
+		return badRequest("player session %s is already %s", req.PlayerSessionID, status)
+	}
+	ps.session = ps.session.WithStatus(model.PlayerCompleted)
+	ps.session.TerminationTime = time.Now().UnixMilli()
+	return nil
+}
+
+func (s *Service) describePlayerSessions(
+	req *request.DescribePlayerSessionsRequest,
+) (result.DescribePlayerSessionsResult, *requestError) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var matches []model.PlayerSession
+	// The game sessions are sorted by ID and their player sessions kept in creation order,
+	// so that NextToken pages through the same order on every call.
+	for _, gameSessionID := range slices.Sorted(maps.Keys(s.gameSessions)) {
+		gs := s.gameSessions[gameSessionID]
+		if req.GameSessionID != "" && gs.session.GameSessionID != req.GameSessionID {
+			continue
+		}
+		s.expireReservationsLocked(gs)
+		for _, ps := range gs.playerSessions {
+			switch {
+			case req.PlayerID != "" && ps.session.PlayerID != req.PlayerID:
+			case req.PlayerSessionID != "" && ps.session.PlayerSessionID != req.PlayerSessionID:
+			case req.PlayerSessionStatusFilter != "" && ps.status() != req.PlayerSessionStatusFilter:
+			default:
+				matches = append(matches, ps.session)
+			}
+		}
+	}
+	start := 0
+	if req.NextToken != "" {
+		var err error
+		if start, err = strconv.Atoi(req.NextToken); err != nil || start < 0 || start > len(matches) {
+			return result.DescribePlayerSessionsResult{}, badRequest("invalid NextToken %s", req.NextToken)
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDescribeLimit
+	}
+	end := min(start+limit, len(matches))
+	res := result.DescribePlayerSessionsResult{PlayerSessions: matches[start:end]}
+	if end < len(matches) {
+		res.NextToken = strconv.Itoa(end)
+	}
+	return res, nil
+}
+
+func (s *Service) startMatchBackfill(req *request.StartMatchBackfillRequest) (result.StartMatchBackfillResult, *requestError) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.gameSessions[req.GameSessionArn]; !ok {
+		return result.StartMatchBackfillResult{}, notFound("game session %s does not exist", req.GameSessionArn)
+	}
+	ticketID := req.TicketID
+	if ticketID == "" {
+		ticketID = uuid.New().String()
+	}
+	if t, ok := s.tickets[ticketID]; ok && !t.Stopped {
+		return result.StartMatchBackfillResult{}, badRequest("ticket %s is already in progress", ticketID)
+	}
+	s.tickets[ticketID] = &BackfillTicket{
+		TicketID:                    ticketID,
+		GameSessionArn:              req.GameSessionArn,
+		MatchmakingConfigurationArn: req.MatchmakingConfigurationArn,
+		Players:                     req.Players,
+	}
+	return result.StartMatchBackfillResult{TicketID: ticketID}, nil
+}
+
+func (s *Service) stopMatchBackfill(req *request.StopMatchBackfillRequest) *requestError {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	t, ok := s.tickets[req.TicketID]
+	if !ok || t.GameSessionArn != req.GameSessionArn {
+		return notFound("ticket %s does not exist", req.TicketID)
+	}
+	t.Stopped = true
+	return nil
+}
+
+func (s *Service) getFleetRoleCredentials(data []byte) result.GetFleetRoleCredentialsResult {
+	var req request.GetFleetRoleCredentialsRequest
+	_ = json.Unmarshal(data, &req)
+	return result.GetFleetRoleCredentialsResult{
+		AssumedRoleUserArn: req.RoleArn + "/" + req.RoleSessionName,
+		AssumedRoleID:      "AROASERVERTEST:" + req.RoleSessionName,
+		AccessKeyID:        "ASIASERVERTEST",
+		SecretAccessKey:    "servertest-secret-access-key",
+		SessionToken:       "servertest-session-token",
+		Expiration:         time.Now().Add(time.Hour).UnixMilli(),
+	}
+}
+
+func (s *Service) hostID(processID string) string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.processes[processID].info.HostID
+}
+
+func (gs *gameSession) findPlayerSession(playerSessionID string) *playerSession {
+	for _, ps := range gs.playerSessions {
+		if ps.session.PlayerSessionID == playerSessionID {
+			return ps
+		}
+	}
+	return nil
+}
@@ -0,0 +1,562 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package servertest provides an in-process Amazon GameLift Servers simulator.
+//
+// The simulator is a websocket server speaking the same JSON protocol as the service, so the server SDK
+// can run end-to-end against it, from InitSDK to ProcessEnding, without a fleet:
+//
+//	service, err := servertest.NewService()
+//	if err != nil {
+//		return err
+//	}
+//	defer service.Close()
+//
+//	client, err := server.NewClient(service.ServerParameters("process-1"))
+//	...
+//	err = client.ProcessReady(processParameters)
+//	...
+//	session, err := service.CreateGameSession("process-1", model.GameSession{MaximumPlayerSessionCount: 2})
+package servertest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/message"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/log"
+)
+
+// Default values used by the simulator.
+const (
+	DefaultHostID    = "servertest-host"
+	DefaultFleetID   = "fleet-servertest"
+	DefaultAuthToken = "servertest-auth-token"
+	// DefaultRegion - region used in the ARNs of simulated game sessions.
+	DefaultRegion = "us-west-2"
+	// DefaultReservationTimeout - time a RESERVED player session waits to be accepted before it times out.
+	DefaultReservationTimeout = 60 * time.Second
+	// defaultDescribeLimit - page size of DescribePlayerSessions when the request does not set a limit.
+	defaultDescribeLimit = 50
+)
+
+// Player session statuses, as reported by DescribePlayerSessions.
+const (
+	statusReserved = "RESERVED"
+	statusActive   = "ACTIVE"
+)
+
+// ErrProcessNotConnected - returned when a message is pushed to a process that is not connected to the simulator.
+var ErrProcessNotConnected = errors.New("servertest: process is not connected")
+
+// Option - configures a Service created by NewService or Start.
+type Option func(*Service)
+
+// WithLogger - sets the logger used to report connections and received requests.
+func WithLogger(l log.ILogger) Option {
+	return func(s *Service) {
+		s.lg = l
+	}
+}
+
+// WithReservationTimeout - sets the time a RESERVED player session waits to be accepted before it times out.
+func WithReservationTimeout(timeout time.Duration) Option {
+	return func(s *Service) {
+		s.reservationTimeout = timeout
+	}
+}
+
+// Service - an in-process Amazon GameLift Servers simulator, see the package documentation.
+type Service struct {
+	// URL - websocket URL of the simulator, for example ws://127.0.0.1:41234.
+	URL string
+
+	lg                 log.ILogger
+	reservationTimeout time.Duration
+	listener           net.Listener
+	httpServer         *http.Server
+	upgrader           websocket.Upgrader
+
+	mtx          sync.Mutex
+	changed      chan struct{}
+	processes    map[string]*process
+	gameSessions map[string]*gameSession
+	tickets      map[string]*BackfillTicket
+}
+
+// ProcessInfo - state of a server process as seen by the simulator.
+type ProcessInfo struct {
+	ProcessID string
+	HostID    string
+	FleetID   string
+	// Connected - the process has an open websocket connection.
+	Connected bool
+	// Connections - number of websocket connections the process has opened, including refreshed ones.
+	Connections int
+	// Active - ProcessReady was called and ProcessEnding was not called yet.
+	Active bool
+	// Ended - ProcessEnding was called.
+	Ended    bool
+	Port     int
+	LogPaths []string
+	// Healthy - the health status reported by the last heartbeat.
+	Healthy       bool
+	LastHeartbeat time.Time
+	// GameSessionID - the game session assigned to the process, if any.
+	GameSessionID string
+}
+
+// BackfillTicket - a match backfill request received by the simulator.
+type BackfillTicket struct {
+	TicketID                    string
+	GameSessionArn              string
+	MatchmakingConfigurationArn string
+	Players                     []model.Player
+	// Stopped - StopMatchBackfill was called for the ticket.
+	Stopped bool
+}
+
+type process struct {
+	info ProcessInfo
+	conn *websocket.Conn
+	// writeMtx - serializes writes, as a websocket connection supports one concurrent writer.
+	writeMtx *sync.Mutex
+}
+
+type gameSession struct {
+	session        model.GameSession
+	processID      string
+	active         bool
+	policy         model.PlayerSessionCreationPolicy
+	playerSessions []*playerSession
+}
+
+type playerSession struct {
+	session    model.PlayerSession
+	reservedAt time.Time
+}
+
+// NewService - starts a simulator listening on a random local port.
+func NewService(opts ...Option) (*Service, error) {
+	return Start("127.0.0.1:0", opts...)
+}
+
+// Start - starts a simulator listening on the specified address, for example "127.0.0.1:8080".
+func Start(addr string, opts ...Option) (*Service, error) {
+	s := &Service{
+		reservationTimeout: DefaultReservationTimeout,
+		changed:            make(chan struct{}),
+		processes:          make(map[string]*process),
+		gameSessions:       make(map[string]*gameSession),
+		tickets:            make(map[string]*BackfillTicket),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s.listener = listener
+	s.URL = "ws://" + listener.Addr().String()
+	s.httpServer = &http.Server{Handler: http.HandlerFunc(s.serveWebsocket), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logf("Simulator stopped: %s", err)
+		}
+	}()
+	return s, nil
+}
+
+// Close - closes all connections and stops the simulator.
+func (s *Service) Close() error {
+	err := s.httpServer.Close()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, p := range s.processes {
+		if p.conn != nil {
+			_ = p.conn.Close()
+			p.conn = nil
+			p.info.Connected = false
+		}
+	}
+	return err
+}
+
+// ServerParameters - returns the server.ServerParameters that connect a server process to the simulator.
+func (s *Service) ServerParameters(processID string) server.ServerParameters {
+	return server.ServerParameters{
+		WebSocketURL: s.URL,
+		ProcessID:    processID,
+		HostID:       DefaultHostID,
+		FleetID:      DefaultFleetID,
+		AuthToken:    DefaultAuthToken,
+	}
+}
+
+// Process - returns the state of the process, and false if the process never connected.
+func (s *Service) Process(processID string) (ProcessInfo, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	p, ok := s.processes[processID]
+	if !ok {
+		return ProcessInfo{}, false
+	}
+	return p.snapshot(), true
+}
+
+// Processes - returns the state of all processes that ever connected.
+func (s *Service) Processes() []ProcessInfo {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	processes := make([]ProcessInfo, 0, len(s.processes))
+	for _, p := range s.processes {
+		processes = append(processes, p.snapshot())
+	}
+	return processes
+}
+
+// WaitForProcess - blocks until the condition holds for the process or ctx is done.
+//
+//	err := service.WaitForProcess(ctx, "process-1", func(p servertest.ProcessInfo) bool { return p.Active })
+func (s *Service) WaitForProcess(ctx context.Context, processID string, condition func(ProcessInfo) bool) error {
+	for {
+		s.mtx.Lock()
+		p, ok := s.processes[processID]
+		done := ok && condition(p.snapshot())
+		changed := s.changed
+		s.mtx.Unlock()
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// GameSession - returns the game session, and false if it does not exist.
+func (s *Service) GameSession(gameSessionID string) (model.GameSession, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	gs, ok := s.gameSessions[gameSessionID]
+	if !ok {
+		return model.GameSession{}, false
+	}
+	session := gs.session
+	if gs.active {
+		session = session.WithStatus(model.GameActive)
+	} else {
+		session = session.WithStatus(model.GameActivating)
+	}
+	return session, true
+}
+
+// BackfillTickets - returns all match backfill requests received by the simulator.
+func (s *Service) BackfillTickets() []BackfillTicket {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	tickets := make([]BackfillTicket, 0, len(s.tickets))
+	for _, t := range s.tickets {
+		tickets = append(tickets, *t)
+	}
+	return tickets
+}
+
+// CreateGameSession - assigns a game session to the process and sends it a CreateGameSession message.
+// GameSessionID, FleetID, IPAddress, Port and DNSName are filled in if empty, and the
+// player session creation policy starts as ACCEPT_ALL.
+func (s *Service) CreateGameSession(processID string, session model.GameSession) (model.GameSession, error) {
+	s.mtx.Lock()
+	p, ok := s.processes[processID]
+	if !ok || !p.info.Active {
+		s.mtx.Unlock()
+		return model.GameSession{}, fmt.Errorf("servertest: process %s is not ready to host a game session", processID)
+	}
+	if p.info.GameSessionID != "" {
+		s.mtx.Unlock()
+		return model.GameSession{}, fmt.Errorf("servertest: process %s already hosts game session %s",
+			processID, p.info.GameSessionID)
+	}
+	if session.FleetID == "" {
+		session.FleetID = p.info.FleetID
+	}
+	if session.GameSessionID == "" {
+		session.GameSessionID = fmt.Sprintf("arn:aws:gamelift:%s::gamesession/%s/gsess-%s",
+			DefaultRegion, session.FleetID, uuid.New().String())
+	}
+	if session.IPAddress == "" {
+		session.IPAddress = "127.0.0.1"
+	}
+	if session.DNSName == "" {
+		session.DNSName = "localhost"
+	}
+	if session.Port == 0 {
+		session.Port = p.info.Port
+	}
+	if _, exists := s.gameSessions[session.GameSessionID]; exists {
+		s.mtx.Unlock()
+		return model.GameSession{}, fmt.Errorf("servertest: game session %s already exists", session.GameSessionID)
+	}
+	s.gameSessions[session.GameSessionID] = &gameSession{
+		session:   session,
+		processID: processID,
+		policy:    model.AcceptAll,
+	}
+	p.info.GameSessionID = session.GameSessionID
+	s.notifyLocked()
+	s.mtx.Unlock()
+
+	msg := message.CreateGameSessionMessage{
+		Message:                   message.NewMessage(message.CreateGameSession),
+		MaximumPlayerSessionCount: session.MaximumPlayerSessionCount,
+		Port:                      session.Port,
+		IPAddress:                 session.IPAddress,
+		GameSessionID:             session.GameSessionID,
+		GameSessionName:           session.Name,
+		GameSessionData:           session.GameSessionData,
+		MatchmakerData:            session.MatchmakerData,
+		DNSName:                   session.DNSName,
+		GameProperties:            session.GameProperties,
+	}
+	return session, s.push(processID, msg)
+}
+
+// UpdateGameSession - sends an UpdateGameSession message for the game session hosted by the process.
+// The stored game session is replaced by update.GameSession, with GameSessionID and FleetID kept if empty.
+func (s *Service) UpdateGameSession(processID string, update model.UpdateGameSession) error {
+	s.mtx.Lock()
+	p, ok := s.processes[processID]
+	if !ok || p.info.GameSessionID == "" {
+		s.mtx.Unlock()
+		return fmt.Errorf("servertest: process %s does not host a game session", processID)
+	}
+	gs := s.gameSessions[p.info.GameSessionID]
+	if update.GameSession.GameSessionID == "" {
+		update.GameSession.GameSessionID = gs.session.GameSessionID
+	}
+	if update.GameSession.FleetID == "" {
+		update.GameSession.FleetID = gs.session.FleetID
+	}
+	gs.session = update.GameSession
+	s.mtx.Unlock()
+
+	return s.push(processID, message.UpdateGameSessionMessage{
+		Message:           message.NewMessage(message.UpdateGameSession),
+		UpdateGameSession: update,
+	})
+}
+
+// RefreshConnection - asks the process to open a new websocket connection to the simulator.
+func (s *Service) RefreshConnection(processID string) error {
+	return s.push(processID, message.RefreshConnectionMessage{
+		Message:                   message.NewMessage(message.RefreshConnection),
+		RefreshConnectionEndpoint: s.URL,
+		AuthToken:                 DefaultAuthToken,
+	})
+}
+
+// TerminateProcess - sends a TerminateProcess message announcing that the process will be shut down
+// at terminationTime.
+func (s *Service) TerminateProcess(processID string, terminationTime time.Time) error {
+	return s.push(processID, message.TerminateProcessMessage{
+		Message:         message.NewMessage(message.TerminateProcess),
+		TerminationTime: terminationTime.UnixMilli(),
+	})
+}
+
+// CreatePlayerSession - reserves a player session in the game session, as the CreatePlayerSession
+// service API does. The game session must be active, accept new players and have a free slot.
+// The player session must be accepted with AcceptPlayerSession before the reservation times out.
+func (s *Service) CreatePlayerSession(gameSessionID, playerID, playerData string) (model.PlayerSession, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	gs, ok := s.gameSessions[gameSessionID]
+	if !ok {
+		return model.PlayerSession{}, fmt.Errorf("servertest: game session %s does not exist", gameSessionID)
+	}
+	if !gs.active {
+		return model.PlayerSession{}, fmt.Errorf("servertest: game session %s is not active", gameSessionID)
+	}
+	if gs.policy == model.DenyAll {
+		return model.PlayerSession{}, fmt.Errorf("servertest: game session %s is not accepting new players", gameSessionID)
+	}
+	s.expireReservationsLocked(gs)
+	occupied := 0
+	for _, ps := range gs.playerSessions {
+		if status := ps.status(); status == statusReserved || status == statusActive {
+			occupied++
+		}
+	}
+	if occupied >= gs.session.MaximumPlayerSessionCount {
+		return model.PlayerSession{}, fmt.Errorf("servertest: game session %s is full", gameSessionID)
+	}
+	now := time.Now()
+	session := model.PlayerSession{
+		PlayerID:        playerID,
+		PlayerSessionID: "psess-" + uuid.New().String(),
+		GameSessionID:   gameSessionID,
+		FleetID:         gs.session.FleetID,
+		PlayerData:      playerData,
+		IPAddress:       gs.session.IPAddress,
+		Port:            gs.session.Port,
+		DNSName:         gs.session.DNSName,
+		CreationTime:    now.UnixMilli(),
+	}.WithStatus(model.PlayerReserved)
+	gs.playerSessions = append(gs.playerSessions, &playerSession{session: session, reservedAt: now})
+	return session, nil
+}
+
+// PlayerSessions - returns the player sessions of the game session.
+func (s *Service) PlayerSessions(gameSessionID string) []model.PlayerSession {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	gs, ok := s.gameSessions[gameSessionID]
+	if !ok {
+		return nil
+	}
+	s.expireReservationsLocked(gs)
+	sessions := make([]model.PlayerSession, 0, len(gs.playerSessions))
+	for _, ps := range gs.playerSessions {
+		sessions = append(sessions, ps.session)
+	}
+	return sessions
+}
+
+// push - sends a service message to the process. Pushed messages carry a request ID, so they are sent with
+// StatusCode 200 for the server SDK to dispatch them to their handler rather than treat them as failed requests.
+func (s *Service) push(processID string, msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	fields["StatusCode"] = http.StatusOK
+
+	s.mtx.Lock()
+	p, ok := s.processes[processID]
+	if !ok || p.conn == nil {
+		s.mtx.Unlock()
+		return ErrProcessNotConnected
+	}
+	conn, writeMtx := p.conn, p.writeMtx
+	s.mtx.Unlock()
+	return writeJSON(conn, writeMtx, fields)
+}
+
+func (s *Service) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	processID := query.Get(common.PidKey)
+	if processID == "" {
+		http.Error(w, "missing "+common.PidKey, http.StatusBadRequest)
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logf("Failed to upgrade connection of process %s: %s", processID, err)
+		return
+	}
+	writeMtx := &sync.Mutex{}
+
+	s.mtx.Lock()
+	p, ok := s.processes[processID]
+	if !ok {
+		p = &process{info: ProcessInfo{ProcessID: processID}}
+		s.processes[processID] = p
+	}
+	p.info.HostID = query.Get(common.ComputeIDKey)
+	p.info.FleetID = query.Get(common.FleetIDKey)
+	p.info.Connected = true
+	p.info.Connections++
+	p.conn = conn
+	p.writeMtx = writeMtx
+	s.notifyLocked()
+	s.mtx.Unlock()
+	s.logf("Process %s connected", processID)
+
+	defer func() {
+		_ = conn.Close()
+		s.mtx.Lock()
+		// A refreshed connection replaces this one, so only the latest connection marks the process as disconnected.
+		if p.conn == conn {
+			p.conn = nil
+			p.info.Connected = false
+			s.notifyLocked()
+		}
+		s.mtx.Unlock()
+		s.logf("Process %s disconnected", processID)
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		response := s.handleRequest(processID, data)
+		if err := writeJSON(conn, writeMtx, response); err != nil {
+			s.logf("Failed to answer process %s: %s", processID, err)
+			return
+		}
+	}
+}
+
+// notifyLocked - wakes up WaitForProcess callers. Must be called with s.mtx held.
+func (s *Service) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Service) logf(format string, args ...any) {
+	if s.lg != nil {
+		s.lg.Debugf(format, args...)
+	}
+}
+
+func (p *process) snapshot() ProcessInfo {
+	info := p.info
+	info.LogPaths = append([]string(nil), p.info.LogPaths...)
+	return info
+}
+
+func (ps *playerSession) status() string {
+	return ps.session.Status.String()
+}
+
+// expireReservationsLocked - times out RESERVED player sessions that were not accepted in time.
+func (s *Service) expireReservationsLocked(gs *gameSession) {
+	for _, ps := range gs.playerSessions {
+		if ps.status() == statusReserved && time.Since(ps.reservedAt) > s.reservationTimeout {
+			ps.session = ps.session.WithStatus(model.PlayerTimedout)
+			ps.session.TerminationTime = time.Now().UnixMilli()
+		}
+	}
+}
+
+func writeJSON(conn *websocket.Conn, writeMtx *sync.Mutex, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	writeMtx.Lock()
+	defer writeMtx.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
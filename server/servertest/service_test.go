@@ -0,0 +1,385 @@
+/*
+ * Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package servertest_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model/request"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server"
+	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/server/servertest"
+)
+
+const (
+	testProcessID = "process-1"
+	testConfigArn = "arn:aws:gamelift:us-west-2:123456789012:matchmakingconfiguration/test-config"
+)
+
+// testLogger - writes log messages to the test output.
+type testLogger struct {
+	t *testing.T
+}
+
+func (l testLogger) Debugf(format string, args ...any) { l.t.Logf("DEBUG "+format, args...) }
+func (l testLogger) Warnf(format string, args ...any)  { l.t.Logf("WARN "+format, args...) }
+func (l testLogger) Errorf(format string, args ...any) { l.t.Logf("ERROR "+format, args...) }
+
+type testServer struct {
+	service        *servertest.Service
+	client         *server.Client
+	gameSessions   chan model.GameSession
+	terminations   chan struct{}
+	updateSessions chan model.UpdateGameSession
+}
+
+func setupTestServer(t *testing.T) *testServer {
+	t.Helper()
+	service, err := servertest.NewService(servertest.WithLogger(testLogger{t}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+
+	client, err := server.NewClient(service.ServerParameters(testProcessID), server.WithLogger(testLogger{t}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Destroy() })
+
+	ts := &testServer{
+		service:        service,
+		client:         client,
+		gameSessions:   make(chan model.GameSession, 1),
+		terminations:   make(chan struct{}, 1),
+		updateSessions: make(chan model.UpdateGameSession, 1),
+	}
+	err = client.ProcessReady(server.ProcessParameters{
+		OnStartGameSession:  func(session model.GameSession) { ts.gameSessions <- session },
+		OnUpdateGameSession: func(update model.UpdateGameSession) { ts.updateSessions <- update },
+		OnProcessTerminate:  func() { ts.terminations <- struct{}{} },
+		OnHealthCheck:       func() bool { return true },
+		Port:                7777,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+// startGameSession - creates a game session on the simulator and activates it from the server process.
+func (ts *testServer) startGameSession(t *testing.T, session model.GameSession) model.GameSession {
+	t.Helper()
+	created, err := ts.service.CreateGameSession(testProcessID, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case received := <-ts.gameSessions:
+		common.AssertEqual(t, created.GameSessionID, received.GameSessionID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for OnStartGameSession")
+	}
+	if err := ts.client.ActivateGameSession(); err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestService_ProcessReady(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+
+	// WHEN
+	process, ok := ts.service.Process(testProcessID)
+
+	// THEN
+	common.AssertEqual(t, true, ok)
+	common.AssertEqual(t, true, process.Connected)
+	common.AssertEqual(t, true, process.Active)
+	common.AssertEqual(t, 7777, process.Port)
+	common.AssertEqual(t, servertest.DefaultFleetID, process.FleetID)
+}
+
+func TestService_GameSessionLifecycle(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+	session := ts.startGameSession(t, model.GameSession{
+		MaximumPlayerSessionCount: 1,
+		GameProperties:            map[string]string{"mode": "duel"},
+	})
+
+	// WHEN
+	playerSession, err := ts.service.CreatePlayerSession(session.GameSessionID, "player-1", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acceptErr := ts.client.AcceptPlayerSession(playerSession.PlayerSessionID)
+	secondAcceptErr := ts.client.AcceptPlayerSession(playerSession.PlayerSessionID)
+	_, fullErr := ts.service.CreatePlayerSession(session.GameSessionID, "player-2", "")
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = session.GameSessionID
+	described, describeErr := ts.client.DescribePlayerSessions(req)
+
+	// THEN
+	stored, ok := ts.service.GameSession(session.GameSessionID)
+	common.AssertEqual(t, true, ok)
+	common.AssertEqual(t, "ACTIVE", stored.Status.String())
+	common.AssertEqual(t, nil, acceptErr)
+	if secondAcceptErr == nil {
+		t.Fatal("Expected accepting an ACTIVE player session to fail")
+	}
+	if fullErr == nil {
+		t.Fatal("Expected a full game session to reject a new player session")
+	}
+	common.AssertEqual(t, nil, describeErr)
+	common.AssertEqual(t, 1, len(described.PlayerSessions))
+	common.AssertEqual(t, "player-1", described.PlayerSessions[0].PlayerID)
+	common.AssertEqual(t, "ACTIVE", described.PlayerSessions[0].Status.String())
+}
+
+func TestService_DescribePlayerSessions_Paginate(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+	session := ts.startGameSession(t, model.GameSession{MaximumPlayerSessionCount: 3})
+	for _, playerID := range []string{"player-1", "player-2", "player-3"} {
+		if _, err := ts.service.CreatePlayerSession(session.GameSessionID, playerID, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// WHEN
+	req := request.NewDescribePlayerSessions()
+	req.GameSessionID = session.GameSessionID
+	req.Limit = 2
+	first, err := ts.client.DescribePlayerSessions(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.NextToken = first.NextToken
+	second, err := ts.client.DescribePlayerSessions(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN
+	common.AssertEqual(t, 2, len(first.PlayerSessions))
+	if first.NextToken == "" {
+		t.Fatal("Expected a next token for the first page")
+	}
+	common.AssertEqual(t, 1, len(second.PlayerSessions))
+	common.AssertEqual(t, "", second.NextToken)
+}
+
+func TestService_DescribePlayerSessions_PaginateAcrossGameSessionsInStableOrder(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+	first := ts.startGameSession(t, model.GameSession{MaximumPlayerSessionCount: 1})
+	client, err := server.NewClient(ts.service.ServerParameters("process-2"), server.WithLogger(testLogger{t}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Destroy()
+	gameSessions := make(chan model.GameSession, 1)
+	err = client.ProcessReady(server.ProcessParameters{
+		OnStartGameSession: func(session model.GameSession) { gameSessions <- session },
+		OnProcessTerminate: func() {},
+		OnHealthCheck:      func() bool { return true },
+		Port:               7778,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ts.service.CreateGameSession("process-2", model.GameSession{MaximumPlayerSessionCount: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-gameSessions
+	if err := client.ActivateGameSession(); err != nil {
+		t.Fatal(err)
+	}
+	for _, gameSessionID := range []string{first.GameSessionID, second.GameSessionID} {
+		if _, err := ts.service.CreatePlayerSession(gameSessionID, "player-1", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// WHEN
+	var paged []string
+	for range 5 {
+		req := request.NewDescribePlayerSessions()
+		req.PlayerID = "player-1"
+		req.Limit = 1
+		for {
+			page, err := ts.client.DescribePlayerSessions(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ps := range page.PlayerSessions {
+				paged = append(paged, ps.GameSessionID)
+			}
+			if page.NextToken == "" {
+				break
+			}
+			req.NextToken = page.NextToken
+		}
+	}
+
+	// THEN
+	expected := slices.Sorted(slices.Values([]string{first.GameSessionID, second.GameSessionID}))
+	common.AssertEqual(t, 5*len(expected), len(paged))
+	for i, gameSessionID := range paged {
+		common.AssertEqual(t, expected[i%len(expected)], gameSessionID)
+	}
+}
+
+func TestService_ReservationTimeout(t *testing.T) {
+	// GIVEN
+	service, err := servertest.NewService(servertest.WithReservationTimeout(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer service.Close()
+	client, err := server.NewClient(service.ServerParameters(testProcessID), server.WithLogger(testLogger{t}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Destroy()
+	gameSessions := make(chan model.GameSession, 1)
+	err = client.ProcessReady(server.ProcessParameters{
+		OnStartGameSession: func(session model.GameSession) { gameSessions <- session },
+		OnProcessTerminate: func() {},
+		OnHealthCheck:      func() bool { return true },
+		Port:               7777,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := service.CreateGameSession(testProcessID, model.GameSession{MaximumPlayerSessionCount: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-gameSessions
+	if err := client.ActivateGameSession(); err != nil {
+		t.Fatal(err)
+	}
+	playerSession, err := service.CreatePlayerSession(session.GameSessionID, "player-1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// WHEN
+	err = client.AcceptPlayerSession(playerSession.PlayerSessionID)
+
+	// THEN
+	if err == nil {
+		t.Fatal("Expected accepting a timed out player session to fail")
+	}
+	common.AssertEqual(t, "TIMEDOUT", service.PlayerSessions(session.GameSessionID)[0].Status.String())
+}
+
+func TestService_MatchBackfill(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+	session := ts.startGameSession(t, model.GameSession{MaximumPlayerSessionCount: 4})
+
+	// WHEN
+	startReq := request.NewStartMatchBackfill(session.GameSessionID, testConfigArn,
+		[]model.Player{{PlayerID: "player-1", Team: "red"}})
+	startReq.TicketID = "ticket-1"
+	started, err := ts.client.StartMatchBackfill(startReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopReq := request.NewStopMatchBackfill()
+	stopReq.GameSessionArn = session.GameSessionID
+	stopReq.MatchmakingConfigurationArn = testConfigArn
+	stopReq.TicketID = started.TicketID
+	stopErr := ts.client.StopMatchBackfill(stopReq)
+
+	// THEN
+	common.AssertEqual(t, "ticket-1", started.TicketID)
+	common.AssertEqual(t, nil, stopErr)
+	tickets := ts.service.BackfillTickets()
+	common.AssertEqual(t, 1, len(tickets))
+	common.AssertEqual(t, true, tickets[0].Stopped)
+	common.AssertEqual(t, "player-1", tickets[0].Players[0].PlayerID)
+}
+
+func TestService_UpdateGameSession(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+	session := ts.startGameSession(t, model.GameSession{MaximumPlayerSessionCount: 4})
+	session.MatchmakerData = `{"matchId":"match-2"}`
+
+	// WHEN
+	err := ts.service.UpdateGameSession(testProcessID, model.UpdateGameSession{
+		GameSession:      session,
+		BackfillTicketID: "ticket-1",
+	}.WithReason(model.MatchmakingDataUpdated))
+
+	// THEN
+	common.AssertEqual(t, nil, err)
+	select {
+	case update := <-ts.updateSessions:
+		common.AssertEqual(t, session.MatchmakerData, update.GameSession.MatchmakerData)
+		common.AssertEqual(t, "ticket-1", update.BackfillTicketID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for OnUpdateGameSession")
+	}
+}
+
+func TestService_TerminateProcess(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+	terminationTime := time.Now().Add(time.Minute)
+
+	// WHEN
+	if err := ts.service.TerminateProcess(testProcessID, terminationTime); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ts.terminations:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for OnProcessTerminate")
+	}
+	endingErr := ts.client.ProcessEnding()
+
+	// THEN
+	common.AssertEqual(t, nil, endingErr)
+	termination, err := ts.client.GetTerminationTime()
+	common.AssertEqual(t, nil, err)
+	common.AssertEqual(t, terminationTime.Unix(), termination)
+	process, _ := ts.service.Process(testProcessID)
+	common.AssertEqual(t, true, process.Ended)
+	common.AssertEqual(t, false, process.Active)
+}
+
+func TestService_RefreshConnection(t *testing.T) {
+	// GIVEN
+	ts := setupTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// WHEN
+	if err := ts.service.RefreshConnection(testProcessID); err != nil {
+		t.Fatal(err)
+	}
+	err := ts.service.WaitForProcess(ctx, testProcessID, func(p servertest.ProcessInfo) bool {
+		return p.Connections == 2 && p.Connected
+	})
+
+	// THEN
+	common.AssertEqual(t, nil, err)
+	if _, err := ts.client.GetComputeCertificate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -50,6 +50,11 @@ func ValidateServerParameters(input ServerParameters, computeType string) error
 	if !isUsingAuthToken && !isUsingSigV4Auth {
 		return common.NewGameLiftError(common.ValidationException, "", fmt.Sprintf("Failed to provide a valid authorization strategy: Either %s are required", authOptions))
 	}
+	if input.ReconnectPolicy != nil {
+		if err := ValidateReconnectPolicy(*input.ReconnectPolicy); err != nil {
+			return err
+		}
+	}
 
 	if isUsingAuthToken {
 		return validateSpecificServerParameters(input, []property{WebSocketUrl, ProcessId, FleetId, HostId})
@@ -108,6 +113,55 @@ func ValidateProcessParameters(input ProcessParameters) error {
 	if input.Port < common.PortMin || input.Port > common.PortMax {
 		return common.NewGameLiftError(common.ValidationException, "", fmt.Sprintf("Port must be between %d and %d", common.PortMin, common.PortMax))
 	}
+	if input.Drain != nil {
+		if err := ValidateDrainParameters(*input.Drain); err != nil {
+			return err
+		}
+	}
+	if input.Reconciliation != nil && input.Reconciliation.Interval < 0 {
+		return common.NewGameLiftError(common.ValidationException, "", "Reconciliation interval must not be negative")
+	}
+	if input.PlayerSessionPolicy != nil {
+		if err := ValidatePlayerSessionPolicyParameters(*input.PlayerSessionPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateDrainParameters(input DrainParameters) error {
+	if input.SafetyMargin < 0 || input.Timeout < 0 || input.PollInterval < 0 {
+		return common.NewGameLiftError(common.ValidationException, "", "Drain durations must not be negative")
+	}
+	return nil
+}
+
+func ValidatePlayerSessionPolicyParameters(input PlayerSessionPolicyParameters) error {
+	if input.DenyAt < 0 || input.AcceptAt < 0 {
+		return common.NewGameLiftError(common.ValidationException, "", "Player session policy thresholds must not be negative")
+	}
+	if input.DenyAt > 0 && input.AcceptAt >= input.DenyAt {
+		return common.NewGameLiftError(common.ValidationException, "", "Player session policy AcceptAt must be lower than DenyAt")
+	}
+	if input.LockAfter < 0 || input.PollInterval < 0 {
+		return common.NewGameLiftError(common.ValidationException, "", "Player session policy durations must not be negative")
+	}
+	return nil
+}
+
+func ValidateReconnectPolicy(input ReconnectPolicy) error {
+	if input.BaseDelay < 0 || input.MaxDelay < 0 {
+		return common.NewGameLiftError(common.ValidationException, "", "Reconnect policy delays must not be negative")
+	}
+	if input.MaxDelay > 0 && input.MaxDelay < input.BaseDelay {
+		return common.NewGameLiftError(common.ValidationException, "", "Reconnect policy MaxDelay must not be lower than BaseDelay")
+	}
+	if input.MaxAttempts < UnlimitedReconnectAttempts {
+		return common.NewGameLiftError(common.ValidationException, "", "Reconnect policy MaxAttempts must not be negative")
+	}
+	if input.Jitter < ReconnectJitterFull || input.Jitter > ReconnectJitterNone {
+		return common.NewGameLiftError(common.ValidationException, "", "Reconnect policy Jitter is not supported")
+	}
 	return nil
 }
 
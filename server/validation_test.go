@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/common"
 	"github.com/amazon-gamelift/amazon-gamelift-servers-go-server-sdk/v5/model"
@@ -93,6 +94,15 @@ func TestValidateServerParameters_InvalidParams(t *testing.T) {
 	common.AssertContains(t, err.Error(), "ProcessID is required.")
 	input.ProcessID = "test-process-id"
 
+	// WHEN - reconnect max delay lower than base delay
+	input.ReconnectPolicy = &ReconnectPolicy{BaseDelay: time.Minute, MaxDelay: time.Second}
+	err = ValidateServerParameters(input, computeType)
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+	common.AssertContains(t, err.Error(), "Reconnect policy MaxDelay must not be lower than BaseDelay")
+	input.ReconnectPolicy = nil
+
 	// WHEN - host id invalid
 	// empty w/ auth token
 	input.HostID = ""
@@ -212,6 +222,30 @@ func TestValidateProcessParameters_InvalidParams(t *testing.T) {
 		t.Fatalf("Expected error, got nil")
 	}
 	common.AssertContains(t, err.Error(), errMessage)
+	// WHEN - drain duration negative
+	input.Port = common.PortMin + 1000
+	input.Drain = &DrainParameters{SafetyMargin: -time.Second}
+	err = ValidateProcessParameters(input)
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+	common.AssertContains(t, err.Error(), "Drain durations must not be negative")
+	// WHEN - reconciliation interval negative
+	input.Drain = nil
+	input.Reconciliation = &ReconciliationParameters{Interval: -time.Second}
+	err = ValidateProcessParameters(input)
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+	common.AssertContains(t, err.Error(), "Reconciliation interval must not be negative")
+	// WHEN - player session policy accepts at the deny threshold
+	input.Reconciliation = nil
+	input.PlayerSessionPolicy = &PlayerSessionPolicyParameters{DenyAt: 10, AcceptAt: 10}
+	err = ValidateProcessParameters(input)
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+	common.AssertContains(t, err.Error(), "Player session policy AcceptAt must be lower than DenyAt")
 }
 
 func TestValidatePlayerSessionCreationPolicy(t *testing.T) {